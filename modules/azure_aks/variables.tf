@@ -276,8 +276,9 @@ variable "cluster_egress_type" {
 }
 
 variable "aks_cluster_private_dns_zone_id" {
-  type    = string
-  default = ""
+  description = "Specify private DNS zone resource ID for AKS private cluster to use."
+  type        = string
+  default     = ""
 }
 
 variable "aks_cluster_run_command_enabled" {
@@ -287,8 +288,9 @@ variable "aks_cluster_run_command_enabled" {
 }
 
 variable "node_resource_group_name" {
-  type    = string
-  default = ""
+  description = "Resource group name for the AKS cluster resources."
+  type        = string
+  default     = ""
 }
 
 # Community Contribution
@@ -172,6 +172,7 @@ output "rwx_filestore_config" {
       "exportRule" : element(tolist(module.vnet.address_space), 0),
     }
   }) : null
+  sensitive = true
 }
 
 output "cluster_node_pool_mode" {
@@ -0,0 +1,47 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// configvars reports the variables of variables.tf whose documented default in docs/CONFIG-VARS.md differs,
+// that are not documented, and the documented variables that no longer exist. Run from the test directory:
+//
+//	go run ./cmd/configvars
+//
+// It exits with status 1 if there are findings.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"test/configvars"
+	"test/tfconfig"
+)
+
+func main() {
+	root := flag.String("root", "..", "Path to the terraform root module")
+	flag.Parse()
+
+	module, err := tfconfig.LoadModule(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading the variables:", err)
+		os.Exit(2)
+	}
+	var rows []configvars.Row
+	for _, path := range configvars.DocPaths {
+		docRows, err := configvars.ParseMarkdown(filepath.Join(*root, path))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading the documentation:", err)
+			os.Exit(2)
+		}
+		rows = append(rows, docRows...)
+	}
+
+	findings := configvars.Compare(rows, module.Variables)
+	for _, finding := range findings {
+		fmt.Println(finding)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
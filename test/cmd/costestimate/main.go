@@ -0,0 +1,68 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// costestimate prints the estimated monthly cost of a planned deployment.
+//
+// Create the plan JSON with:
+//
+//	terraform plan -var-file=my.tfvars -out=my.tfplan
+//	terraform show -json my.tfplan > my.tfplan.json
+//
+// Then run from the test directory:
+//
+//	go run ./cmd/costestimate -plan my.tfplan.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"test/cost"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+func main() {
+	planPath := flag.String("plan", "", "Path to the JSON output of 'terraform show -json' for a plan file")
+	pricesPath := flag.String("prices", "cost/price_sheet.json", "Path to the price sheet JSON file")
+	budget := flag.Float64("budget", 0, "Exit with a non-zero code if the maximum monthly estimate exceeds this value")
+	flag.Parse()
+
+	if *planPath == "" {
+		fmt.Println("Error: -plan is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*planPath)
+	if err != nil {
+		fmt.Println("Error reading plan file:", err)
+		os.Exit(1)
+	}
+	plan, err := terraform.ParsePlanJSON(string(data))
+	if err != nil {
+		fmt.Println("Error parsing plan file:", err)
+		os.Exit(1)
+	}
+	prices, err := cost.LoadPriceSheet(*pricesPath)
+	if err != nil {
+		fmt.Println("Error loading price sheet:", err)
+		os.Exit(1)
+	}
+
+	estimate, err := cost.EstimatePlan(plan, prices)
+	if err != nil {
+		fmt.Println("Warning: some resources could not be priced:")
+		fmt.Println(err)
+	}
+	if err := estimate.Write(os.Stdout); err != nil {
+		fmt.Println("Error writing estimate:", err)
+		os.Exit(1)
+	}
+
+	if *budget > 0 && estimate.MaxMonthly > *budget {
+		fmt.Printf("Maximum monthly estimate %.2f %s exceeds the budget of %.2f %s\n",
+			estimate.MaxMonthly, estimate.Currency, *budget, estimate.Currency)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// providerschema regenerates the provider schema snapshot that the plan test tables are validated against.
+// It requires terraform and access to the provider registry. Run from the test directory:
+//
+//	go run ./cmd/providerschema
+//
+// Only the schemas of the azurerm, kubernetes and cloudinit providers are kept, without descriptions. Run it
+// after changing a provider version in versions.tf, and fix the test cases it warns about.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"test/providerschema"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func main() {
+	root := flag.String("root", "..", "Path to the terraform root module whose versions.tf pins the providers")
+	outPath := flag.String("out", "providerschema/provider_schema.json", "Path to write the snapshot to")
+	flag.Parse()
+
+	versions, err := os.ReadFile(filepath.Join(*root, "versions.tf"))
+	if err != nil {
+		fmt.Println("Error reading versions.tf:", err)
+		os.Exit(1)
+	}
+	// Initialize a copy of versions.tf only, so no backend or module of the root module is needed
+	dir, err := os.MkdirTemp("", "providerschema")
+	if err != nil {
+		fmt.Println("Error creating a temporary directory:", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), versions, 0644); err != nil {
+		fmt.Println("Error writing versions.tf:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Running 'terraform init'")
+	init := exec.Command("terraform", "-chdir="+dir, "init", "-backend=false", "-input=false")
+	init.Stdout, init.Stderr = os.Stdout, os.Stderr
+	if err := init.Run(); err != nil {
+		fmt.Println("Error initializing the providers:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Running 'terraform providers schema -json'")
+	out, err := exec.Command("terraform", "-chdir="+dir, "providers", "schema", "-json").Output()
+	if err != nil {
+		fmt.Println("Error reading the provider schemas:", err)
+		os.Exit(1)
+	}
+	schemas := &tfjson.ProviderSchemas{}
+	if err := json.Unmarshal(out, schemas); err != nil {
+		fmt.Println("Error parsing the provider schemas:", err)
+		os.Exit(1)
+	}
+
+	out, err = exec.Command("terraform", "-chdir="+dir, "version", "-json").Output()
+	if err != nil {
+		fmt.Println("Error reading the provider versions:", err)
+		os.Exit(1)
+	}
+	var version struct {
+		ProviderSelections map[string]string `json:"provider_selections"`
+	}
+	if err := json.Unmarshal(out, &version); err != nil {
+		fmt.Println("Error parsing the provider versions:", err)
+		os.Exit(1)
+	}
+
+	snapshot := providerschema.Snapshot{
+		Versions: make(map[string]string),
+		Schemas:  providerschema.Trim(schemas, providerschema.ValidatedProviders),
+	}
+	for source := range snapshot.Schemas.Schemas {
+		snapshot.Versions[source] = version.ProviderSelections[source]
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		fmt.Println("Error encoding snapshot:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*outPath, append(data, '\n'), 0644); err != nil {
+		fmt.Println("Error writing snapshot:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote the schemas of %d providers to %s\n", len(snapshot.Schemas.Schemas), *outPath)
+
+	// Report the test cases that a provider upgrade broke, such as queries of removed or renamed attributes
+	cases, err := providerschema.FindTableCases("defaultplan", "nondefaultplan", "moduleplan")
+	if err != nil {
+		fmt.Println("Error reading the plan test tables:", err)
+		os.Exit(1)
+	}
+	for _, finding := range providerschema.ValidateTableCases(&snapshot, cases) {
+		fmt.Println("Warning:", finding)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// runner runs the Go tests with the settings of the test profile, streams their output and writes the logs,
+// a JUnit report and a summary to the output directory. It is the entrypoint of the Terratest Docker image,
+// and can also be run from the test directory:
+//
+//	go run ./cmd/runner -p ./defaultplan -r TestPlanDefaults -v
+//
+// The secret values that the tests find are recorded by hash, and the run fails if one of them, or of the Azure
+// client secrets, appears in the output.
+//
+// The exit code is 0 if all tests passed, 1 if a test failed or a secret was found in the output, 2 for invalid arguments and 3 if a package did
+// not build or the tests could not be run.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"test/helpers"
+	"test/report"
+)
+
+const (
+	exitPassed = 0
+	exitFailed = 1
+	exitUsage  = 2
+	exitError  = 3
+)
+
+// secretVars are the environment variables whose values must not appear in the test output.
+var secretVars = []string{
+	"TF_VAR_client_secret",
+	"ARM_CLIENT_SECRET",
+	"AZURE_CLIENT_SECRET",
+	"ARM_CLIENT_CERTIFICATE_PASSWORD",
+}
+
+// minSecretLength is the length below which the values of secretVars are not scanned for, like the helpers.
+const minSecretLength = 6
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	var pkg, pattern string
+	var verbose bool
+	flags := flag.NewFlagSet("runner", flag.ContinueOnError)
+	flags.StringVar(&pkg, "package", "./...", "The package to test")
+	flags.StringVar(&pkg, "p", "./...", "Shorthand for -package")
+	flags.StringVar(&pattern, "run", "", "The name of the tests to run. Default is the tests of the suites in the test profile")
+	flags.StringVar(&pattern, "r", "", "Shorthand for -run")
+	flags.BoolVar(&verbose, "verbose", false, "Show the output of all tests, not only of the failed ones")
+	flags.BoolVar(&verbose, "v", false, "Shorthand for -verbose")
+	configPath := flags.String("config", valueOr(os.Getenv(helpers.ConfigFileEnvVar), "config.yaml"), "Path to the test configuration file")
+	profile := flags.String("profile", os.Getenv(helpers.ProfileEnvVar), "Test profile, defaults to "+helpers.DefaultProfile)
+	outDir := flags.String("output", "testoutput", "Directory for the logs, the JUnit report and the summary")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitPassed
+		}
+		return exitUsage
+	}
+
+	// The project is mounted to /viya4-iac-azure in the container, and the tests run in its test directory
+	if _, err := os.Stat("../main.tf"); err != nil {
+		fmt.Println("Error: The viya4-iac-azure project is not in the parent directory, mount it to /viya4-iac-azure")
+		return exitUsage
+	}
+	setupContainerUser()
+	resolveAzureCredential()
+
+	config, err := helpers.LoadTestConfig(*configPath, *profile, os.Getenv)
+	if err != nil {
+		fmt.Println("Error loading the test configuration:", err)
+		return exitUsage
+	}
+	fmt.Printf("Effective test configuration:\n%s\n", config)
+	if pattern == "" {
+		pattern = config.RunPattern()
+	}
+
+	args := []string{"test", "-json", pkg, "-run", pattern, "-timeout", config.Timeout}
+	if config.Parallel > 0 {
+		args = append(args, "-parallel", strconv.Itoa(config.Parallel))
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		fmt.Println("Error creating the output directory:", err)
+		return exitError
+	}
+	hashesFile, err := filepath.Abs(filepath.Join(*outDir, "secret_hashes"))
+	if err != nil {
+		fmt.Println("Error creating the secret hashes file:", err)
+		return exitError
+	}
+	os.Remove(hashesFile)
+	os.Setenv(helpers.SecretHashesFileEnvVar, hashesFile)
+
+	results, err := runTests(args, verbose, *outDir)
+	if err != nil {
+		fmt.Println("Error running the tests:", err)
+		return exitError
+	}
+	if err := writeResults(results, *outDir); err != nil {
+		fmt.Println("Error writing the test results:", err)
+		return exitError
+	}
+	leaks, err := scanSecrets(hashesFile, *outDir)
+	if err != nil {
+		fmt.Println("Error scanning the test output for secrets:", err)
+		return exitError
+	}
+	if len(leaks) > 0 {
+		fmt.Println("Error: secret values found in the test output:")
+		for _, leak := range leaks {
+			fmt.Println("  " + leak.String())
+		}
+	}
+
+	switch {
+	case len(results.Packages) == 0 || len(results.FailedPackages()) > 0:
+		return exitError
+	case len(results.Failed()) > 0 || len(leaks) > 0:
+		return exitFailed
+	}
+	return exitPassed
+}
+
+// runTests runs go test with the arguments and streams its output to stdout and test_output.log, and its JSON
+// events to test_output.json, in outDir. The exit code of go test is not an error, the report has the results.
+func runTests(args []string, verbose bool, outDir string) (*report.Report, error) {
+	logFile, err := os.Create(filepath.Join(outDir, "test_output.log"))
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+	jsonFile, err := os.Create(filepath.Join(outDir, "test_output.json"))
+	if err != nil {
+		return nil, err
+	}
+	defer jsonFile.Close()
+
+	out := io.MultiWriter(os.Stdout, logFile)
+	fmt.Fprintf(out, "Running 'go %s'\n", strings.Join(args, " "))
+
+	reader, writer := io.Pipe()
+	cmd := exec.Command("go", args...)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		writer.Close()
+		waitErr <- err
+	}()
+
+	results := report.New(verbose)
+	scanner := bufio.NewScanner(reader)
+	// Terraform writes long lines, e.g. for plan output
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fmt.Fprintln(jsonFile, line)
+		for _, shown := range results.AddLine(line) {
+			io.WriteString(out, shown)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		// Keep draining so go test is not blocked on a full pipe
+		io.Copy(io.Discard, reader)
+		<-waitErr
+		return nil, err
+	}
+
+	var exitErr *exec.ExitError
+	if err := <-waitErr; err != nil && !errors.As(err, &exitErr) {
+		return nil, err
+	}
+	return results, nil
+}
+
+// writeResults writes the per test logs, the JUnit report and the summary to outDir, and the summary to stdout.
+func writeResults(results *report.Report, outDir string) error {
+	if err := results.WriteTestLogs(filepath.Join(outDir, "logs")); err != nil {
+		return err
+	}
+
+	junitFile, err := os.Create(filepath.Join(outDir, "report.xml"))
+	if err != nil {
+		return err
+	}
+	defer junitFile.Close()
+	if err := results.WriteJUnit(junitFile); err != nil {
+		return err
+	}
+
+	summaryFile, err := os.Create(filepath.Join(outDir, "summary.log"))
+	if err != nil {
+		return err
+	}
+	defer summaryFile.Close()
+	fmt.Println()
+	return results.WriteSummary(io.MultiWriter(os.Stdout, summaryFile))
+}
+
+// scanSecrets returns the lines of the logs and reports in outDir that contain a secret recorded in hashesFile
+// or the value of one of secretVars.
+func scanSecrets(hashesFile string, outDir string) ([]report.Leak, error) {
+	hashes, err := report.LoadSecretHashes(hashesFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range secretVars {
+		if value := os.Getenv(name); len(value) >= minSecretLength {
+			hashes.Add(value)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	var paths []string
+	for _, name := range []string{"test_output.log", "test_output.json", "logs", "report.xml", "summary.log"} {
+		paths = append(paths, filepath.Join(outDir, name))
+	}
+	return hashes.Scan(paths...)
+}
+
+// resolveAzureCredential resolves the Azure authentication method with the chain of the tests and sets its
+// environment for all the tests, so the plan and apply tests use the same method. If no method is configured,
+// the tests that need Azure fail with the diagnostic.
+func resolveAzureCredential() {
+	credential, err := helpers.ResolveCredentialChain(helpers.DefaultCredentialChain, os.Getenv)
+	if err == nil {
+		err = credential.Apply(os.Setenv, os.Unsetenv)
+	}
+	if err != nil {
+		fmt.Println("Warning: Error resolving Azure credentials:", err)
+		return
+	}
+	fmt.Printf("Authenticating to Azure with %s\n", credential)
+}
+
+// setupContainerUser adds the user and group the container runs as to /etc/passwd and /etc/group, since ssh
+// and git need a user name. Nothing is changed outside a container or if they exist.
+func setupContainerUser() {
+	uid := strconv.Itoa(os.Getuid())
+	gid := strconv.Itoa(os.Getgid())
+	if _, err := user.LookupId(uid); err != nil {
+		appendLine("/etc/passwd", fmt.Sprintf("viya4-iac-azure:*:%s:%s:,,,:/viya4-iac-azure:/bin/bash", uid, gid))
+	}
+	if _, err := user.LookupGroupId(gid); err != nil {
+		appendLine("/etc/group", fmt.Sprintf("viya4-iac-azure:*:%s:", gid))
+	}
+}
+
+func appendLine(path string, line string) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		fmt.Printf("Warning: unable to add the container user to %s: %s\n", path, err)
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, line)
+}
+
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
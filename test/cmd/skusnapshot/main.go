@@ -0,0 +1,78 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// skusnapshot regenerates the offline SKU capability snapshot used by the plan tests.
+// It requires a logged in Azure CLI. Run from the test directory:
+//
+//	go run ./cmd/skusnapshot -locations eastus,eastus2,westus -sizes Standard_D16s_v5
+//
+// Only the managed disk types, the VM sizes already in the snapshot and the VM sizes of -sizes are kept. The
+// snapshot is also the SKU catalog of the vCPU quota check, so the vCPUs and the zones of a VM size always
+// come from the same output.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"test/sku"
+)
+
+func main() {
+	locations := flag.String("locations", "eastus,eastus2,westus", "Comma separated list of locations to include")
+	sizes := flag.String("sizes", "", "Comma separated list of VM sizes to add to the ones of the snapshot")
+	outPath := flag.String("out", "sku/vm_skus_snapshot.json", "Path to write the snapshot to")
+	flag.Parse()
+
+	catalog, err := sku.LoadCatalog(*outPath)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Println("Error loading SKU snapshot:", err)
+		os.Exit(1)
+	}
+	kept := make(map[string]bool)
+	if catalog != nil {
+		for size := range catalog.VirtualMachines {
+			kept[size] = true
+		}
+	}
+	for _, size := range strings.Split(*sizes, ",") {
+		if size = strings.TrimSpace(size); size != "" {
+			kept[size] = true
+		}
+	}
+
+	snapshot := sku.Snapshot{}
+	for _, location := range strings.Split(*locations, ",") {
+		location = strings.TrimSpace(location)
+		fmt.Printf("Running 'az vm list-skus --all --location %s'\n", location)
+		out, err := exec.Command("az", "vm", "list-skus", "--all", "--location", location, "-o", "json").Output()
+		if err != nil {
+			fmt.Printf("Error listing SKUs for %s: %s\n", location, err)
+			os.Exit(1)
+		}
+		var skus sku.Snapshot
+		if err := json.Unmarshal(out, &skus); err != nil {
+			fmt.Printf("Error parsing SKUs for %s: %s\n", location, err)
+			os.Exit(1)
+		}
+		for _, resourceSku := range skus {
+			if resourceSku.ResourceType == "disks" || (resourceSku.ResourceType == "virtualMachines" && kept[resourceSku.Name]) {
+				snapshot = append(snapshot, resourceSku)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		fmt.Println("Error encoding snapshot:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*outPath, append(data, '\n'), 0644); err != nil {
+		fmt.Println("Error writing snapshot:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d SKUs to %s\n", len(snapshot), *outPath)
+}
@@ -0,0 +1,18 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// tablelint checks the helpers.TestCase, helpers.TupleTestCase and helpers.ApplyTestCase tables of the tests.
+// Run it from the test directory with go vet:
+//
+//	go build -o /tmp/tablelint ./cmd/tablelint && go vet -vettool=/tmp/tablelint ./...
+package main
+
+import (
+	"test/tablelint"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(tablelint.Analyzer)
+}
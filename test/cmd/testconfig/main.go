@@ -0,0 +1,51 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// testconfig prints the effective test configuration, the profile in TEST_PROFILE of config.yaml with the
+// environment overrides applied. Run from the test directory:
+//
+//	go run ./cmd/testconfig
+//
+// Print a single go test setting with:
+//
+//	go run ./cmd/testconfig -get timeout
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"test/helpers"
+)
+
+func main() {
+	defaultPath := os.Getenv(helpers.ConfigFileEnvVar)
+	if defaultPath == "" {
+		defaultPath = "config.yaml"
+	}
+	path := flag.String("config", defaultPath, "Path to the test configuration file")
+	profile := flag.String("profile", os.Getenv(helpers.ProfileEnvVar), "Test profile, defaults to "+helpers.DefaultProfile)
+	get := flag.String("get", "", "Print only this go test setting: run, parallel or timeout")
+	flag.Parse()
+
+	config, err := helpers.LoadTestConfig(*path, *profile, os.Getenv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading the test configuration:", err)
+		os.Exit(1)
+	}
+
+	switch *get {
+	case "":
+		fmt.Print(config)
+	case "run":
+		fmt.Println(config.RunPattern())
+	case "parallel":
+		fmt.Println(strconv.Itoa(config.Parallel))
+	case "timeout":
+		fmt.Println(config.Timeout)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown setting %s, use run, parallel or timeout\n", *get)
+		os.Exit(2)
+	}
+}
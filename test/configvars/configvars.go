@@ -0,0 +1,315 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package configvars checks the variables documented in the tables of docs/CONFIG-VARS.md against the variable
+// blocks of variables.tf.
+package configvars
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"test/tfconfig"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// DocPaths are the documents of the variables, relative to the root module.
+var DocPaths = []string{"docs/CONFIG-VARS.md", "docs/community/community_config_vars.md"}
+
+// ObjectSections maps the sections whose tables document the attributes of the objects of a variable to the
+// variable whose default object holds their defaults. The rows of sections mapped to an empty name, such as the
+// node pool attributes of the community variables, have no default in variables.tf and are not checked.
+var ObjectSections = map[string]string{
+	"Postgres Servers":                   "postgres_server_defaults",
+	"Spot Nodes":                         "",
+	"OS Disk Type and Kubelet Disk Type": "",
+}
+
+// Undocumented are the variables of variables.tf that are not documented yet. Remove a variable when adding it
+// to the documentation.
+var Undocumented = []string{
+	"aks_cluster_enable_host_encryption",
+	"aks_dns_service_ip",
+	"aks_node_disk_encryption_set_id",
+	"aks_pod_cidr",
+	"aks_service_cidr",
+	"cluster_node_pool_mode",
+	"create_aks_azure_monitor",
+	"egress_public_ip_name",
+	"enable_vm_host_encryption",
+	"iac_tooling",
+	"jump_vm_zone",
+	"log_analytics_solution_name",
+	"log_analytics_solution_product",
+	"log_analytics_solution_promotion_code",
+	"log_analytics_solution_publisher",
+	"log_analytics_workspace_sku",
+	"log_retention_in_days",
+	"metric_category",
+	"node_pools",
+	"os_disk_storage_account_type",
+	"postgres_servers",
+	"resource_log_category",
+	"resource_provider_registrations",
+	"resource_providers_to_register",
+	"vm_disk_encryption_set_id",
+}
+
+// Row is a row of a documentation table with a Default column.
+type Row struct {
+	Path    string
+	Name    string
+	Default string
+	Section string
+	Line    int
+}
+
+func (r Row) String() string {
+	return fmt.Sprintf("%s:%d", r.Path, r.Line)
+}
+
+// ParseMarkdown returns the rows of the tables of the file that have a Name and a Default column. Tables
+// without a Default column, such as the one of the node pool attributes, are skipped.
+func ParseMarkdown(path string) ([]Row, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rows []Row
+	var section string
+	var columns map[string]int
+	inCode := false
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "```"):
+			inCode = !inCode
+		case inCode:
+		case strings.HasPrefix(line, "#"):
+			section = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			columns = nil
+		case !strings.HasPrefix(line, "|"):
+			columns = nil
+		case columns == nil:
+			columns = make(map[string]int)
+			for i, cell := range splitRow(line) {
+				columns[strings.ToLower(cell)] = i
+			}
+		case strings.Trim(line, "|:- ") == "":
+			// The separator line of the table
+		default:
+			nameColumn, hasName := columns["name"]
+			defaultColumn, hasDefault := columns["default"]
+			cells := splitRow(line)
+			if !hasName || !hasDefault || len(cells) <= nameColumn {
+				continue
+			}
+			row := Row{Path: path, Name: cleanName(cells[nameColumn]), Section: section, Line: number}
+			if defaultColumn < len(cells) {
+				row.Default = cells[defaultColumn]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, scanner.Err()
+}
+
+// splitRow returns the cells of a table row. As in GitHub tables, only escaped pipes do not end a cell.
+func splitRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(strings.ReplaceAll(line, `\|`, "\x00"), "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(strings.ReplaceAll(cell, "\x00", "|"))
+	}
+	return cells
+}
+
+// cleanName returns the variable name of a Name cell, e.g. availability_zones for "`availability_zones` (Optional)".
+func cleanName(cell string) string {
+	name, _, _ := strings.Cut(strings.ReplaceAll(cell, "`", ""), " ")
+	return name
+}
+
+// DocumentedDefault returns the value of a Default cell, and false if the cell describes the default in words
+// or with an expression, e.g. value of `resource_group_name`. An empty cell is a null value.
+func DocumentedDefault(cell string) (cty.Value, bool) {
+	cell = strings.TrimSpace(strings.Trim(strings.TrimSpace(cell), "`"))
+	if cell == "" {
+		return cty.NullVal(cty.DynamicPseudoType), true
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(cell), "CONFIG-VARS.md", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+// Equal returns whether two values have the same JSON encoding, so that a documented list equals a tuple and a
+// documented object equals a map.
+func Equal(a cty.Value, b cty.Value) bool {
+	aJson, aErr := normalize(a)
+	bJson, bErr := normalize(b)
+	return aErr == nil && bErr == nil && reflect.DeepEqual(aJson, bJson)
+}
+
+func normalize(value cty.Value) (interface{}, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+	data, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// PlanValue returns a value as the jsonpath queries of the test cases print it: strings without quotes and
+// other values as JSON.
+func PlanValue(value cty.Value) string {
+	if value.IsNull() {
+		return "<nil>"
+	}
+	if value.Type() == cty.String {
+		return value.AsString()
+	}
+	data, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return value.GoString()
+	}
+	return string(data)
+}
+
+// Format returns a value as it is written in CONFIG-VARS.md.
+func Format(value cty.Value) string {
+	if value.IsNull() {
+		return "null"
+	}
+	data, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return value.GoString()
+	}
+	return string(data)
+}
+
+// Compare returns the documented defaults that differ from the defaults of the variables, the variables that
+// are documented more than once, the documented variables that do not exist and the variables that are not
+// documented, except the Undocumented ones. The rows of the ObjectSections are compared with the attributes of
+// the default object of their variable.
+func Compare(rows []Row, variables map[string]*tfconfig.Variable) []string {
+	var findings []string
+	documented := make(map[string]Row)
+	attributes := make(map[string]map[string]Row)
+	for _, row := range rows {
+		variableName, isObject := ObjectSections[row.Section]
+		switch {
+		case isObject && variableName == "":
+		case isObject:
+			if attributes[variableName] == nil {
+				attributes[variableName] = make(map[string]Row)
+			}
+			findings = append(findings, compareAttribute(row, variables[variableName], attributes[variableName])...)
+		default:
+			findings = append(findings, compareVariable(row, variables, documented)...)
+		}
+	}
+
+	for _, name := range sortedNames(variables) {
+		_, isDocumented := documented[name]
+		_, isObject := attributes[name]
+		switch {
+		case (isDocumented || isObject) && containsString(Undocumented, name):
+			findings = append(findings, fmt.Sprintf("%s: %s is documented, remove it from configvars.Undocumented", position(variables[name]), name))
+		case !isDocumented && !isObject && !containsString(Undocumented, name):
+			findings = append(findings, fmt.Sprintf("%s: %s is not documented", position(variables[name]), name))
+		}
+	}
+	for _, name := range Undocumented {
+		if _, ok := variables[name]; !ok {
+			findings = append(findings, fmt.Sprintf("%s is not a variable, remove it from configvars.Undocumented", name))
+		}
+	}
+	for variableName, documentedAttributes := range attributes {
+		variable := variables[variableName]
+		if variable == nil || !variable.Default.Type().IsObjectType() {
+			continue
+		}
+		for name := range variable.Default.Type().AttributeTypes() {
+			if _, ok := documentedAttributes[name]; !ok {
+				findings = append(findings, fmt.Sprintf("%s: the %s attribute of %s is not documented", position(variable), name, variableName))
+			}
+		}
+	}
+	sort.Strings(findings)
+	return findings
+}
+
+func compareVariable(row Row, variables map[string]*tfconfig.Variable, documented map[string]Row) []string {
+	if first, ok := documented[row.Name]; ok {
+		return []string{fmt.Sprintf("%s: %s is documented more than once, first at %s", row, row.Name, first)}
+	}
+	documented[row.Name] = row
+	variable, ok := variables[row.Name]
+	if !ok {
+		return []string{fmt.Sprintf("%s: %s is documented, but it is not a variable", row, row.Name)}
+	}
+	return compareDefault(row, variable.Default)
+}
+
+func compareAttribute(row Row, variable *tfconfig.Variable, documented map[string]Row) []string {
+	if first, ok := documented[row.Name]; ok {
+		return []string{fmt.Sprintf("%s: %s is documented more than once, first at %s", row, row.Name, first)}
+	}
+	documented[row.Name] = row
+	if variable == nil || !variable.Default.Type().IsObjectType() || !variable.Default.Type().HasAttribute(row.Name) {
+		return []string{fmt.Sprintf("%s: %s is documented in %s, but it is not an attribute of the default object",
+			row, row.Name, row.Section)}
+	}
+	return compareDefault(row, variable.Default.GetAttr(row.Name))
+}
+
+func compareDefault(row Row, actual cty.Value) []string {
+	documented, ok := DocumentedDefault(row.Default)
+	if !ok || Equal(documented, actual) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: the documented default of %s is %s, but it is %s", row, row.Name, Format(documented), Format(actual))}
+}
+
+func position(variable *tfconfig.Variable) string {
+	return fmt.Sprintf("%s:%d", variable.Range.Filename, variable.Range.Start.Line)
+}
+
+func sortedNames(variables map[string]*tfconfig.Variable) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
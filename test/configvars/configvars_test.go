@@ -0,0 +1,198 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package configvars
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"test/tfconfig"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const testMarkdown = "# Configuration Variables\n" +
+	"\n" +
+	"## General\n" +
+	"\n" +
+	"| Name | Description | Type | Default | Notes |\n" +
+	"| :--- | :--- | :--- | :--- | :--- |\n" +
+	"| prefix | A prefix used in the name for all cloud resources | string | | |\n" +
+	"| location | The Azure region | string | \"eastus\" | |\n" +
+	"| `kubernetes_version` | The AKS version | string | \"1.32\" | |\n" +
+	"| tags | Map of tags | map | { project_name = \"sasviya4\" } | |\n" +
+	"| zones | The zones \\| if any | list | [\"1\"] | |\n" +
+	"| location | The Azure region, again | string | \"eastus\" | |\n" +
+	"| nfs_vm_zone | Removed | string | null | |\n" +
+	"| rg_name | The resource group | string | value of `prefix` | |\n" +
+	"\n" +
+	"```hcl\n" +
+	"| ignored | in code | string | \"x\" | |\n" +
+	"```\n" +
+	"\n" +
+	"## Postgres Servers\n" +
+	"\n" +
+	"| Name | Description | Type | Default | Notes |\n" +
+	"| :--- | :--- | :--- | :--- | :--- |\n" +
+	"| sku_name | The SKU | string | \"GP_Standard_D4ds_v5\" | |\n" +
+	"| storage_mb | The storage | number | 65536 | |\n" +
+	"\n" +
+	"## Node Pools\n" +
+	"\n" +
+	"| Name | Description | Type | Notes |\n" +
+	"| :--- | :--- | :--- | :--- |\n" +
+	"| machine_type | The VM type | string | |\n"
+
+const testVariables = `variable "prefix" {
+  type = string
+}
+
+variable "location" {
+  type    = string
+  default = "eastus"
+}
+
+variable "kubernetes_version" {
+  default = "1.33"
+}
+
+variable "tags" {
+  type    = map(any)
+  default = {}
+}
+
+variable "zones" {
+  default = ["1"]
+}
+
+variable "rg_name" {
+  default = null
+}
+
+variable "jump_vm_zone" {
+  default = null
+}
+
+variable "aks_pod_cidr" {
+  default = "192.168.0.0/16"
+}
+
+variable "postgres_server_defaults" {
+  default = {
+    sku_name   = "GP_Standard_D4s_v3"
+    storage_mb = 65536
+    version    = "15"
+  }
+}
+`
+
+func writeTestFiles(t *testing.T) ([]Row, map[string]*tfconfig.Variable) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CONFIG-VARS.md")
+	require.NoError(t, os.WriteFile(path, []byte(testMarkdown), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "variables.tf"), []byte(testVariables), 0644))
+	rows, err := ParseMarkdown(path)
+	require.NoError(t, err)
+	module, err := tfconfig.LoadModule(dir)
+	require.NoError(t, err)
+	return rows, module.Variables
+}
+
+// TestParseMarkdown verifies that the rows of the tables with a Default column are read, outside of code blocks.
+func TestParseMarkdown(t *testing.T) {
+	rows, _ := writeTestFiles(t)
+
+	var names []string
+	for _, row := range rows {
+		names = append(names, row.Section+"/"+row.Name)
+	}
+	assert.Equal(t, []string{
+		"General/prefix", "General/location", "General/kubernetes_version", "General/tags", "General/zones",
+		"General/location", "General/nfs_vm_zone", "General/rg_name",
+		"Postgres Servers/sku_name", "Postgres Servers/storage_mb",
+	}, names)
+	assert.Equal(t, 7, rows[0].Line)
+	assert.Equal(t, "", rows[0].Default)
+	assert.Equal(t, `["1"]`, rows[4].Default)
+}
+
+// TestDocumentedDefault verifies that literal defaults are parsed and defaults described in words are not.
+func TestDocumentedDefault(t *testing.T) {
+	tests := map[string]struct {
+		cell     string
+		expected cty.Value
+		ok       bool
+	}{
+		"empty":     {"", cty.NullVal(cty.DynamicPseudoType), true},
+		"null":      {"null", cty.NullVal(cty.DynamicPseudoType), true},
+		"string":    {`"eastus"`, cty.StringVal("eastus"), true},
+		"codeSpan":  {"`\"eastus\"`", cty.StringVal("eastus"), true},
+		"number":    {"100", cty.NumberIntVal(100), true},
+		"bool":      {"false", cty.False, true},
+		"list":      {`["1", "2"]`, cty.TupleVal([]cty.Value{cty.StringVal("1"), cty.StringVal("2")}), true},
+		"reference": {"value of `prefix`", cty.NilVal, false},
+		"words":     {"Determined by Azure", cty.NilVal, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, ok := DocumentedDefault(tc.cell)
+			require.Equal(t, tc.ok, ok)
+			if ok {
+				assert.True(t, Equal(tc.expected, value), "expected %s, got %s", Format(tc.expected), Format(value))
+			}
+		})
+	}
+	assert.True(t, Equal(cty.ListVal([]cty.Value{cty.StringVal("1")}), cty.TupleVal([]cty.Value{cty.StringVal("1")})))
+	assert.Equal(t, "eastus", PlanValue(cty.StringVal("eastus")))
+	assert.Equal(t, `["1"]`, PlanValue(cty.TupleVal([]cty.Value{cty.StringVal("1")})))
+	assert.Equal(t, "<nil>", PlanValue(cty.NullVal(cty.String)))
+}
+
+// TestCompare verifies that mismatched defaults, duplicate rows, removed variables, undocumented variables and
+// undocumented attributes are reported.
+func TestCompare(t *testing.T) {
+	rows, variables := writeTestFiles(t)
+
+	var findings, stale []string
+	for _, finding := range Compare(rows, variables) {
+		finding = strings.TrimPrefix(finding, filepath.Dir(rows[0].Path)+string(filepath.Separator))
+		if strings.HasSuffix(finding, "remove it from configvars.Undocumented") {
+			stale = append(stale, finding)
+		} else {
+			findings = append(findings, finding)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"CONFIG-VARS.md:9: the documented default of kubernetes_version is \"1.32\", but it is \"1.33\"",
+		"CONFIG-VARS.md:10: the documented default of tags is {\"project_name\":\"sasviya4\"}, but it is {}",
+		"CONFIG-VARS.md:12: location is documented more than once, first at " + rows[1].String(),
+		"CONFIG-VARS.md:13: nfs_vm_zone is documented, but it is not a variable",
+		"CONFIG-VARS.md:24: the documented default of sku_name is \"GP_Standard_D4ds_v5\", but it is \"GP_Standard_D4s_v3\"",
+		"variables.tf:35: the version attribute of postgres_server_defaults is not documented",
+	}, findings)
+	// Only the Undocumented variables of the test variables.tf exist
+	assert.Len(t, stale, len(Undocumented)-2)
+	assert.Contains(t, stale, "aks_service_cidr is not a variable, remove it from configvars.Undocumented")
+}
+
+// Verify that the defaults documented in docs/CONFIG-VARS.md and docs/community/community_config_vars.md match
+// the defaults of variables.tf, and that every variable is documented.
+func TestPlanConfigVarsMatchVariables(t *testing.T) {
+	module, err := tfconfig.LoadModule("../..")
+	require.NoError(t, err)
+
+	var rows []Row
+	for _, path := range DocPaths {
+		docRows, err := ParseMarkdown(filepath.Join("../..", path))
+		require.NoError(t, err)
+		rows = append(rows, docRows...)
+	}
+	for _, finding := range Compare(rows, module.Variables) {
+		assert.Fail(t, "The documentation does not match variables.tf", finding)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cost estimates the monthly cost of a planned deployment from a local price sheet.
+package cost
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"test/planvalues"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// LineItem is the estimated monthly cost of a single planned resource. Resources
+// that scale, such as AKS node pools, are priced at their minimum and maximum size.
+type LineItem struct {
+	Address    string
+	Type       string
+	Details    string
+	MinMonthly float64
+	MaxMonthly float64
+}
+
+// Estimate is the estimated monthly cost of a planned deployment.
+type Estimate struct {
+	Currency   string
+	LineItems  []LineItem
+	MinMonthly float64
+	MaxMonthly float64
+}
+
+// A pricer returns the line item for a planned resource of a given type.
+type pricer func(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error)
+
+var pricers = map[string]pricer{
+	"azurerm_linux_virtual_machine":        priceVirtualMachine,
+	"azurerm_kubernetes_cluster":           priceKubernetesCluster,
+	"azurerm_kubernetes_cluster_node_pool": priceNodePool,
+	"azurerm_managed_disk":                 priceManagedDisk,
+	"azurerm_netapp_pool":                  priceNetAppPool,
+	"azurerm_postgresql_flexible_server":   pricePostgresFlexibleServer,
+	"azurerm_container_registry":           priceContainerRegistry,
+	"azurerm_log_analytics_workspace":      priceLogAnalyticsWorkspace,
+}
+
+// EstimatePlan walks the planned resources and prices each one it knows about. All
+// missing prices are reported in the returned error.
+func EstimatePlan(plan *terraform.PlanStruct, prices *PriceSheet) (*Estimate, error) {
+	estimate := &Estimate{Currency: prices.Currency}
+	var errs []error
+
+	for address, resource := range plan.ResourcePlannedValuesMap {
+		priceFn, ok := pricers[resource.Type]
+		if !ok {
+			continue
+		}
+		item, err := priceFn(resource, prices)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", address, err))
+			continue
+		}
+		item.Address = address
+		item.Type = resource.Type
+		estimate.LineItems = append(estimate.LineItems, item)
+		estimate.MinMonthly += item.MinMonthly
+		estimate.MaxMonthly += item.MaxMonthly
+	}
+
+	sort.Slice(estimate.LineItems, func(i, j int) bool {
+		return estimate.LineItems[i].Address < estimate.LineItems[j].Address
+	})
+	return estimate, errors.Join(errs...)
+}
+
+// Write prints the estimate as a table with a total row.
+func (e *Estimate) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "RESOURCE\tDETAILS\tMIN/MONTH (%s)\tMAX/MONTH (%s)\n", e.Currency, e.Currency)
+	for _, item := range e.LineItems {
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%.2f\n", item.Address, item.Details, item.MinMonthly, item.MaxMonthly)
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%.2f\t%.2f\n", e.MinMonthly, e.MaxMonthly)
+	return tw.Flush()
+}
+
+func priceVirtualMachine(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	size := planvalues.String(resource.AttributeValues, "size")
+	hourly, ok := prices.VirtualMachines[size]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no virtual_machines price for size %q", size)
+	}
+	monthly := hourly * prices.HoursPerMonth
+	return LineItem{Details: size, MinMonthly: monthly, MaxMonthly: monthly}, nil
+}
+
+func priceKubernetesCluster(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	tier := planvalues.String(resource.AttributeValues, "sku_tier")
+	tierHourly, ok := prices.KubernetesClusters[tier]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no kubernetes_clusters price for sku_tier %q", tier)
+	}
+
+	pool := planvalues.FirstBlock(resource.AttributeValues, "default_node_pool")
+	size := planvalues.String(pool, "vm_size")
+	nodeHourly, ok := prices.VirtualMachines[size]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no virtual_machines price for default_node_pool vm_size %q", size)
+	}
+	minNodes, maxNodes := nodeCounts(pool)
+
+	return LineItem{
+		Details:    fmt.Sprintf("%s tier, %d-%d x %s", tier, minNodes, maxNodes, size),
+		MinMonthly: (tierHourly + nodeHourly*float64(minNodes)) * prices.HoursPerMonth,
+		MaxMonthly: (tierHourly + nodeHourly*float64(maxNodes)) * prices.HoursPerMonth,
+	}, nil
+}
+
+func priceNodePool(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	size := planvalues.String(resource.AttributeValues, "vm_size")
+	hourly, ok := prices.VirtualMachines[size]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no virtual_machines price for vm_size %q", size)
+	}
+	minNodes, maxNodes := nodeCounts(resource.AttributeValues)
+	return LineItem{
+		Details:    fmt.Sprintf("%d-%d x %s", minNodes, maxNodes, size),
+		MinMonthly: hourly * float64(minNodes) * prices.HoursPerMonth,
+		MaxMonthly: hourly * float64(maxNodes) * prices.HoursPerMonth,
+	}, nil
+}
+
+func priceManagedDisk(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	diskType := planvalues.String(resource.AttributeValues, "storage_account_type")
+	perGB, ok := prices.ManagedDisks[diskType]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no managed_disks price for storage_account_type %q", diskType)
+	}
+	sizeGB := planvalues.Number(resource.AttributeValues, "disk_size_gb")
+	monthly := perGB * sizeGB
+	return LineItem{Details: fmt.Sprintf("%.0f GB %s", sizeGB, diskType), MinMonthly: monthly, MaxMonthly: monthly}, nil
+}
+
+func priceNetAppPool(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	level := planvalues.String(resource.AttributeValues, "service_level")
+	perTB, ok := prices.NetAppPools[level]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no netapp_pools price for service_level %q", level)
+	}
+	sizeTB := planvalues.Number(resource.AttributeValues, "size_in_tb")
+	monthly := perTB * sizeTB
+	return LineItem{Details: fmt.Sprintf("%.0f TB %s", sizeTB, level), MinMonthly: monthly, MaxMonthly: monthly}, nil
+}
+
+func pricePostgresFlexibleServer(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	sku := planvalues.String(resource.AttributeValues, "sku_name")
+	hourly, ok := prices.PostgresFlexibleServers[sku]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no postgres_flexible_servers price for sku_name %q", sku)
+	}
+	storageGB := planvalues.Number(resource.AttributeValues, "storage_mb") / 1024
+	monthly := hourly*prices.HoursPerMonth + storageGB*prices.PostgresStorage
+	return LineItem{Details: fmt.Sprintf("%s, %.0f GB", sku, storageGB), MinMonthly: monthly, MaxMonthly: monthly}, nil
+}
+
+func priceContainerRegistry(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	sku := planvalues.String(resource.AttributeValues, "sku")
+	monthly, ok := prices.ContainerRegistries[sku]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no container_registries price for sku %q", sku)
+	}
+	replicas, _ := resource.AttributeValues["georeplications"].([]interface{})
+	monthly += float64(len(replicas)) * prices.ContainerRegistryGeoReplica
+	return LineItem{Details: fmt.Sprintf("%s, %d geo-replicas", sku, len(replicas)), MinMonthly: monthly, MaxMonthly: monthly}, nil
+}
+
+func priceLogAnalyticsWorkspace(resource *tfjson.StateResource, prices *PriceSheet) (LineItem, error) {
+	sku := planvalues.String(resource.AttributeValues, "sku")
+	perGB, ok := prices.LogAnalytics.PerGB[sku]
+	if !ok {
+		return LineItem{}, fmt.Errorf("no log_analytics price for sku %q", sku)
+	}
+	monthly := perGB * prices.LogAnalytics.DailyIngestionGB * prices.HoursPerMonth / 24
+	return LineItem{Details: fmt.Sprintf("%s, %.1f GB/day", sku, prices.LogAnalytics.DailyIngestionGB), MinMonthly: monthly, MaxMonthly: monthly}, nil
+}
+
+// nodeCounts returns the minimum and maximum node count of an autoscaled or static node pool.
+func nodeCounts(pool map[string]interface{}) (int, int) {
+	minCount, hasMin := pool["min_count"].(float64)
+	maxCount, hasMax := pool["max_count"].(float64)
+	if hasMin && hasMax {
+		return int(minCount), int(maxCount)
+	}
+	nodeCount := int(planvalues.Number(pool, "node_count"))
+	return nodeCount, nodeCount
+}
@@ -0,0 +1,121 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cost
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEstimatePlan verifies the pricing of each supported resource type against the bundled price sheet.
+func TestEstimatePlan(t *testing.T) {
+	prices, err := LoadPriceSheet("price_sheet.json")
+	require.NoError(t, err)
+
+	plan := &terraform.PlanStruct{
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{
+			"module.nfs[0].azurerm_linux_virtual_machine.vm": {
+				Type:            "azurerm_linux_virtual_machine",
+				AttributeValues: map[string]interface{}{"size": "Standard_D4s_v5"},
+			},
+			"module.aks.azurerm_kubernetes_cluster.aks": {
+				Type: "azurerm_kubernetes_cluster",
+				AttributeValues: map[string]interface{}{
+					"sku_tier": "Standard",
+					"default_node_pool": []interface{}{map[string]interface{}{
+						"vm_size":   "Standard_E8s_v5",
+						"min_count": float64(1),
+						"max_count": float64(5),
+					}},
+				},
+			},
+			"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.static_node_pool[0]": {
+				Type:            "azurerm_kubernetes_cluster_node_pool",
+				AttributeValues: map[string]interface{}{"vm_size": "Standard_E16ds_v5", "node_count": float64(2)},
+			},
+			"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]": {
+				Type:            "azurerm_managed_disk",
+				AttributeValues: map[string]interface{}{"storage_account_type": "Standard_LRS", "disk_size_gb": float64(128)},
+			},
+			"module.netapp[0].azurerm_netapp_pool.anf": {
+				Type:            "azurerm_netapp_pool",
+				AttributeValues: map[string]interface{}{"service_level": "Premium", "size_in_tb": float64(4)},
+			},
+			"module.flex_postgresql[\"default\"].azurerm_postgresql_flexible_server.flexpsql": {
+				Type:            "azurerm_postgresql_flexible_server",
+				AttributeValues: map[string]interface{}{"sku_name": "GP_Standard_D4s_v3", "storage_mb": float64(131072)},
+			},
+			"azurerm_container_registry.acr[0]": {
+				Type: "azurerm_container_registry",
+				AttributeValues: map[string]interface{}{
+					"sku":             "Premium",
+					"georeplications": []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+				},
+			},
+			"azurerm_log_analytics_workspace.viya4[0]": {
+				Type:            "azurerm_log_analytics_workspace",
+				AttributeValues: map[string]interface{}{"sku": "PerGB2018"},
+			},
+			"azurerm_resource_group.aks_rg[0]": {
+				Type:            "azurerm_resource_group",
+				AttributeValues: map[string]interface{}{"location": "eastus"},
+			},
+		},
+	}
+
+	estimate, err := EstimatePlan(plan, prices)
+	require.NoError(t, err)
+	require.Len(t, estimate.LineItems, 8, "Unpriced resource types should be skipped")
+
+	expected := map[string][2]float64{
+		"module.nfs[0].azurerm_linux_virtual_machine.vm":                                      {0.192 * 730, 0.192 * 730},
+		"module.aks.azurerm_kubernetes_cluster.aks":                                           {(0.10 + 0.504) * 730, (0.10 + 0.504*5) * 730},
+		"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.static_node_pool[0]": {1.152 * 2 * 730, 1.152 * 2 * 730},
+		"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]":                                  {0.045 * 128, 0.045 * 128},
+		"module.netapp[0].azurerm_netapp_pool.anf":                                            {302.0 * 4, 302.0 * 4},
+		"module.flex_postgresql[\"default\"].azurerm_postgresql_flexible_server.flexpsql":     {0.356*730 + 128*0.115, 0.356*730 + 128*0.115},
+		"azurerm_container_registry.acr[0]":                                                   {50.0 + 2*50.0, 50.0 + 2*50.0},
+		"azurerm_log_analytics_workspace.viya4[0]":                                            {2.30 * 730 / 24, 2.30 * 730 / 24},
+	}
+	minTotal, maxTotal := 0.0, 0.0
+	for _, item := range estimate.LineItems {
+		want, ok := expected[item.Address]
+		require.True(t, ok, "Unexpected line item %s", item.Address)
+		assert.InDelta(t, want[0], item.MinMonthly, 0.001, "Unexpected minimum for %s", item.Address)
+		assert.InDelta(t, want[1], item.MaxMonthly, 0.001, "Unexpected maximum for %s", item.Address)
+		minTotal += want[0]
+		maxTotal += want[1]
+	}
+	assert.InDelta(t, minTotal, estimate.MinMonthly, 0.001)
+	assert.InDelta(t, maxTotal, estimate.MaxMonthly, 0.001)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, estimate.Write(out))
+	assert.Contains(t, out.String(), "TOTAL")
+	assert.Contains(t, out.String(), "1-5 x Standard_E8s_v5")
+}
+
+// TestEstimatePlanMissingPrice verifies that resources without a price are reported rather than priced at zero.
+func TestEstimatePlanMissingPrice(t *testing.T) {
+	prices, err := LoadPriceSheet("price_sheet.json")
+	require.NoError(t, err)
+
+	plan := &terraform.PlanStruct{
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{
+			"module.jump[0].azurerm_linux_virtual_machine.vm": {
+				Type:            "azurerm_linux_virtual_machine",
+				AttributeValues: map[string]interface{}{"size": "Standard_Unknown"},
+			},
+		},
+	}
+
+	_, err = EstimatePlan(plan, prices)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Standard_Unknown")
+}
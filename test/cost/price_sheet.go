@@ -0,0 +1,56 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// PriceSheet holds the list prices used to estimate a planned deployment.
+// Hourly prices are converted to monthly prices using HoursPerMonth.
+type PriceSheet struct {
+	Currency      string  `json:"currency"`
+	HoursPerMonth float64 `json:"hours_per_month"`
+	// Hourly price by VM size, used for VMs and AKS node pools
+	VirtualMachines map[string]float64 `json:"virtual_machines"`
+	// Price per GB-month by storage account type
+	ManagedDisks map[string]float64 `json:"managed_disks"`
+	// Hourly cluster management price by AKS SKU tier
+	KubernetesClusters map[string]float64 `json:"kubernetes_clusters"`
+	// Price per TB-month by NetApp service level
+	NetAppPools map[string]float64 `json:"netapp_pools"`
+	// Hourly price by PostgreSQL flexible server SKU
+	PostgresFlexibleServers map[string]float64 `json:"postgres_flexible_servers"`
+	// Price per GB-month of PostgreSQL flexible server storage
+	PostgresStorage float64 `json:"postgres_storage"`
+	// Monthly price by container registry SKU
+	ContainerRegistries map[string]float64 `json:"container_registries"`
+	// Monthly price of each container registry geo-replica
+	ContainerRegistryGeoReplica float64           `json:"container_registry_geo_replica"`
+	LogAnalytics                LogAnalyticsPrice `json:"log_analytics"`
+}
+
+// LogAnalyticsPrice holds the ingestion price by workspace SKU and the assumed daily ingestion.
+type LogAnalyticsPrice struct {
+	PerGB            map[string]float64 `json:"per_gb"`
+	DailyIngestionGB float64            `json:"daily_ingestion_gb"`
+}
+
+// LoadPriceSheet reads a PriceSheet from a JSON file.
+func LoadPriceSheet(path string) (*PriceSheet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	prices := &PriceSheet{}
+	if err := json.Unmarshal(data, prices); err != nil {
+		return nil, fmt.Errorf("parsing price sheet %s: %w", path, err)
+	}
+	if prices.HoursPerMonth <= 0 {
+		return nil, fmt.Errorf("price sheet %s: hours_per_month must be greater than 0", path)
+	}
+	return prices, nil
+}
@@ -6,16 +6,17 @@ package defaultapply
 import (
 	"test/helpers"
 	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
 )
 
 func TestApplyDefaultMain(t *testing.T) {
-	// terrafrom init and apply using the default configuration
-	terraformOptions, plan := helpers.InitPlanAndApply(t, nil)
-
-	// deferred cleanup routine for the resources created by the terrafrom init and apply after the test have been run
-	defer helpers.DestroyDouble(t, terraformOptions)
-
-	// Drop in new test cases here
-	testApplyResourceGroup(t, plan)
-	testApplyVirtualMachine(t, plan)
+	// terrafrom init and apply using the default configuration, validate it and destroy it in separate stages
+	helpers.RunApplyStages(t, nil, func(t *testing.T, terraformOptions *terraform.Options, plan *terraform.PlanStruct) {
+		// Drop in new test cases here
+		testApplyResourceGroup(t, plan)
+		testApplyVirtualMachine(t, plan)
+		testApplyKubernetes(t, terraformOptions, plan)
+		testApplyNFSOverSSH(t, terraformOptions, plan)
+	})
 }
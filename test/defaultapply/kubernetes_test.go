@@ -0,0 +1,21 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultapply
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+func testApplyKubernetes(t *testing.T, terraformOptions *terraform.Options, plan *terraform.PlanStruct) {
+	client := helpers.GetKubernetesClientset(t, terraformOptions)
+
+	// validate the cluster objects match the plan
+	helpers.RunApplyTests(t, helpers.KubernetesNodePoolTests(client, plan))
+	helpers.RunApplyTests(t, helpers.KubernetesServiceAccountTests(client, plan))
+	helpers.RunApplyTests(t, helpers.KubernetesStorageClassTests(client, helpers.DefaultStorageClasses))
+	helpers.RunApplyTests(t, helpers.KubernetesVersionTests(client, plan))
+}
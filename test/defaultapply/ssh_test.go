@@ -0,0 +1,27 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultapply
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+func testApplyNFSOverSSH(t *testing.T, terraformOptions *terraform.Options, plan *terraform.PlanStruct) {
+	harness := helpers.GetSSHHarness(t, terraformOptions)
+	nfsPrivateIP := terraform.Output(t, terraformOptions, "nfs_private_ip")
+	jump := harness.JumpRunner()
+	nfs := harness.PrivateRunner(helpers.SSHHost{
+		Address: nfsPrivateIP,
+		User:    terraform.Output(t, terraformOptions, "nfs_admin_username"),
+	})
+
+	// validate the NFS server and the jump VM mount work, once cloud-init has finished
+	helpers.RunApplyTests(t, helpers.CloudInitTests(nfs, "nfs"))
+	helpers.RunApplyTests(t, helpers.CloudInitTests(jump, "jump"))
+	helpers.RunApplyTests(t, helpers.NFSServerTests(nfs, plan))
+	helpers.RunApplyTests(t, helpers.JumpMountTests(jump, plan, nfsPrivateIP))
+}
@@ -0,0 +1,22 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultapply
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+func TestApplyUpgradeMain(t *testing.T) {
+	// terraform init and apply of the previous release, then of the current code with the same state
+	helpers.RunUpgradeApply(t, nil, func(t *testing.T, terraformOptions *terraform.Options, plan *terraform.PlanStruct) {
+		// the upgraded deployment must pass the same validations as a new one
+		testApplyResourceGroup(t, plan)
+		testApplyVirtualMachine(t, plan)
+		testApplyKubernetes(t, terraformOptions, plan)
+		testApplyNFSOverSSH(t, terraformOptions, plan)
+	})
+}
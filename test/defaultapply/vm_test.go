@@ -34,8 +34,8 @@ func testVMList(t *testing.T, plan *terraform.PlanStruct, resourceGroupName stri
 
 	tests := map[string]helpers.ApplyTestCase{
 		"vmsLengthTest": {
-			Expected: len(vmList),
-			Actual:   2,
+			Expected: 2,
+			Actual:   len(vmList),
 		},
 		"vmsContainNsfTest": {
 			Expected:       nfsVmName,
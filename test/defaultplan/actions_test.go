@@ -0,0 +1,52 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// Test the planned actions of a new deployment with the sample-input-defaults.tfvars file: every resource is
+// created, and the attributes that Azure assigns are unknown until apply.
+func TestPlanResourceActions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]helpers.TestCase{
+		"clusterCreated": {
+			Expected:        helpers.ActionCreate,
+			Retriever:       helpers.RetrieveResourceAction,
+			ResourceMapName: "module.aks.azurerm_kubernetes_cluster.aks",
+		},
+		"statelessNodePoolCreated": {
+			Expected:        helpers.ActionCreate,
+			Retriever:       helpers.RetrieveResourceAction,
+			ResourceMapName: "module.node_pools[\"stateless\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]",
+		},
+		"vnetCreated": {
+			Expected:        helpers.ActionCreate,
+			Retriever:       helpers.RetrieveResourceAction,
+			ResourceMapName: "module.vnet.azurerm_virtual_network.vnet[0]",
+		},
+		"clusterFqdnUnknown": {
+			Expected:          "true",
+			Retriever:         helpers.RetrieveFromResourceChanges,
+			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
+			AttributeJsonPath: "{$.change.after_unknown.fqdn}",
+		},
+	}
+
+	plan := helpers.GetDefaultPlan(t)
+	helpers.RunTests(t, tests, plan)
+
+	for address, change := range plan.ResourceChangesMap {
+		if change.Mode == tfjson.DataResourceMode {
+			continue
+		}
+		helpers.AssertResourceAction(t, plan, address, helpers.ActionCreate)
+	}
+	helpers.AssertUnknownUntilApply(t, plan, "module.aks.azurerm_kubernetes_cluster.aks", []string{"id", "kube_config", "kube_admin_config"})
+}
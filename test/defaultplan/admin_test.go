@@ -4,17 +4,21 @@
 package defaultplan
 
 import (
+	"fmt"
 	"test/helpers"
 	"testing"
 )
 
 // TestAdminAccess verifies that NSG rules for admin access are correctly applied in the Terraform plan.
+// default_public_access_cidrs is set to the public access cidrs of the test profile.
 func TestPlanAdminAccess(t *testing.T) {
 	t.Parallel()
 
+	publicCIDRs := helpers.GetTestConfig(t).PublicCIDRs
+
 	tests := map[string]helpers.TestCase{
 		"defaultCidrTest": {
-			Expected:          "{[123.45.67.89/16]}",
+			Expected:          fmt.Sprintf("{%v}", publicCIDRs),
 			ResourceMapName:   "default_public_access_cidrs",
 			Retriever:         helpers.RetrieveFromRawPlanResource,
 			AttributeJsonPath: "{$}",
@@ -50,5 +54,5 @@ func TestPlanAdminAccess(t *testing.T) {
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
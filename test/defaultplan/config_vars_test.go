@@ -0,0 +1,79 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultplan
+
+import (
+	"path/filepath"
+	"test/configvars"
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// documentedAttributes maps the variables that are passed unchanged to a resource attribute to that attribute.
+var documentedAttributes = map[string]struct {
+	resourceMapName   string
+	attributeJsonPath string
+}{
+	"kubernetes_version":                  {"module.aks.azurerm_kubernetes_cluster.aks", "{$.kubernetes_version}"},
+	"node_vm_admin":                       {"module.aks.azurerm_kubernetes_cluster.aks", "{$.linux_profile[0].admin_username}"},
+	"aks_network_plugin":                  {"module.aks.azurerm_kubernetes_cluster.aks", "{$.network_profile[0].network_plugin}"},
+	"aks_network_dataplane":               {"module.aks.azurerm_kubernetes_cluster.aks", "{$.network_profile[0].network_data_plane}"},
+	"aks_network_plugin_mode":             {"module.aks.azurerm_kubernetes_cluster.aks", "{$.network_profile[0].network_plugin_mode}"},
+	"aks_cluster_sku_tier":                {"module.aks.azurerm_kubernetes_cluster.aks", "{$.sku_tier}"},
+	"cluster_support_tier":                {"module.aks.azurerm_kubernetes_cluster.aks", "{$.support_plan}"},
+	"aks_cluster_run_command_enabled":     {"module.aks.azurerm_kubernetes_cluster.aks", "{$.run_command_enabled}"},
+	"aks_azure_policy_enabled":            {"module.aks.azurerm_kubernetes_cluster.aks", "{$.azure_policy_enabled}"},
+	"enable_workload_identity":            {"module.aks.azurerm_kubernetes_cluster.aks", "{$.workload_identity_enabled}"},
+	"default_nodepool_vm_type":            {"module.aks.azurerm_kubernetes_cluster.aks", "{$.default_node_pool[0].vm_size}"},
+	"default_nodepool_os_disk_size":       {"module.aks.azurerm_kubernetes_cluster.aks", "{$.default_node_pool[0].os_disk_size_gb}"},
+	"default_nodepool_max_pods":           {"module.aks.azurerm_kubernetes_cluster.aks", "{$.default_node_pool[0].max_pods}"},
+	"default_nodepool_min_nodes":          {"module.aks.azurerm_kubernetes_cluster.aks", "{$.default_node_pool[0].min_count}"},
+	"default_nodepool_max_nodes":          {"module.aks.azurerm_kubernetes_cluster.aks", "{$.default_node_pool[0].max_count}"},
+	"default_nodepool_availability_zones": {"module.aks.azurerm_kubernetes_cluster.aks", "{$.default_node_pool[0].zones}"},
+	"fips_enabled":                        {"module.aks.azurerm_kubernetes_cluster.aks", "{$.default_node_pool[0].fips_enabled}"},
+	"jump_vm_admin":                       {"module.jump[0].azurerm_linux_virtual_machine.vm", "{$.admin_username}"},
+	"jump_vm_machine_type":                {"module.jump[0].azurerm_linux_virtual_machine.vm", "{$.size}"},
+	"nfs_vm_admin":                        {"module.nfs[0].azurerm_linux_virtual_machine.vm", "{$.admin_username}"},
+	"nfs_vm_machine_type":                 {"module.nfs[0].azurerm_linux_virtual_machine.vm", "{$.size}"},
+	"nfs_raid_disk_type":                  {"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]", "{$.storage_account_type}"},
+	"nfs_raid_disk_size":                  {"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]", "{$.disk_size_gb}"},
+}
+
+// Test that the defaults documented in docs/CONFIG-VARS.md are the values of the default plan, for the
+// variables that are not set by the sample-input-defaults.tfvars file or the location.
+func TestPlanConfigVarsDefaults(t *testing.T) {
+	t.Parallel()
+
+	rows, err := configvars.ParseMarkdown(filepath.Join("../..", configvars.DocPaths[0]))
+	require.NoError(t, err)
+	documented := make(map[string]string)
+	for _, row := range rows {
+		if value, ok := configvars.DocumentedDefault(row.Default); ok {
+			documented[row.Name] = configvars.PlanValue(value)
+		}
+	}
+
+	helpers.ForEachLocation(t, func(t *testing.T, location helpers.TestLocation) {
+		variables := helpers.GetDefaultPlanVars(t)
+		location.SetVariables(variables)
+
+		tests := make(map[string]helpers.TestCase)
+		for name, attribute := range documentedAttributes {
+			expected, ok := documented[name]
+			require.True(t, ok, "%s has no documented default in %s", name, configvars.DocPaths[0])
+			if _, set := variables[name]; set {
+				continue
+			}
+			tests[name] = helpers.TestCase{
+				Expected:          expected,
+				ResourceMapName:   attribute.resourceMapName,
+				AttributeJsonPath: attribute.attributeJsonPath,
+				Message:           "The default plan does not use the default of " + name + " documented in " + configvars.DocPaths[0],
+			}
+		}
+		helpers.RunTests(t, tests, helpers.GetDefaultPlanForLocation(t, location))
+	})
+}
@@ -62,7 +62,7 @@ func TestPlanDefaults(t *testing.T) {
 			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
 			AttributeJsonPath: "{$.linux_profile[0].ssh_key[0].key_data}",
 			AssertFunction:    assert.NotEqual,
-			Message:           "The Jump VM machine type should be Standard_B2ls_v2",
+			Message:           "The AKS node SSH public key should be set",
 		},
 		"runCommandDefaultTest": {
 			Expected:          "false",
@@ -83,7 +83,7 @@ func TestPlanDefaults(t *testing.T) {
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
 
 // Test the general variables when using the sample-input-defaults.tfvars file.
@@ -142,7 +142,7 @@ func TestPlanGeneral(t *testing.T) {
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
 
 func TestPlanAcrDisabled(t *testing.T) {
@@ -157,5 +157,5 @@ func TestPlanAcrDisabled(t *testing.T) {
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
@@ -0,0 +1,34 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultplan
+
+import (
+	"test/helpers"
+	"testing"
+)
+
+// Test the dependencies of the plan of the sample-input-defaults.tfvars file that order the destroy: terraform
+// destroys a resource before the resources it depends on.
+func TestPlanDependencyGraph(t *testing.T) {
+	t.Parallel()
+
+	graph := helpers.GetGraph(t, helpers.GetDefaultPlanVars(t))
+
+	helpers.AssertDependsOn(t, graph, "module.aks", "module.vnet.azurerm_subnet.subnet",
+		"The cluster should be destroyed before its subnet")
+	helpers.AssertDependsOn(t, graph, "module.node_pools[*]", "module.aks.azurerm_kubernetes_cluster.aks",
+		"The node pools should be destroyed before the cluster")
+	helpers.AssertDependsOn(t, graph, "module.node_pools[*]", "module.vnet.azurerm_subnet.subnet",
+		"The node pools should be destroyed before their subnet")
+	helpers.AssertDependsOn(t, graph, "module.kubeconfig", "module.aks.azurerm_kubernetes_cluster.aks",
+		"The kubeconfig resources should be destroyed before the cluster")
+	helpers.AssertDependsOn(t, graph, "kubernetes_config_map.sas_iac_buildinfo", "module.aks.azurerm_kubernetes_cluster.aks",
+		"The build info config map should be destroyed before the cluster")
+	for _, vm := range []string{"module.jump[0]", "module.nfs[0]"} {
+		helpers.AssertDependsOn(t, graph, vm+".azurerm_network_interface_security_group_association.vm_nic_sg",
+			"azurerm_network_security_group.nsg", "The NSG association should be destroyed before the NSG")
+		helpers.AssertDependsOn(t, graph, vm+".azurerm_network_interface.vm_nic", "module.vnet.azurerm_subnet.subnet",
+			"The network interface should be destroyed before its subnet")
+	}
+}
@@ -18,9 +18,9 @@ func TestPlanNetwork(t *testing.T) {
 			AttributeJsonPath: "{$.address_space}",
 		},
 		"vnetSubnetTest": {
-			Expected:          "",
 			ResourceMapName:   "module.vnet.azurerm_virtual_network.vnet[0]",
-			AttributeJsonPath: "{$.subnet[0].name}",
+			AttributeJsonPath: "{$.subnet}",
+			ExpectAbsent:      true,
 		},
 		"clusterEgressTypeTest": {
 			Expected:          "loadBalancer",
@@ -33,14 +33,14 @@ func TestPlanNetwork(t *testing.T) {
 			AttributeJsonPath: "{$.network_profile[0].network_plugin}",
 		},
 		"aksNetworkPolicyTest": {
-			Expected:          "",
 			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
-			AttributeJsonPath: "{$.expressions.aks_network_policy.reference[0]}",
+			AttributeJsonPath: "{$.network_profile[0].network_policy}",
+			ExpectAbsent:      true,
 		},
 		"aksNetworkPluginModeTest": {
-			Expected:          "",
+			Expected:          "overlay",
 			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
-			AttributeJsonPath: "{$.expressions.aks_network_plugin_mode.reference[0]}",
+			AttributeJsonPath: "{$.network_profile[0].network_plugin_mode}",
 		},
 		"kubeletPluginAksPodCidrTest": {
 			Expected:        "10.244.0.0/16",
@@ -49,5 +49,5 @@ func TestPlanNetwork(t *testing.T) {
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
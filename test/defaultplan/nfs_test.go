@@ -24,7 +24,7 @@ func TestPlanNFSPublicIP(t *testing.T) {
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
 
 func TestPlanNFSDisk(t *testing.T) {
@@ -109,5 +109,5 @@ func TestPlanNFSDisk(t *testing.T) {
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
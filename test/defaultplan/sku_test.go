@@ -0,0 +1,29 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultplan
+
+import (
+	"test/helpers"
+	"test/sku"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Verify that the VM sizes, zones, disk types and host encryption settings of the default plan are
+// supported in each test location according to the offline SKU snapshot.
+// Regenerate the snapshot with 'go run ./cmd/skusnapshot' from the test directory.
+func TestPlanSkuCapabilities(t *testing.T) {
+	t.Parallel()
+
+	snapshot, err := sku.LoadSnapshot("../sku/vm_skus_snapshot.json")
+	require.NoError(t, err)
+
+	helpers.ForEachLocation(t, func(t *testing.T, location helpers.TestLocation) {
+		for _, finding := range sku.CheckPlan(helpers.GetDefaultPlanForLocation(t, location), snapshot) {
+			assert.Fail(t, "Unsupported SKU configuration", finding.String())
+		}
+	})
+}
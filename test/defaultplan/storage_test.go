@@ -29,12 +29,12 @@ func TestPlanStorage(t *testing.T) {
 			AttributeJsonPath: "{$}",
 			AssertFunction:    assert.NotEqual,
 		},
-		"vmZoneEmptyStrTest": {
-			Expected:          "",
+		"vmZoneAbsentTest": {
 			ResourceMapName:   "module.nfs[0].azurerm_linux_virtual_machine.vm",
-			AttributeJsonPath: "{$.vm_zone}",
+			AttributeJsonPath: "{$.zone}",
+			ExpectAbsent:      true,
 		},
 	}
 
-	helpers.RunTests(t, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTests(t, tests)
 }
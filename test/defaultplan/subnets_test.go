@@ -18,7 +18,7 @@ func TestPlanSubnets(t *testing.T) {
 				"serviceEndpoints":                         {`["Microsoft.Sql"]`, "{$.service_endpoints}"},
 				"privateEndpointNetworkPolicies":           {`Enabled`, "{$.private_endpoint_network_policies}"},
 				"privateLinkServiceNetworkPoliciesEnabled": {`false`, "{$.private_link_service_network_policies_enabled}"},
-				"serviceDelegations":                       {``, "{$.service_delegations}"},
+				"serviceDelegations":                       {`[]`, "{$.delegation}"},
 			},
 		},
 		"misc": {
@@ -27,11 +27,11 @@ func TestPlanSubnets(t *testing.T) {
 				"serviceEndpoints":                         {`["Microsoft.Sql"]`, "{$.service_endpoints}"},
 				"privateEndpointNetworkPolicies":           {`Enabled`, "{$.private_endpoint_network_policies}"},
 				"privateLinkServiceNetworkPoliciesEnabled": {`false`, "{$.private_link_service_network_policies_enabled}"},
-				"serviceDelegations":                       {``, "{$.service_delegations}"},
+				"serviceDelegations":                       {`[]`, "{$.delegation}"},
 			},
 		},
 	}
 
 	resourceMapNameFmt := "module.vnet.azurerm_subnet.subnet[\"%s\"]"
-	helpers.RunTupleTests(t, resourceMapNameFmt, tests, helpers.GetDefaultPlan(t))
+	helpers.RunDefaultPlanTupleTests(t, resourceMapNameFmt, tests)
 }
@@ -4,59 +4,118 @@
 package helpers
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"test/quota"
+	"test/sku"
 	"testing"
 
+	"github.com/gruntwork-io/terratest/modules/files"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/terraform"
-	test_structure "github.com/gruntwork-io/terratest/modules/test-structure"
 	"github.com/stretchr/testify/require"
 )
 
 func InitPlanAndApply(t *testing.T, overrides map[string]interface{}) (*terraform.Options, *terraform.PlanStruct) {
-	validateEnvVars(t, "TF_VAR_client_id", "TF_VAR_client_secret", "TF_VAR_tenant_id",
-		"TF_VAR_subscription_id", "TF_VAR_public_cidrs")
-
-	tfVarsPath := "../../examples/sample-input-defaults.tfvars"
+	RequireSuite(t, ApplySuite)
+	return initPlanAndApply(t, overrides, "")
+}
 
-	variables := make(map[string]interface{})
-	terraform.GetAllVariablesFromVarFile(t, tfVarsPath, &variables)
+// initPlanAndApply copies the configuration to a temporary folder, then plans and applies it. When workDir is
+// set, the copy and the plan file are kept in it, and the options and plan are saved to it before the apply so
+// a later teardown stage can destroy a partial apply.
+func initPlanAndApply(t *testing.T, overrides map[string]interface{}, workDir string) (*terraform.Options, *terraform.PlanStruct) {
+	ResolveAzureCredential(t)
 
 	// Use a unique prefix in case multiple applies are processing.
-	variables["prefix"] = "terratest-" + strings.ToLower(random.UniqueId())
-	variables["location"] = "eastus"
-	variables["default_public_access_cidrs"] = os.Getenv("TF_VAR_public_cidrs")
-	if overrides != nil {
-		for k, v := range overrides {
-			variables[k] = v
-		}
+	prefix := "terratest-" + strings.ToLower(random.UniqueId())
+	variables := getApplyVariables(t, "../../", prefix, overrides)
+
+	destRootFolder := os.TempDir()
+	if workDir != "" {
+		destRootFolder = workDir
+		require.NoError(t, os.MkdirAll(workDir, 0755))
 	}
 
 	// Set up Terraform options with temporary folders (deleted in DestroyDouble)
 	options := &terraform.Options{
-		TerraformDir: test_structure.CopyTerraformFolderToTemp(t, "../../", ""),
+		TerraformDir: copyRepository(t, destRootFolder),
 		Vars:         variables,
-		PlanFilePath: filepath.Join(os.TempDir(), "testplan-"+variables["prefix"].(string)+".tfplan"),
+		PlanFilePath: filepath.Join(destRootFolder, "testplan-"+variables["prefix"].(string)+".tfplan"),
 		NoColor:      true,
+		Logger:       RedactingLogger,
 	}
 
-	plan := terraform.InitAndPlanAndShowWithStruct(t, options)
+	planJSON := terraform.InitAndPlanAndShow(t, options)
+	plan, err := terraform.ParsePlanJSON(planJSON)
+	require.NoError(t, err)
+	RegisterPlanSecrets(plan)
+
+	if workDir != "" {
+		saveStagedApply(t, workDir, options, planJSON)
+	}
+
+	checkVCPUQuota(t, plan)
 
 	terraform.Apply(t, options)
 
+	CheckIdempotency(t, options)
+
 	return options, plan
 }
 
-func validateEnvVars(t *testing.T, vars ...string) {
-	for _, v := range vars {
-		if os.Getenv(v) == "" {
-			t.Fatalf("Environment variable %s must be set", v)
-		}
+// VCPUUsageFileEnvVar names a file with the output of 'az vm list-usage --location <location> -o json' for the
+// location of the apply, the first of GetTestLocations. When set, the worst-case vCPUs of the plan are checked
+// against it before applying.
+const VCPUUsageFileEnvVar = "VCPU_USAGE_FILE"
+
+// checkVCPUQuota fails the test before the apply if the vCPU quota in VCPU_USAGE_FILE is too small for the plan.
+func checkVCPUQuota(t *testing.T, plan *terraform.PlanStruct) {
+	usageFile := os.Getenv(VCPUUsageFileEnvVar)
+	if usageFile == "" {
+		return
+	}
+
+	catalog, err := sku.LoadCatalog("../sku/vm_skus_snapshot.json")
+	require.NoError(t, err)
+	usages, err := quota.LoadUsages(usageFile)
+	require.NoError(t, err)
+	report, err := quota.Preflight(plan, catalog, usages)
+	require.NoError(t, err)
+
+	out := &strings.Builder{}
+	require.NoError(t, report.Write(out))
+	if len(report.Shortfalls) > 0 {
+		t.Fatalf("vCPU quota pre-flight check failed:\n%s", out.String())
+	}
+	t.Logf("vCPU quota pre-flight check passed:\n%s", out.String())
+}
+
+// getApplyVariables returns the variables of the tfvars file of the test profile in the rootFolder
+// configuration, set to the prefix, the first test location and the public access cidrs, with the overrides
+// applied.
+func getApplyVariables(t *testing.T, rootFolder string, prefix string, overrides map[string]interface{}) map[string]interface{} {
+	config := GetTestConfig(t)
+	publicCIDRs := requirePublicCIDRs(t)
+	tfVarsPath := filepath.Join(rootFolder, "examples", config.TfvarsFile)
+
+	variables := make(map[string]interface{})
+	terraform.GetAllVariablesFromVarFile(t, tfVarsPath, &variables)
+
+	variables["prefix"] = prefix
+	GetTestLocations(t)[0].SetVariables(variables)
+	variables["default_public_access_cidrs"] = publicCIDRs
+	for k, v := range overrides {
+		variables[k] = v
 	}
+	RegisterSensitiveVariables(variables)
+	return variables
 }
 
+// DestroyDouble destroys the resources of the options, retrying once, then removes the plan file and the copy of
+// the repository that copyRepository made for them. Other folders are left alone.
 func DestroyDouble(t *testing.T, terraformOptions *terraform.Options) {
 	// Destroy the resources we created
 	_, err := terraform.DestroyE(t, terraformOptions)
@@ -70,10 +129,54 @@ func DestroyDouble(t *testing.T, terraformOptions *terraform.Options) {
 	}
 
 	// Remove the temporary folders
-	err = os.Remove(terraformOptions.PlanFilePath)
-	require.NoError(t, err)
-	tempTestFolderSlice := strings.Split(terraformOptions.TerraformDir, string(os.PathSeparator))
-	tempTestFolderPath := strings.Join(tempTestFolderSlice[:len(tempTestFolderSlice)-1], string(os.PathSeparator))
-	err = os.RemoveAll(tempTestFolderPath)
+	if isRepositoryCopyFolder(t, filepath.Dir(terraformOptions.PlanFilePath)) {
+		err = os.Remove(terraformOptions.PlanFilePath)
+		require.NoError(t, err)
+	}
+	require.NoError(t, removeRepositoryCopy(t, terraformOptions.TerraformDir))
+}
+
+// repositoryCopyPrefix starts the name of the folders that copyRepository creates, so that only those are removed.
+const repositoryCopyPrefix = "terratest-repository-"
+
+// copyRepository copies the repository, without its hidden folders and state files, to a new folder in
+// destRootFolder and returns the path of the copy. Unlike test_structure.CopyTerraformFolderToDest, it copies even
+// when a SKIP_* stage variable is set, so terraform never runs in the repository itself.
+func copyRepository(t *testing.T, destRootFolder string) string {
+	repository, err := files.CopyTerraformFolderToDest("../../", destRootFolder, repositoryCopyPrefix)
 	require.NoError(t, err)
+	return repository
+}
+
+// removeRepositoryCopy removes the folder that copyRepository created for the copy at path. It refuses to remove
+// any other folder, such as the repository itself.
+func removeRepositoryCopy(t *testing.T, path string) error {
+	folder, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(filepath.Base(folder), repositoryCopyPrefix) || !isRepositoryCopyFolder(t, filepath.Dir(folder)) {
+		return fmt.Errorf("%s is not a copy of the repository made by the test helpers, not removing it", path)
+	}
+	return os.RemoveAll(folder)
+}
+
+// isRepositoryCopyFolder reports whether copyRepository makes its copies in the folder, which is the temporary
+// directory, the apply work dir or a folder inside them.
+func isRepositoryCopyFolder(t *testing.T, folder string) bool {
+	return isInFolder(folder, os.TempDir()) || isInFolder(folder, GetApplyWorkDir(t))
+}
+
+// isInFolder reports whether the path is the folder or is inside it.
+func isInFolder(path string, folder string) bool {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	folder, err = filepath.Abs(folder)
+	if err != nil {
+		return false
+	}
+	relative, err := filepath.Rel(folder, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(os.PathSeparator))
 }
@@ -0,0 +1,168 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	test_structure "github.com/gruntwork-io/terratest/modules/test-structure"
+	"github.com/stretchr/testify/require"
+)
+
+// Apply stages. Set SKIP_<stage>, e.g. SKIP_teardown=true, to skip a stage.
+const (
+	SetupStage    = "setup"
+	ValidateStage = "validate"
+	TeardownStage = "teardown"
+)
+
+// ApplyWorkDirEnvVar overrides the folder the Terraform options, plan, state and configuration copy of a staged
+// apply are kept in. The folder is deleted by the teardown stage, and the setup stage refuses to run while it
+// holds a staged apply. It must be outside the repository or hidden,
+// since the repository is copied into it.
+const ApplyWorkDirEnvVar = "APPLY_WORK_DIR"
+
+// DefaultApplyWorkDir is the work dir, relative to the test package, used when APPLY_WORK_DIR is not set.
+const DefaultApplyWorkDir = ".test-data"
+
+// The files of the Terraform options and of the redacted plan in the work dir of a staged apply
+const (
+	stagedOptionsFile = "TerraformOptions.json"
+	stagedPlanFile    = "plan.json"
+)
+
+// ApplyValidation validates an applied configuration.
+type ApplyValidation func(t *testing.T, terraformOptions *terraform.Options, plan *terraform.PlanStruct)
+
+// GetApplyWorkDir returns the absolute path of the work dir of a staged apply. It fails the test if the work dir
+// is the repository, contains it, or is a folder of the repository that is not hidden and would be copied into
+// itself.
+func GetApplyWorkDir(t *testing.T) string {
+	workDir := os.Getenv(ApplyWorkDirEnvVar)
+	if workDir == "" {
+		workDir = DefaultApplyWorkDir
+	}
+	workDir, err := filepath.Abs(workDir)
+	require.NoError(t, err)
+	repository, err := filepath.Abs("../..")
+	require.NoError(t, err)
+	if isInFolder(repository, workDir) {
+		t.Fatalf("The apply work dir %s contains the repository, set %s to a folder outside of it", workDir, ApplyWorkDirEnvVar)
+	}
+	if relative, err := filepath.Rel(repository, workDir); err == nil && isInFolder(workDir, repository) &&
+		!strings.HasPrefix(relative, ".") && !strings.Contains(relative, string(os.PathSeparator)+".") {
+		t.Fatalf("The apply work dir %s is in the repository and not hidden, set %s to a folder outside of it", workDir, ApplyWorkDirEnvVar)
+	}
+	return workDir
+}
+
+// RunApplyStages applies the configuration with the overrides, runs validate against it and destroys it, as the
+// setup, validate and teardown stages. The Terraform options and plan are saved to the work dir during setup and
+// loaded from it by the later stages, so a deployment can be applied once with SKIP_teardown set, validated
+// repeatedly with SKIP_setup and SKIP_teardown set, and finally destroyed with SKIP_setup and SKIP_validate set.
+// It is skipped unless the apply suite is enabled in the test profile.
+func RunApplyStages(t *testing.T, overrides map[string]interface{}, validate ApplyValidation) {
+	RequireSuite(t, ApplySuite)
+	workDir := GetApplyWorkDir(t)
+
+	// Stop before the teardown is deferred, so a deployment applied by an earlier run is not destroyed
+	if os.Getenv(test_structure.SKIP_STAGE_ENV_VAR_PREFIX+SetupStage) == "" && test_structure.IsTestDataPresent(t, stagedOptionsPath(workDir)) {
+		t.Fatalf("A staged apply already exists in %s. Set SKIP_%s to validate it, or set SKIP_%s and SKIP_%s to destroy it before applying again",
+			workDir, SetupStage, SetupStage, ValidateStage)
+	}
+
+	// deferred cleanup routine for the resources created by the setup stage, which also runs if a stage fails
+	defer test_structure.RunTestStage(t, TeardownStage, func() {
+		if !test_structure.IsTestDataPresent(t, stagedOptionsPath(workDir)) {
+			t.Logf("No staged apply in %s, nothing to tear down", workDir)
+			return
+		}
+		DestroyDouble(t, LoadStagedTerraformOptions(t, workDir, overrides))
+		// The resources are destroyed, so the state, options and plan of the staged apply can go
+		require.NoError(t, os.RemoveAll(workDir))
+	})
+
+	test_structure.RunTestStage(t, SetupStage, func() {
+		initPlanAndApply(t, overrides, workDir)
+	})
+
+	test_structure.RunTestStage(t, ValidateStage, func() {
+		validate(t, LoadStagedTerraformOptions(t, workDir, overrides), LoadStagedPlan(t, workDir))
+	})
+}
+
+// saveStagedApply saves the Terraform options and the plan to the work dir for the later stages. Both are saved
+// redacted, so the secret variables, such as the postgres_servers passwords, are not kept in clear text.
+func saveStagedApply(t *testing.T, workDir string, options *terraform.Options, planJSON string) {
+	data, err := json.Marshal(options)
+	require.NoError(t, err)
+	test_structure.SaveTestData(t, stagedOptionsPath(workDir), true, json.RawMessage(Redact(string(data))))
+	test_structure.SaveTestData(t, stagedPlanPath(workDir), true, Redact(planJSON))
+}
+
+// LoadStagedTerraformOptions loads the Terraform options saved by the setup stage. The redacted variables are
+// read again from the tfvars file of the test profile in the copy of the repository and from the overrides,
+// which must be the ones passed to the setup stage.
+func LoadStagedTerraformOptions(t *testing.T, workDir string, overrides map[string]interface{}) *terraform.Options {
+	requireStagedData(t, stagedOptionsPath(workDir))
+	var options terraform.Options
+	test_structure.LoadTestData(t, stagedOptionsPath(workDir), &options)
+	if hasRedactedValue(options.Vars) {
+		prefix, _ := options.Vars["prefix"].(string)
+		restoreRedactedVariables(options.Vars, getApplyVariables(t, options.TerraformDir, prefix, overrides))
+		require.False(t, hasRedactedValue(options.Vars),
+			"The redacted variables of the staged apply in %s could not be read again, pass the overrides of the setup stage", workDir)
+	}
+	RegisterSensitiveVariables(options.Vars)
+	options.Logger = RedactingLogger
+	return &options
+}
+
+// restoreRedactedVariables replaces the variables with a redacted value by the variable of the same name in source.
+func restoreRedactedVariables(variables map[string]interface{}, source map[string]interface{}) {
+	for name, value := range variables {
+		if restored, ok := source[name]; ok && hasRedactedValue(value) {
+			variables[name] = restored
+		}
+	}
+}
+
+// hasRedactedValue reports whether a string of the value, at any depth, was redacted.
+func hasRedactedValue(value interface{}) bool {
+	for _, leaf := range stringLeaves(value) {
+		if strings.Contains(leaf, Redacted) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadStagedPlan loads the plan saved by the setup stage, so RetrieveFromPlan works in a later run.
+func LoadStagedPlan(t *testing.T, workDir string) *terraform.PlanStruct {
+	requireStagedData(t, stagedPlanPath(workDir))
+	var planJSON string
+	test_structure.LoadTestData(t, stagedPlanPath(workDir), &planJSON)
+	plan, err := terraform.ParsePlanJSON(planJSON)
+	require.NoError(t, err)
+	return plan
+}
+
+func requireStagedData(t *testing.T, path string) {
+	if !test_structure.IsTestDataPresent(t, path) {
+		t.Fatalf("No staged apply data at %s, run the %s stage first (unset SKIP_%s)", path, SetupStage, SetupStage)
+	}
+}
+
+func stagedOptionsPath(workDir string) string {
+	return filepath.Join(workDir, stagedOptionsFile)
+}
+
+func stagedPlanPath(workDir string) string {
+	return filepath.Join(workDir, stagedPlanFile)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetApplyWorkDir verifies that APPLY_WORK_DIR overrides the default work dir and is made absolute.
+func TestGetApplyWorkDir(t *testing.T) {
+	t.Setenv(ApplyWorkDirEnvVar, "")
+	defaultDir, err := filepath.Abs(DefaultApplyWorkDir)
+	require.NoError(t, err)
+	assert.Equal(t, defaultDir, GetApplyWorkDir(t))
+
+	workDir := t.TempDir()
+	t.Setenv(ApplyWorkDirEnvVar, workDir)
+	assert.Equal(t, workDir, GetApplyWorkDir(t))
+}
+
+// TestLoadStagedData verifies that the options and plan saved by the setup stage are restored by later stages.
+func TestLoadStagedData(t *testing.T) {
+	workDir := t.TempDir()
+	options := &terraform.Options{TerraformDir: "/tmp/terraform", Vars: map[string]interface{}{"prefix": "staged"}}
+	planJSON := `{"format_version":"1.2","variables":{"prefix":{"value":"staged"}},` +
+		`"planned_values":{"root_module":{"resources":[{"address":"azurerm_resource_group.aks_rg[0]",` +
+		`"type":"azurerm_resource_group","values":{"name":"staged-rg"}}]}}}`
+	saveStagedApply(t, workDir, options, planJSON)
+	assert.FileExists(t, filepath.Join(workDir, "TerraformOptions.json"))
+
+	assert.Equal(t, options.TerraformDir, LoadStagedTerraformOptions(t, workDir, nil).TerraformDir)
+	plan := LoadStagedPlan(t, workDir)
+	assert.Equal(t, "staged-rg", RetrieveFromPlan(plan, "azurerm_resource_group.aks_rg[0]", "{$.name}")())
+}
+
+// TestSaveStagedApplyRedacted verifies that the secret variables are not saved in clear text, and that the
+// redacted variables are restored from the variables read again.
+func TestSaveStagedApplyRedacted(t *testing.T) {
+	workDir := t.TempDir()
+	servers := map[string]interface{}{"default": map[string]interface{}{"administrator_password": "staged-Passw0rd"}}
+	options := &terraform.Options{TerraformDir: "/tmp/terraform", Vars: map[string]interface{}{"prefix": "staged", "postgres_servers": servers}}
+	RegisterSensitiveVariables(options.Vars)
+	saveStagedApply(t, workDir, options, `{"format_version":"1.2"}`)
+
+	data, err := os.ReadFile(filepath.Join(workDir, "TerraformOptions.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "staged-Passw0rd")
+
+	variables := map[string]interface{}{"prefix": "staged", "postgres_servers": map[string]interface{}{
+		"default": map[string]interface{}{"administrator_password": Redacted}}}
+	assert.True(t, hasRedactedValue(variables))
+	restoreRedactedVariables(variables, map[string]interface{}{"prefix": "other", "postgres_servers": servers})
+	assert.Equal(t, map[string]interface{}{"prefix": "staged", "postgres_servers": servers}, variables)
+	assert.False(t, hasRedactedValue(variables))
+}
+
+// TestRemoveRepositoryCopy verifies that only the folders made by copyRepository in the temporary directory or the
+// work dir are removed, and never the repository.
+func TestRemoveRepositoryCopy(t *testing.T) {
+	folder, err := os.MkdirTemp(t.TempDir(), repositoryCopyPrefix)
+	require.NoError(t, err)
+	repository := filepath.Join(folder, "module")
+	require.NoError(t, os.MkdirAll(repository, 0755))
+	require.NoError(t, removeRepositoryCopy(t, repository))
+	assert.NoDirExists(t, folder)
+
+	other := filepath.Join(t.TempDir(), "module")
+	require.NoError(t, os.MkdirAll(other, 0755))
+	assert.ErrorContains(t, removeRepositoryCopy(t, other), "not a copy of the repository")
+	assert.DirExists(t, other)
+	assert.ErrorContains(t, removeRepositoryCopy(t, "../.."), "not a copy of the repository")
+}
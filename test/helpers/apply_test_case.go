@@ -4,8 +4,12 @@
 package helpers
 
 import (
-	"github.com/stretchr/testify/assert"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // ApplyTestCase struct defines the attributes for a test case
@@ -16,6 +20,31 @@ type ApplyTestCase struct {
 	ActualRetriever   func() string
 	AssertFunction    assert.ComparisonAssertionFunc
 	Message           string
+	// Eventually re-invokes ActualRetriever until the assertion passes or the timeout expires.
+	// Use it for values that lag behind the apply, e.g. VM InstanceView or AKS node readiness.
+	Eventually *Eventually
+}
+
+// DefaultPollInterval is the interval between the polls of an Eventually without a PollInterval, so that
+// polling does not call the Azure or Kubernetes API in a tight loop.
+const DefaultPollInterval = 5 * time.Second
+
+// Eventually configures the polling of an eventually consistent ApplyTestCase. The interval
+// between polls starts at PollInterval and is multiplied by Backoff after each poll, up to MaxInterval.
+type Eventually struct {
+	Timeout time.Duration
+	// PollInterval is the interval before the second poll. Zero means DefaultPollInterval.
+	PollInterval time.Duration
+	// Backoff multiplies the poll interval after each poll. Values below 1 keep the interval constant.
+	Backoff float64
+	// MaxInterval caps the poll interval. Zero means no cap.
+	MaxInterval time.Duration
+}
+
+// observation is a value returned by ActualRetriever while polling.
+type observation struct {
+	elapsed time.Duration
+	value   string
 }
 
 // RunApplyTest runs a test case
@@ -24,18 +53,72 @@ func RunApplyTest(t *testing.T, tc ApplyTestCase) {
 	if tc.ExpectedRetriever != nil {
 		expected = tc.ExpectedRetriever()
 	}
+	assertFn := tc.AssertFunction
+	if assertFn == nil {
+		assertFn = assert.Equal
+	}
+	// Secrets of the plan and the deployment are redacted in the failure message
+	if tc.Eventually != nil && tc.ActualRetriever != nil {
+		runEventually(redactingT{t}, tc, assertFn, expected)
+		return
+	}
 	actual := tc.Actual
 	if tc.ActualRetriever != nil {
 		actual = tc.ActualRetriever()
 	}
-	assertFn := tc.AssertFunction
-	if assertFn == nil {
-		assertFn = assert.Equal
+	compare(redactingT{t}, assertFn, expected, actual, tc.Message)
+}
+
+// runEventually polls ActualRetriever until the assertion passes or the timeout expires. The
+// final assertion message includes every observed value.
+func runEventually(t assert.TestingT, tc ApplyTestCase, assertFn assert.ComparisonAssertionFunc, expected interface{}) {
+	start := time.Now()
+	interval := tc.Eventually.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
 	}
-	validateFn := AssertComparison(assertFn, expected)
-	validateFn(t, actual, tc.Message)
+	var history []observation
+
+	for {
+		actual := tc.ActualRetriever()
+		history = append(history, observation{time.Since(start), actual})
+		if compare(&probeT{}, assertFn, expected, actual) {
+			return
+		}
+		if time.Since(start)+interval > tc.Eventually.Timeout {
+			break
+		}
+		time.Sleep(interval)
+		if tc.Eventually.Backoff > 1 {
+			interval = time.Duration(float64(interval) * tc.Eventually.Backoff)
+		}
+		if tc.Eventually.MaxInterval > 0 && interval > tc.Eventually.MaxInterval {
+			interval = tc.Eventually.MaxInterval
+		}
+	}
+
+	observed := make([]string, len(history))
+	for i, o := range history {
+		observed[i] = fmt.Sprintf("%s: %q", o.elapsed.Round(time.Second), o.value)
+	}
+	message := fmt.Sprintf("%s (no match after %d polls over %s, observed values: [%s])",
+		tc.Message, len(history), time.Since(start).Round(time.Second), strings.Join(observed, ", "))
+	compare(t, assertFn, expected, history[len(history)-1].value, message)
+}
+
+// compare runs the comparison assertion function with the arguments in the order it expects.
+func compare(t assert.TestingT, fn assert.ComparisonAssertionFunc, expected interface{}, actual interface{}, messages ...interface{}) bool {
+	if invertArgs(fn) {
+		return fn(t, actual, expected, messages...)
+	}
+	return fn(t, expected, actual, messages...)
 }
 
+// probeT is an assert.TestingT that discards failures so an assertion can be tried without failing the test.
+type probeT struct{}
+
+func (p *probeT) Errorf(string, ...interface{}) {}
+
 // RunApplyTests ranges over a set of test cases and runs them
 func RunApplyTests(t *testing.T, tests map[string]ApplyTestCase) {
 	for name, tc := range tests {
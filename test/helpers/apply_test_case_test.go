@@ -0,0 +1,85 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRunApplyTestEventually verifies that an eventually consistent test case polls the retriever
+// until the assertion passes.
+func TestRunApplyTestEventually(t *testing.T) {
+	values := []string{"Updating", "Updating", "Succeeded"}
+	calls := 0
+
+	RunApplyTest(t, ApplyTestCase{
+		Expected: "Succeeded",
+		ActualRetriever: func() string {
+			value := values[calls]
+			calls++
+			return value
+		},
+		Message: "Provisioning state is incorrect",
+		Eventually: &Eventually{
+			Timeout:      time.Second,
+			PollInterval: time.Millisecond,
+			Backoff:      2,
+			MaxInterval:  5 * time.Millisecond,
+		},
+	})
+	assert.Equal(t, 3, calls)
+}
+
+// TestRunApplyTestEventuallyTimeout verifies that the failure message of an eventually consistent
+// test case contains the history of observed values.
+func TestRunApplyTestEventuallyTimeout(t *testing.T) {
+	recorder := &recordingT{}
+	tc := ApplyTestCase{
+		Expected:        "Succeeded",
+		ActualRetriever: func() string { return "Updating" },
+		Message:         "Provisioning state is incorrect",
+		Eventually:      &Eventually{Timeout: 20 * time.Millisecond, PollInterval: 5 * time.Millisecond},
+	}
+
+	runEventually(recorder, tc, assert.Equal, "Succeeded")
+
+	assert.True(t, recorder.failed)
+	assert.Contains(t, recorder.message, "Provisioning state is incorrect (no match after")
+	assert.Contains(t, recorder.message, `0s: "Updating"`)
+}
+
+// TestRunApplyTestEventuallyDefaultInterval verifies that an eventually consistent test case without a poll
+// interval waits DefaultPollInterval between polls, instead of polling in a tight loop until the timeout.
+func TestRunApplyTestEventuallyDefaultInterval(t *testing.T) {
+	recorder := &recordingT{}
+	calls := 0
+	tc := ApplyTestCase{
+		Expected: "Succeeded",
+		ActualRetriever: func() string {
+			calls++
+			return "Updating"
+		},
+		Message:    "Provisioning state is incorrect",
+		Eventually: &Eventually{Timeout: 50 * time.Millisecond},
+	}
+
+	runEventually(recorder, tc, assert.Equal, "Succeeded")
+
+	assert.True(t, recorder.failed)
+	assert.Equal(t, 1, calls)
+}
+
+type recordingT struct {
+	failed  bool
+	message string
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,262 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables that select the test configuration and override single settings of its profile.
+const (
+	ConfigFileEnvVar  = "TEST_CONFIG_FILE"
+	ProfileEnvVar     = "TEST_PROFILE"
+	TfvarsFileEnvVar  = "TEST_TFVARS_FILE"
+	PublicCIDRsEnvVar = "TF_VAR_public_cidrs"
+	ParallelEnvVar    = "TEST_PARALLEL"
+	TimeoutEnvVar     = "TEST_TIMEOUT"
+	CostBudgetEnvVar  = "TEST_COST_BUDGET"
+	SuitesEnvVar      = "TEST_SUITES"
+)
+
+// DefaultConfigFile is the test configuration, relative to the test packages.
+const DefaultConfigFile = "../config.yaml"
+
+// DefaultProfile is used when TEST_PROFILE is not set.
+const DefaultProfile = "local"
+
+// Test suites that a profile can enable.
+const (
+	PlanSuite    = "plan"
+	ApplySuite   = "apply"
+	UpgradeSuite = "upgrade"
+)
+
+// DefaultBudgetKey is the budgets entry used for the example tfvars files without their own budget.
+const DefaultBudgetKey = "default"
+
+// TestConfig is a test environment profile of config.yaml with the environment overrides applied.
+type TestConfig struct {
+	Profile     string             `yaml:"-"`
+	Locations   []string           `yaml:"locations"`
+	TfvarsFile  string             `yaml:"tfvars_file"`
+	PublicCIDRs []string           `yaml:"public_cidrs"`
+	Parallel    int                `yaml:"parallel"`
+	Timeout     string             `yaml:"timeout"`
+	Budgets     map[string]float64 `yaml:"budgets"`
+	Suites      []string           `yaml:"suites"`
+}
+
+type testConfigFile struct {
+	Profiles map[string]TestConfig `yaml:"profiles"`
+}
+
+var logConfigOnce sync.Once
+
+// GetTestConfig returns the profile in TEST_PROFILE of the configuration file in TEST_CONFIG_FILE, or of
+// DefaultConfigFile, with the environment overrides applied. The effective configuration is logged once.
+func GetTestConfig(t *testing.T) *TestConfig {
+	path := os.Getenv(ConfigFileEnvVar)
+	if path == "" {
+		path = DefaultConfigFile
+	}
+	config, err := LoadTestConfig(path, os.Getenv(ProfileEnvVar), os.Getenv)
+	if err != nil {
+		t.Fatalf("Error loading the test configuration: %s", err)
+	}
+	logConfigOnce.Do(func() {
+		t.Logf("Effective test configuration:\n%s", config)
+	})
+	return config
+}
+
+// LoadTestConfig returns the profile of the configuration file with the overrides of getenv applied. The
+// DefaultProfile is used if profile is empty.
+func LoadTestConfig(path string, profile string, getenv func(string) string) (*TestConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file testConfigFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	config, ok := file.Profiles[profile]
+	if !ok {
+		names := make([]string, 0, len(file.Profiles))
+		for name := range file.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("profile %s is not in %s, use one of %s", profile, path, strings.Join(names, ", "))
+	}
+	config.Profile = profile
+
+	if err := config.applyOverrides(getenv); err != nil {
+		return nil, err
+	}
+	config.setDefaults()
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+	return &config, nil
+}
+
+// Budget returns the maximum monthly cost estimate of the example tfvars file, or false if the profile has
+// neither a budget for the file nor a "default" budget, in which case the estimate is not limited.
+func (c *TestConfig) Budget(exampleFileName string) (float64, bool) {
+	if budget, ok := c.Budgets[exampleFileName]; ok {
+		return budget, true
+	}
+	budget, ok := c.Budgets[DefaultBudgetKey]
+	return budget, ok
+}
+
+// requirePublicCIDRs returns the public access cidrs of the test profile. It fails the test if there are none,
+// so no plan or apply runs with a null default_public_access_cidrs.
+func requirePublicCIDRs(t *testing.T) []string {
+	config := GetTestConfig(t)
+	if len(config.PublicCIDRs) == 0 {
+		t.Fatalf("The %s test profile has no public access cidrs, set %s", config.Profile, PublicCIDRsEnvVar)
+	}
+	return config.PublicCIDRs
+}
+
+// SuiteEnabled reports whether the profile runs the test suite.
+func (c *TestConfig) SuiteEnabled(suite string) bool {
+	return containsString(c.Suites, suite)
+}
+
+// RunPattern returns the go test -run pattern of the test functions in the enabled suites. The apply and
+// upgrade tests are both named TestApply*, and the helpers skip the one whose suite is not enabled.
+func (c *TestConfig) RunPattern() string {
+	var names []string
+	if c.SuiteEnabled(PlanSuite) {
+		names = append(names, "Plan")
+	}
+	if c.SuiteEnabled(ApplySuite) || c.SuiteEnabled(UpgradeSuite) {
+		names = append(names, "Apply")
+	}
+	if len(names) == 1 {
+		return ".*" + names[0] + ".*"
+	}
+	return ".*(" + strings.Join(names, "|") + ").*"
+}
+
+func (c *TestConfig) String() string {
+	data, _ := yaml.Marshal(struct {
+		Profile     string `yaml:"profile"`
+		*TestConfig `yaml:",inline"`
+	}{c.Profile, c})
+	return string(data)
+}
+
+// RequireSuite skips the test if the test suite is not enabled in the profile.
+func RequireSuite(t *testing.T, suite string) {
+	config := GetTestConfig(t)
+	if !config.SuiteEnabled(suite) {
+		t.Skipf("The %s suite is not enabled in the %s test profile, set %s to run it", suite, config.Profile, SuitesEnvVar)
+	}
+}
+
+func (c *TestConfig) applyOverrides(getenv func(string) string) error {
+	if value := getenv(LocationsEnvVar); value != "" {
+		c.Locations = splitList(value)
+	}
+	if value := getenv(TfvarsFileEnvVar); value != "" {
+		c.TfvarsFile = value
+	}
+	if value := getenv(PublicCIDRsEnvVar); value != "" {
+		c.PublicCIDRs = parseCIDRs(value)
+	}
+	if value := getenv(ParallelEnvVar); value != "" {
+		parallel, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ParallelEnvVar, err)
+		}
+		c.Parallel = parallel
+	}
+	if value := getenv(TimeoutEnvVar); value != "" {
+		c.Timeout = value
+	}
+	if value := getenv(CostBudgetEnvVar); value != "" {
+		budget, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %w", CostBudgetEnvVar, err)
+		}
+		// A budget from the environment applies to every example tfvars file
+		c.Budgets = map[string]float64{DefaultBudgetKey: budget}
+	}
+	if value := getenv(SuitesEnvVar); value != "" {
+		c.Suites = splitList(value)
+	}
+	return nil
+}
+
+func (c *TestConfig) setDefaults() {
+	if len(c.Locations) == 0 {
+		c.Locations = []string{DefaultLocation}
+	}
+	if c.TfvarsFile == "" {
+		c.TfvarsFile = "sample-input-defaults.tfvars"
+	}
+	if c.Timeout == "" {
+		c.Timeout = "60m"
+	}
+	if len(c.Suites) == 0 {
+		c.Suites = []string{PlanSuite}
+	}
+}
+
+func (c *TestConfig) validate() error {
+	if c.Parallel < 0 {
+		return fmt.Errorf("parallel must not be negative: %d", c.Parallel)
+	}
+	if timeout, err := time.ParseDuration(c.Timeout); err != nil || timeout <= 0 {
+		return fmt.Errorf("timeout must be a positive duration such as 60m: %s", c.Timeout)
+	}
+	for _, suite := range c.Suites {
+		if suite != PlanSuite && suite != ApplySuite && suite != UpgradeSuite {
+			return fmt.Errorf("unknown suite %s, use %s, %s or %s", suite, PlanSuite, ApplySuite, UpgradeSuite)
+		}
+	}
+	return nil
+}
+
+// parseCIDRs reads a list of cidrs in the HCL list form of TF_VAR_public_cidrs, e.g. ["1.2.3.4/16"], or as a
+// comma separated list.
+func parseCIDRs(value string) []string {
+	var cidrs []string
+	if err := json.Unmarshal([]byte(value), &cidrs); err == nil {
+		return cidrs
+	}
+	return splitList(value)
+}
+
+// splitList returns the trimmed, non-empty items of a comma separated list.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,90 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadTestConfig verifies that the shipped profiles load and that the environment overrides them.
+func TestLoadTestConfig(t *testing.T) {
+	for _, profile := range []string{"local", "ci", "nightly"} {
+		_, err := LoadTestConfig(DefaultConfigFile, profile, mapEnv(nil))
+		assert.NoError(t, err, profile)
+	}
+
+	config, err := LoadTestConfig(DefaultConfigFile, "", mapEnv(nil))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfile, config.Profile)
+	assert.Equal(t, []string{"123.45.67.89/16"}, config.PublicCIDRs)
+	budget, ok := config.Budget("sample-input-minimal.tfvars")
+	assert.True(t, ok)
+	assert.Equal(t, 6000.0, budget)
+	budget, _ = config.Budget("sample-input-ha.tfvars")
+	assert.Equal(t, 15000.0, budget)
+	assert.Equal(t, ".*Plan.*", config.RunPattern())
+	assert.False(t, config.SuiteEnabled(ApplySuite))
+
+	config, err = LoadTestConfig(DefaultConfigFile, "local", mapEnv(map[string]string{
+		LocationsEnvVar:   "eastus2, westus",
+		TfvarsFileEnvVar:  "sample-input-minimal.tfvars",
+		PublicCIDRsEnvVar: `["1.2.3.4/32", "5.6.7.8/32"]`,
+		ParallelEnvVar:    "2",
+		TimeoutEnvVar:     "90m",
+		CostBudgetEnvVar:  "100",
+		SuitesEnvVar:      "plan,apply",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &TestConfig{
+		Profile:     "local",
+		Locations:   []string{"eastus2", "westus"},
+		TfvarsFile:  "sample-input-minimal.tfvars",
+		PublicCIDRs: []string{"1.2.3.4/32", "5.6.7.8/32"},
+		Parallel:    2,
+		Timeout:     "90m",
+		Budgets:     map[string]float64{DefaultBudgetKey: 100},
+		Suites:      []string{PlanSuite, ApplySuite},
+	}, config)
+	budget, _ = config.Budget("sample-input-minimal.tfvars")
+	assert.Equal(t, 100.0, budget)
+	assert.Equal(t, ".*(Plan|Apply).*", config.RunPattern())
+
+	_, ok = (&TestConfig{Budgets: map[string]float64{"sample-input-minimal.tfvars": 6000}}).Budget("sample-input-ha.tfvars")
+	assert.False(t, ok, "Without a default budget, the other example tfvars files are not limited")
+}
+
+// TestLoadTestConfigErrors verifies that unknown profiles, fields and suites and invalid values are reported.
+func TestLoadTestConfigErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("profiles:\n  local:\n    timeout: 60m\n")
+	config, err := LoadTestConfig(path, "", mapEnv(nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultLocation}, config.Locations)
+	assert.Equal(t, []string{PlanSuite}, config.Suites)
+
+	_, err = LoadTestConfig(path, "nightly", mapEnv(nil))
+	assert.EqualError(t, err, "profile nightly is not in "+path+", use one of local")
+
+	_, err = LoadTestConfig(path, "", mapEnv(map[string]string{SuitesEnvVar: "plan,destroy"}))
+	assert.EqualError(t, err, "profile local: unknown suite destroy, use plan, apply or upgrade")
+
+	_, err = LoadTestConfig(path, "", mapEnv(map[string]string{TimeoutEnvVar: "an hour"}))
+	assert.EqualError(t, err, "profile local: timeout must be a positive duration such as 60m: an hour")
+
+	_, err = LoadTestConfig(path, "", mapEnv(map[string]string{ParallelEnvVar: "many"}))
+	assert.ErrorContains(t, err, ParallelEnvVar)
+
+	write("profiles:\n  local:\n    location: eastus\n")
+	_, err = LoadTestConfig(path, "", mapEnv(nil))
+	assert.ErrorContains(t, err, "field location not found")
+}
@@ -0,0 +1,319 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Azure authentication methods, in the order of DefaultCredentialChain.
+const (
+	ClientSecretMethod      = "client secret"
+	ClientCertificateMethod = "client certificate"
+	OIDCMethod              = "OIDC federated token"
+	ManagedIdentityMethod   = "managed identity"
+	AzureCLIMethod          = "Azure CLI"
+)
+
+// AzureCredential is a resolved authentication method. Env holds the TF_VAR_*, ARM_* and AZURE_* variables that
+// configure terraform, the azurerm and azuread providers and the terratest Azure clients for the method.
+type AzureCredential struct {
+	Method         string
+	TenantID       string
+	SubscriptionID string
+	ClientID       string
+	Env            map[string]string
+	// Unset lists variables that must not be set, e.g. AZURE_CLIENT_ID, which makes the terratest Azure
+	// clients skip the Azure CLI.
+	Unset []string
+	// Setup runs after the environment is set, e.g. to sign in the Azure CLI.
+	Setup func() error
+}
+
+// CredentialSource resolves an AzureCredential from the environment. It returns nil if the method is not
+// configured and an error if it is only partly configured.
+type CredentialSource func(getenv func(string) string) (*AzureCredential, error)
+
+// DefaultCredentialChain is tried in order by ResolveAzureCredential. The first configured method is used.
+var DefaultCredentialChain = []CredentialSource{
+	ClientSecretCredential,
+	ClientCertificateCredential,
+	OIDCCredential,
+	ManagedIdentityCredential,
+	AzureCLICredential,
+}
+
+// azAccountShow returns the output of 'az account show -o json'. Replaced in tests.
+var azAccountShow = func() ([]byte, error) {
+	return exec.Command("az", "account", "show", "-o", "json").Output()
+}
+
+// azLogin runs 'az login' with the arguments. Replaced in tests.
+var azLogin = func(args ...string) error {
+	out, err := exec.Command("az", append([]string{"login", "--output", "none"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("az login: %w: %s", err, out)
+	}
+	return nil
+}
+
+// ResolveAzureCredential resolves the first configured method of DefaultCredentialChain, sets its environment
+// for terraform and the Azure clients for the rest of the test and logs the method used.
+func ResolveAzureCredential(t *testing.T) *AzureCredential {
+	credential, err := ResolveCredentialChain(DefaultCredentialChain, os.Getenv)
+	if err != nil {
+		t.Fatalf("Error resolving Azure credentials: %s", err)
+	}
+	err = credential.Apply(func(name string, value string) error {
+		t.Setenv(name, value)
+		return nil
+	}, func(name string) error {
+		t.Setenv(name, "")
+		return os.Unsetenv(name)
+	})
+	if err != nil {
+		t.Fatalf("Error setting up Azure %s authentication: %s", credential.Method, err)
+	}
+	t.Logf("Authenticating to Azure with %s", credential)
+	return credential
+}
+
+// processCredential is the credential resolved for the parallel plan tests of the test binary.
+var processCredential struct {
+	sync.Once
+	credential *AzureCredential
+	err        error
+}
+
+// resolveProcessAzureCredential resolves DefaultCredentialChain once for the test binary and sets its environment
+// for the process, since the parallel plan tests cannot use t.Setenv. The first test logs the method used.
+func resolveProcessAzureCredential(t *testing.T) *AzureCredential {
+	processCredential.Do(func() {
+		credential, err := ResolveCredentialChain(DefaultCredentialChain, os.Getenv)
+		if err == nil {
+			err = credential.Apply(os.Setenv, os.Unsetenv)
+		}
+		if err != nil {
+			processCredential.err = err
+			return
+		}
+		processCredential.credential = credential
+		t.Logf("Authenticating to Azure with %s", credential)
+	})
+	if processCredential.err != nil {
+		t.Fatalf("Error resolving Azure credentials: %s", processCredential.err)
+	}
+	return processCredential.credential
+}
+
+// Apply sets the environment of the credential with setenv, removes the variables of Unset with unsetenv and runs
+// its Setup, e.g. with os.Setenv and os.Unsetenv in the test runner.
+func (c *AzureCredential) Apply(setenv func(string, string) error, unsetenv func(string) error) error {
+	for name, value := range c.Env {
+		if err := setenv(name, value); err != nil {
+			return err
+		}
+	}
+	for _, name := range c.Unset {
+		if err := unsetenv(name); err != nil {
+			return err
+		}
+	}
+	if c.Setup != nil {
+		return c.Setup()
+	}
+	return nil
+}
+
+// String describes the method, tenant, subscription and client of the credential, without secrets.
+func (c *AzureCredential) String() string {
+	return fmt.Sprintf("%s (tenant %s, subscription %s, client %s)", c.Method, c.TenantID, c.SubscriptionID, valueOr(c.ClientID, "none"))
+}
+
+// ResolveCredentialChain returns the credential of the first configured source.
+func ResolveCredentialChain(chain []CredentialSource, getenv func(string) string) (*AzureCredential, error) {
+	for _, source := range chain {
+		credential, err := source(getenv)
+		if err != nil {
+			return nil, err
+		}
+		if credential != nil {
+			return credential, nil
+		}
+	}
+	return nil, fmt.Errorf("no Azure authentication method is configured, set one of:\n" +
+		"  client secret: TF_VAR_client_id and TF_VAR_client_secret (or ARM_/AZURE_ equivalents)\n" +
+		"  client certificate: ARM_CLIENT_ID and ARM_CLIENT_CERTIFICATE_PATH\n" +
+		"  OIDC federated token: ARM_CLIENT_ID and ARM_OIDC_TOKEN_FILE_PATH or AZURE_FEDERATED_TOKEN_FILE\n" +
+		"  managed identity: ARM_USE_MSI=true or TF_VAR_use_msi=true\n" +
+		"  Azure CLI: run 'az login'\n" +
+		"along with TF_VAR_tenant_id and TF_VAR_subscription_id for all but the Azure CLI")
+}
+
+// ClientSecretCredential authenticates as a service principal with a client secret.
+func ClientSecretCredential(getenv func(string) string) (*AzureCredential, error) {
+	secret := firstEnv(getenv, "TF_VAR_client_secret", "ARM_CLIENT_SECRET", "AZURE_CLIENT_SECRET")
+	if secret == "" {
+		return nil, nil
+	}
+	credential, err := newCredential(ClientSecretMethod, getenv, true)
+	if err != nil {
+		return nil, err
+	}
+	credential.setEnv(secret, "TF_VAR_client_secret", "ARM_CLIENT_SECRET", "AZURE_CLIENT_SECRET")
+	return credential, nil
+}
+
+// ClientCertificateCredential authenticates as a service principal with a client certificate.
+func ClientCertificateCredential(getenv func(string) string) (*AzureCredential, error) {
+	path := firstEnv(getenv, "ARM_CLIENT_CERTIFICATE_PATH", "AZURE_CLIENT_CERTIFICATE_PATH", "AZURE_CERTIFICATE_PATH")
+	if path == "" {
+		return nil, nil
+	}
+	credential, err := newCredential(ClientCertificateMethod, getenv, true)
+	if err != nil {
+		return nil, err
+	}
+	credential.setEnv(path, "ARM_CLIENT_CERTIFICATE_PATH", "AZURE_CERTIFICATE_PATH")
+	password := firstEnv(getenv, "ARM_CLIENT_CERTIFICATE_PASSWORD", "AZURE_CLIENT_CERTIFICATE_PASSWORD", "AZURE_CERTIFICATE_PASSWORD")
+	credential.setEnv(password, "ARM_CLIENT_CERTIFICATE_PASSWORD", "AZURE_CERTIFICATE_PASSWORD")
+	credential.setEnv("", "TF_VAR_client_secret")
+	return credential, nil
+}
+
+// OIDCCredential authenticates as a service principal or workload identity with a federated token, e.g. in CI.
+// The terratest Azure clients cannot use a federated token, so the Azure CLI is signed in with it and the
+// clients use the Azure CLI.
+func OIDCCredential(getenv func(string) string) (*AzureCredential, error) {
+	tokenFile := firstEnv(getenv, "ARM_OIDC_TOKEN_FILE_PATH", "AZURE_FEDERATED_TOKEN_FILE")
+	if tokenFile == "" {
+		return nil, nil
+	}
+	credential, err := newCredential(OIDCMethod, getenv, true)
+	if err != nil {
+		return nil, err
+	}
+	credential.setEnv("true", "ARM_USE_OIDC")
+	credential.setEnv(tokenFile, "ARM_OIDC_TOKEN_FILE_PATH")
+	credential.setEnv("", "TF_VAR_client_secret")
+	credential.Unset = []string{"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET"}
+	delete(credential.Env, "AZURE_CLIENT_ID")
+	credential.Setup = func() error {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return fmt.Errorf("reading federated token: %w", err)
+		}
+		return azLogin("--service-principal", "--username", credential.ClientID, "--tenant", credential.TenantID,
+			"--federated-token", strings.TrimSpace(string(token)))
+	}
+	return credential, nil
+}
+
+// ManagedIdentityCredential authenticates with the managed identity of the Azure VM or container running the
+// tests. Set ARM_CLIENT_ID to use a user assigned identity.
+func ManagedIdentityCredential(getenv func(string) string) (*AzureCredential, error) {
+	if !strings.EqualFold(firstEnv(getenv, "ARM_USE_MSI", "TF_VAR_use_msi"), "true") {
+		return nil, nil
+	}
+	credential, err := newCredential(ManagedIdentityMethod, getenv, false)
+	if err != nil {
+		return nil, err
+	}
+	credential.setEnv("true", "ARM_USE_MSI", "TF_VAR_use_msi")
+	credential.setEnv("", "TF_VAR_client_secret")
+	credential.Unset = []string{"AZURE_CLIENT_SECRET", "ARM_CLIENT_SECRET"}
+	// An empty AZURE_CLIENT_ID makes the terratest Azure clients use the system assigned identity
+	credential.setEnv(credential.ClientID, "AZURE_CLIENT_ID")
+	return credential, nil
+}
+
+// AzureCLICredential authenticates with the account the Azure CLI is signed in to. The tenant and subscription
+// default to the ones of the CLI account.
+func AzureCLICredential(getenv func(string) string) (*AzureCredential, error) {
+	out, err := azAccountShow()
+	if err != nil {
+		return nil, nil
+	}
+	var account struct {
+		ID       string `json:"id"`
+		TenantID string `json:"tenantId"`
+	}
+	if err := json.Unmarshal(out, &account); err != nil {
+		return nil, fmt.Errorf("parsing 'az account show' output: %w", err)
+	}
+
+	credential := &AzureCredential{
+		Method:         AzureCLIMethod,
+		TenantID:       valueOr(firstEnv(getenv, "TF_VAR_tenant_id", "ARM_TENANT_ID", "AZURE_TENANT_ID"), account.TenantID),
+		SubscriptionID: valueOr(firstEnv(getenv, "TF_VAR_subscription_id", "ARM_SUBSCRIPTION_ID", "AZURE_SUBSCRIPTION_ID"), account.ID),
+		Env:            map[string]string{},
+		// Without a client ID the providers and the terratest Azure clients use the Azure CLI
+		Unset: []string{"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "ARM_CLIENT_ID", "ARM_CLIENT_SECRET"},
+	}
+	credential.setCommonEnv()
+	credential.setEnv("true", "ARM_USE_CLI")
+	credential.setEnv("", "TF_VAR_client_id", "TF_VAR_client_secret")
+	return credential, nil
+}
+
+// newCredential reads the tenant, subscription and client ID common to all methods but the Azure CLI.
+func newCredential(method string, getenv func(string) string, requireClientID bool) (*AzureCredential, error) {
+	credential := &AzureCredential{
+		Method:         method,
+		TenantID:       firstEnv(getenv, "TF_VAR_tenant_id", "ARM_TENANT_ID", "AZURE_TENANT_ID"),
+		SubscriptionID: firstEnv(getenv, "TF_VAR_subscription_id", "ARM_SUBSCRIPTION_ID", "AZURE_SUBSCRIPTION_ID"),
+		ClientID:       firstEnv(getenv, "TF_VAR_client_id", "ARM_CLIENT_ID", "AZURE_CLIENT_ID"),
+		Env:            map[string]string{},
+	}
+	var missing []string
+	if credential.TenantID == "" {
+		missing = append(missing, "TF_VAR_tenant_id")
+	}
+	if credential.SubscriptionID == "" {
+		missing = append(missing, "TF_VAR_subscription_id")
+	}
+	if requireClientID && credential.ClientID == "" {
+		missing = append(missing, "TF_VAR_client_id")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Azure %s authentication also needs %s", method, strings.Join(missing, ", "))
+	}
+	credential.setCommonEnv()
+	credential.setEnv(credential.ClientID, "TF_VAR_client_id", "ARM_CLIENT_ID", "AZURE_CLIENT_ID")
+	return credential, nil
+}
+
+func (c *AzureCredential) setCommonEnv() {
+	c.setEnv(c.TenantID, "TF_VAR_tenant_id", "ARM_TENANT_ID", "AZURE_TENANT_ID")
+	c.setEnv(c.SubscriptionID, "TF_VAR_subscription_id", "ARM_SUBSCRIPTION_ID", "AZURE_SUBSCRIPTION_ID")
+}
+
+func (c *AzureCredential) setEnv(value string, names ...string) {
+	for _, name := range names {
+		c.Env[name] = value
+	}
+}
+
+// firstEnv returns the first non-empty value of the environment variables.
+func firstEnv(getenv func(string) string, names ...string) string {
+	for _, name := range names {
+		if value := getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,151 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapEnv(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
+
+// TestResolveCredentialChain verifies the method chosen for each environment and the variables set for it.
+func TestResolveCredentialChain(t *testing.T) {
+	azAccountShow = func() ([]byte, error) {
+		return []byte(`{"id": "cli-subscription", "tenantId": "cli-tenant"}`), nil
+	}
+	defer func() { azAccountShow = func() ([]byte, error) { return nil, errors.New("not logged in") } }()
+
+	common := map[string]string{"TF_VAR_tenant_id": "tenant", "TF_VAR_subscription_id": "subscription"}
+	with := func(values map[string]string) map[string]string {
+		merged := map[string]string{}
+		for k, v := range common {
+			merged[k] = v
+		}
+		for k, v := range values {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	tests := map[string]struct {
+		env      map[string]string
+		method   string
+		expected map[string]string
+		unset    []string
+	}{
+		"clientSecret": {
+			env:      with(map[string]string{"TF_VAR_client_id": "client", "TF_VAR_client_secret": "secret"}),
+			method:   ClientSecretMethod,
+			expected: map[string]string{"ARM_CLIENT_ID": "client", "AZURE_CLIENT_SECRET": "secret", "ARM_TENANT_ID": "tenant"},
+		},
+		"clientCertificate": {
+			env:      with(map[string]string{"ARM_CLIENT_ID": "client", "ARM_CLIENT_CERTIFICATE_PATH": "/certs/sp.pfx"}),
+			method:   ClientCertificateMethod,
+			expected: map[string]string{"TF_VAR_client_id": "client", "AZURE_CERTIFICATE_PATH": "/certs/sp.pfx", "TF_VAR_client_secret": ""},
+		},
+		"oidc": {
+			env:      with(map[string]string{"AZURE_CLIENT_ID": "client", "AZURE_FEDERATED_TOKEN_FILE": "/var/run/token"}),
+			method:   OIDCMethod,
+			expected: map[string]string{"ARM_USE_OIDC": "true", "ARM_OIDC_TOKEN_FILE_PATH": "/var/run/token", "ARM_CLIENT_ID": "client"},
+			unset:    []string{"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET"},
+		},
+		"managedIdentity": {
+			env:      with(map[string]string{"ARM_USE_MSI": "true"}),
+			method:   ManagedIdentityMethod,
+			expected: map[string]string{"TF_VAR_use_msi": "true", "AZURE_CLIENT_ID": "", "AZURE_SUBSCRIPTION_ID": "subscription"},
+			unset:    []string{"AZURE_CLIENT_SECRET", "ARM_CLIENT_SECRET"},
+		},
+		"azureCLI": {
+			env:      map[string]string{},
+			method:   AzureCLIMethod,
+			expected: map[string]string{"ARM_USE_CLI": "true", "TF_VAR_tenant_id": "cli-tenant", "TF_VAR_subscription_id": "cli-subscription"},
+			unset:    []string{"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "ARM_CLIENT_ID", "ARM_CLIENT_SECRET"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			credential, err := ResolveCredentialChain(DefaultCredentialChain, mapEnv(tc.env))
+			require.NoError(t, err)
+			assert.Equal(t, tc.method, credential.Method)
+			for key, value := range tc.expected {
+				actual, ok := credential.Env[key]
+				assert.True(t, ok, "%s is not set", key)
+				assert.Equal(t, value, actual, key)
+			}
+			assert.Equal(t, tc.unset, credential.Unset)
+		})
+	}
+}
+
+// TestResolveCredentialChainErrors verifies the diagnostics for a partly configured method and for no method.
+func TestResolveCredentialChainErrors(t *testing.T) {
+	azAccountShow = func() ([]byte, error) { return nil, errors.New("not logged in") }
+
+	_, err := ResolveCredentialChain(DefaultCredentialChain, mapEnv(map[string]string{"TF_VAR_client_secret": "secret"}))
+	assert.EqualError(t, err, "Azure client secret authentication also needs TF_VAR_tenant_id, TF_VAR_subscription_id, TF_VAR_client_id")
+
+	_, err = ResolveCredentialChain(DefaultCredentialChain, mapEnv(map[string]string{}))
+	assert.ErrorContains(t, err, "no Azure authentication method is configured")
+}
+
+// TestOIDCCredentialSetup verifies that the Azure CLI is signed in with the federated token.
+func TestOIDCCredentialSetup(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token\n"), 0600))
+	var loginArgs []string
+	azLogin = func(args ...string) error {
+		loginArgs = args
+		return nil
+	}
+
+	credential, err := OIDCCredential(mapEnv(map[string]string{
+		"ARM_CLIENT_ID": "client", "ARM_TENANT_ID": "tenant", "ARM_SUBSCRIPTION_ID": "subscription",
+		"ARM_OIDC_TOKEN_FILE_PATH": tokenFile,
+	}))
+	require.NoError(t, err)
+	require.NoError(t, credential.Setup())
+	assert.Equal(t, []string{"--service-principal", "--username", "client", "--tenant", "tenant",
+		"--federated-token", "federated-token"}, loginArgs)
+}
+
+// TestApplyCredential verifies that the environment of a credential is set, its Unset variables are removed and
+// its setup runs, and that it is described without its secret.
+func TestApplyCredential(t *testing.T) {
+	credential, err := ClientSecretCredential(mapEnv(map[string]string{
+		"TF_VAR_client_id": "client", "TF_VAR_client_secret": "client-secret", "TF_VAR_tenant_id": "tenant",
+		"TF_VAR_subscription_id": "subscription",
+	}))
+	require.NoError(t, err)
+	credential.Unset = []string{"ARM_USE_MSI"}
+	setUp := false
+	credential.Setup = func() error {
+		setUp = true
+		return nil
+	}
+
+	env := map[string]string{"ARM_USE_MSI": "true"}
+	require.NoError(t, credential.Apply(func(name string, value string) error {
+		env[name] = value
+		return nil
+	}, func(name string) error {
+		delete(env, name)
+		return nil
+	}))
+	assert.Equal(t, "client-secret", env["ARM_CLIENT_SECRET"])
+	assert.Equal(t, "subscription", env["AZURE_SUBSCRIPTION_ID"])
+	assert.NotContains(t, env, "ARM_USE_MSI")
+	assert.True(t, setUp)
+	assert.Equal(t, "client secret (tenant tenant, subscription subscription, client client)", credential.String())
+}
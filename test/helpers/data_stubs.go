@@ -0,0 +1,397 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// dataStubsFile is the override file that WriteDataStubs writes to the directory of each module with stubs.
+const dataStubsFile = "data_stubs_override.tf"
+
+// A DataStub replaces a data source of the root module or of a local module with fixed values, so that a plan
+// that reads existing Azure resources, such as the network of sample-input-byo.tfvars, runs without them.
+type DataStub struct {
+	// Module is the directory of the module of the data source relative to the root module, e.g.
+	// modules/azurerm_vnet, or empty for the root module
+	Module string
+	// Address is the type and name of the data source, e.g. azurerm_resource_group.aks_rg
+	Address string
+	// Instances are the attributes of each instance of the data source, keyed by the index of a data source with
+	// count, e.g. "0", or by the key of a data source with for_each. A data source with neither has the key "".
+	Instances map[string]map[string]interface{}
+}
+
+// WriteDataStubs writes an override file to the directory of each module of the stubs in repository, a copy of
+// the repository. Terraform cannot change the type of a block in an override file, so the override file sets
+// the count of each stubbed data source to 0, or its for_each to {}, and overrides each local value, output and
+// argument of the module that references the data source with its expression, with the reference replaced by
+// the values of the stub. A reference in a nested block cannot be overridden on its own and is an error.
+func WriteDataStubs(repository string, stubs []DataStub) error {
+	modules := make(map[string][]DataStub)
+	for _, stub := range stubs {
+		modules[stub.Module] = append(modules[stub.Module], stub)
+	}
+	for module, moduleStubs := range modules {
+		dir := filepath.Join(repository, module)
+		override, err := DataStubOverride(dir, moduleStubs)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, dataStubsFile), override, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DataStubOverride returns the override file of the stubs of the data sources of the module in dir, see
+// WriteDataStubs.
+func DataStubOverride(dir string, stubs []DataStub) ([]byte, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	var bodies []*hclsyntax.Body
+	sources := make(map[string][]byte)
+	for _, file := range files {
+		if base := filepath.Base(file); base == "override.tf" || strings.HasSuffix(base, "_override.tf") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, diags := hclsyntax.ParseConfig(src, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		bodies = append(bodies, parsed.Body.(*hclsyntax.Body))
+		sources[file] = src
+	}
+
+	// The values of the stubs, as an HCL expression
+	values := make(map[string]string)
+	var override strings.Builder
+	override.WriteString("# Generated by helpers.WriteDataStubs, do not edit\n")
+	for _, stub := range stubs {
+		block := findDataBlock(bodies, stub.Address)
+		if block == nil {
+			return nil, fmt.Errorf("%s has no data source %s", dir, stub.Address)
+		}
+		value, meta, err := stub.value(block)
+		if err != nil {
+			return nil, err
+		}
+		values[stub.Address] = value
+		fmt.Fprintf(&override, "\ndata %q %q {\n  %s\n}\n", block.Labels[0], block.Labels[1], meta)
+	}
+
+	for _, body := range bodies {
+		for _, block := range body.Blocks {
+			if block.Type == "data" && values[block.Labels[0]+"."+block.Labels[1]] != "" {
+				continue
+			}
+			if err := checkNestedBlocks(block.Body, values); err != nil {
+				return nil, err
+			}
+			var arguments strings.Builder
+			for _, name := range attributeNames(block.Body.Attributes) {
+				attribute := block.Body.Attributes[name]
+				if name == "depends_on" {
+					continue
+				}
+				if expr, ok := replaceDataReferences(sources[attribute.SrcRange.Filename], attribute.Expr, values); ok {
+					fmt.Fprintf(&arguments, "  %s = %s\n", name, expr)
+				}
+			}
+			if arguments.Len() > 0 {
+				fmt.Fprintf(&override, "\n%s {\n%s}\n", blockHeader(block), arguments.String())
+			}
+		}
+	}
+	return hclwrite.Format([]byte(override.String())), nil
+}
+
+// value returns the values of the instances of the stub as an HCL expression, a tuple for a data source with
+// count, an object for a data source with for_each, and the argument that removes the instances of the data source.
+func (s DataStub) value(block *hclsyntax.Block) (string, string, error) {
+	var value interface{}
+	meta := "count = 0"
+	switch {
+	case block.Body.Attributes["for_each"] != nil:
+		value, meta = s.Instances, "for_each = {}"
+	case block.Body.Attributes["count"] != nil:
+		instances := make([]map[string]interface{}, len(s.Instances))
+		for key, attributes := range s.Instances {
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(instances) {
+				return "", "", fmt.Errorf("the instances of the stub of %s, which has count, must be numbered from 0, got %q", s.Address, key)
+			}
+			instances[index] = attributes
+		}
+		value = instances
+	default:
+		attributes, ok := s.Instances[""]
+		if !ok || len(s.Instances) != 1 {
+			return "", "", fmt.Errorf("the stub of %s, which has no count or for_each, must have the single instance \"\"", s.Address)
+		}
+		value = attributes
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", "", err
+	}
+	quoted := strings.NewReplacer("${", "$${", "%{", "%%{").Replace(strconv.Quote(string(data)))
+	return "jsondecode(" + quoted + ")", meta, nil
+}
+
+// findDataBlock returns the data block of the type and name of the address.
+func findDataBlock(bodies []*hclsyntax.Body, address string) *hclsyntax.Block {
+	for _, body := range bodies {
+		for _, block := range body.Blocks {
+			if block.Type == "data" && len(block.Labels) == 2 && block.Labels[0]+"."+block.Labels[1] == address {
+				return block
+			}
+		}
+	}
+	return nil
+}
+
+// replaceDataReferences returns the source of the expression with the references to the stubbed data sources
+// replaced by their values, and whether it has any.
+func replaceDataReferences(src []byte, expr hclsyntax.Expression, values map[string]string) (string, bool) {
+	type replacement struct {
+		start, end int
+		value      string
+	}
+	var replacements []replacement
+	hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		if traversal, ok := node.(*hclsyntax.ScopeTraversalExpr); ok {
+			if address, ok := dataAddress(traversal.Traversal); ok && values[address] != "" {
+				replacements = append(replacements, replacement{
+					start: traversal.Traversal[0].SourceRange().Start.Byte,
+					end:   traversal.Traversal[2].SourceRange().End.Byte,
+					value: values[address],
+				})
+			}
+		}
+		return nil
+	})
+	if len(replacements) == 0 {
+		return "", false
+	}
+
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start > replacements[j].start })
+	result := string(expr.Range().SliceBytes(src))
+	for _, r := range replacements {
+		start, end := r.start-expr.Range().Start.Byte, r.end-expr.Range().Start.Byte
+		result = result[:start] + r.value + result[end:]
+	}
+	return result, true
+}
+
+// checkNestedBlocks returns an error for a reference to a stubbed data source in a nested block.
+func checkNestedBlocks(body *hclsyntax.Body, values map[string]string) error {
+	for _, block := range body.Blocks {
+		var err error
+		hclsyntax.VisitAll(block.Body, func(node hclsyntax.Node) hcl.Diagnostics {
+			if traversal, ok := node.(*hclsyntax.ScopeTraversalExpr); ok && err == nil {
+				if address, ok := dataAddress(traversal.Traversal); ok && values[address] != "" {
+					err = fmt.Errorf("%s: data.%s is referenced in a nested %s block, which cannot be stubbed",
+						traversal.SrcRange, address, block.Type)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataAddress returns the type and name of the data source of a traversal starting with data.
+func dataAddress(traversal hcl.Traversal) (string, bool) {
+	if traversal.RootName() != "data" || len(traversal) < 3 {
+		return "", false
+	}
+	dataType, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	name, ok := traversal[2].(hcl.TraverseAttr)
+	return dataType.Name + "." + name.Name, ok
+}
+
+// blockHeader returns the type and labels of a block, as they open it.
+func blockHeader(block *hclsyntax.Block) string {
+	header := block.Type
+	for _, label := range block.Labels {
+		header += " " + strconv.Quote(label)
+	}
+	return header
+}
+
+func attributeNames(attributes hclsyntax.Attributes) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stubSubscription is the subscription of the resources of BYONetworkStubs.
+const stubSubscription = "/subscriptions/00000000-0000-0000-0000-000000000000"
+
+// byoSubnetPrefixes are the address prefixes of the subnets of BYONetworkStubs, those of the default of the
+// subnets variable. A subnet that is not listed gets a /24 prefix after them.
+var byoSubnetPrefixes = map[string]string{
+	"aks":        "192.168.0.0/23",
+	"misc":       "192.168.2.0/24",
+	"netapp":     "192.168.3.0/24",
+	"postgresql": "192.168.4.0/24",
+}
+
+// BYONetworkStubs returns the stubs of the data sources that the root module reads for the existing resource
+// groups, virtual network, subnets, network security group and user assigned identity named by the variables,
+// such as those of sample-input-byo.tfvars. Only the data sources of the variables that are set are stubbed,
+// and their IDs are in a subscription that does not exist.
+func BYONetworkStubs(variables map[string]interface{}) []DataStub {
+	var stubs []DataStub
+	location, _ := variables["location"].(string)
+	resourceGroup, _ := variables["resource_group_name"].(string)
+	if resourceGroup != "" {
+		stubs = append(stubs, DataStub{Address: "azurerm_resource_group.aks_rg", Instances: map[string]map[string]interface{}{
+			"0": resourceGroupStub(resourceGroup, location),
+		}})
+	}
+	networkResourceGroup, _ := variables["vnet_resource_group_name"].(string)
+	if networkResourceGroup != "" {
+		stubs = append(stubs, DataStub{Address: "azurerm_resource_group.network_rg", Instances: map[string]map[string]interface{}{
+			"0": resourceGroupStub(networkResourceGroup, location),
+		}})
+	} else if resourceGroup != "" {
+		networkResourceGroup = resourceGroup
+	} else {
+		prefix, _ := variables["prefix"].(string)
+		networkResourceGroup = prefix + "-rg"
+	}
+	networkResourceGroupID := stubSubscription + "/resourceGroups/" + networkResourceGroup
+
+	if name, _ := variables["nsg_name"].(string); name != "" {
+		stubs = append(stubs, DataStub{Address: "azurerm_network_security_group.nsg", Instances: map[string]map[string]interface{}{
+			"0": {
+				"id":                  networkResourceGroupID + "/providers/Microsoft.Network/networkSecurityGroups/" + name,
+				"name":                name,
+				"location":            location,
+				"resource_group_name": networkResourceGroup,
+				"security_rule":       []interface{}{},
+				"tags":                map[string]string{},
+			},
+		}})
+	}
+	if name, _ := variables["aks_uai_name"].(string); name != "" {
+		stubs = append(stubs, DataStub{Address: "azurerm_user_assigned_identity.uai", Instances: map[string]map[string]interface{}{
+			"0": {
+				"id":                  networkResourceGroupID + "/providers/Microsoft.ManagedIdentity/userAssignedIdentities/" + name,
+				"name":                name,
+				"location":            location,
+				"resource_group_name": networkResourceGroup,
+				"client_id":           "00000000-0000-0000-0000-000000000001",
+				"principal_id":        "00000000-0000-0000-0000-000000000002",
+				"tenant_id":           "00000000-0000-0000-0000-000000000003",
+				"tags":                map[string]string{},
+			},
+		}})
+	}
+
+	vnetName, _ := variables["vnet_name"].(string)
+	if vnetName == "" {
+		return stubs
+	}
+	vnetID := networkResourceGroupID + "/providers/Microsoft.Network/virtualNetworks/" + vnetName
+	stubs = append(stubs, DataStub{Module: "modules/azurerm_vnet", Address: "azurerm_virtual_network.vnet", Instances: map[string]map[string]interface{}{
+		"0": {
+			"id":                  vnetID,
+			"name":                vnetName,
+			"location":            location,
+			"resource_group_name": networkResourceGroup,
+			"address_space":       []string{"192.168.0.0/16"},
+			"dns_servers":         []string{},
+			"tags":                map[string]string{},
+		},
+	}})
+
+	subnets := make(map[string]map[string]interface{})
+	names := stringMap(variables["subnet_names"])
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := names[key]
+		prefix, ok := byoSubnetPrefixes[key]
+		if !ok {
+			prefix = fmt.Sprintf("192.168.%d.0/24", 5+len(subnets))
+		}
+		subnets[key] = map[string]interface{}{
+			"id":                        vnetID + "/subnets/" + value,
+			"name":                      value,
+			"virtual_network_name":      vnetName,
+			"resource_group_name":       networkResourceGroup,
+			"address_prefix":            prefix,
+			"address_prefixes":          []string{prefix},
+			"network_security_group_id": "",
+			"route_table_id":            networkResourceGroupID + "/providers/Microsoft.Network/routeTables/" + vnetName + "-rt",
+			"service_endpoints":         []string{},
+		}
+	}
+	if len(subnets) > 0 {
+		stubs = append(stubs, DataStub{Module: "modules/azurerm_vnet", Address: "azurerm_subnet.subnet", Instances: subnets})
+	}
+	return stubs
+}
+
+// resourceGroupStub returns the attributes of an existing resource group.
+func resourceGroupStub(name string, location string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         stubSubscription + "/resourceGroups/" + name,
+		"name":       name,
+		"location":   location,
+		"managed_by": "",
+		"tags":       map[string]string{},
+	}
+}
+
+// stringMap returns the entries of a map of strings of the variables, as read from a tfvars file or set by a test.
+func stringMap(value interface{}) map[string]string {
+	values := make(map[string]string)
+	switch typed := value.(type) {
+	case map[string]string:
+		for key, value := range typed {
+			values[key] = value
+		}
+	case map[string]interface{}:
+		for key, value := range typed {
+			if s, ok := value.(string); ok {
+				values[key] = s
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,142 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stubModule = `data "azurerm_resource_group" "rg" {
+  count = var.rg_name == null ? 0 : 1
+  name  = var.rg_name
+}
+
+data "azurerm_subnet" "subnet" {
+  for_each = var.subnet_names
+  name     = each.value
+}
+
+locals {
+  rg       = var.rg_name == null ? azurerm_resource_group.rg[0] : data.azurerm_resource_group.rg[0]
+  location = var.location
+}
+
+resource "azurerm_network_interface" "nic" {
+  name     = "nic"
+  location = local.rg.location
+  ip_configuration {
+    subnet_id = local.subnet_id
+  }
+  depends_on = [data.azurerm_subnet.subnet]
+}
+
+output "subnet_ids" {
+  value = { for k, v in data.azurerm_subnet.subnet : k => v.id }
+}
+`
+
+// TestDataStubOverride verifies that the override file removes the stubbed data sources and overrides the local
+// values, arguments and outputs that reference them, and only those.
+func TestDataStubOverride(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(stubModule), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "previous_override.tf"), []byte("locals {\n  rg = data.azurerm_resource_group.rg[0]\n}\n"), 0644))
+
+	stubs := []DataStub{
+		{Address: "azurerm_resource_group.rg", Instances: map[string]map[string]interface{}{"0": {"name": "test-rg", "location": "eastus"}}},
+		{Address: "azurerm_subnet.subnet", Instances: map[string]map[string]interface{}{"aks": {"id": "${id}"}}},
+	}
+	require.NoError(t, WriteDataStubs(dir, stubs))
+	override, err := os.ReadFile(filepath.Join(dir, dataStubsFile))
+	require.NoError(t, err)
+	assert.Equal(t, `# Generated by helpers.WriteDataStubs, do not edit
+
+data "azurerm_resource_group" "rg" {
+  count = 0
+}
+
+data "azurerm_subnet" "subnet" {
+  for_each = {}
+}
+
+locals {
+  rg = var.rg_name == null ? azurerm_resource_group.rg[0] : jsondecode("[{\"location\":\"eastus\",\"name\":\"test-rg\"}]")[0]
+}
+
+output "subnet_ids" {
+  value = { for k, v in jsondecode("{\"aks\":{\"id\":\"$${id}\"}}") : k => v.id }
+}
+`, string(override))
+}
+
+// TestDataStubOverrideErrors verifies that a data source that does not exist, instances that do not match the
+// count of the data source and a reference in a nested block are errors.
+func TestDataStubOverrideErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(stubModule), 0644))
+
+	_, err := DataStubOverride(dir, []DataStub{{Address: "azurerm_virtual_network.vnet"}})
+	assert.ErrorContains(t, err, "has no data source azurerm_virtual_network.vnet")
+
+	_, err = DataStubOverride(dir, []DataStub{{Address: "azurerm_resource_group.rg", Instances: map[string]map[string]interface{}{"1": {}}}})
+	assert.ErrorContains(t, err, `must be numbered from 0, got "1"`)
+
+	nested := stubModule + `
+resource "azurerm_network_interface" "nested" {
+  ip_configuration {
+    subnet_id = data.azurerm_subnet.subnet["aks"].id
+  }
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(nested), 0644))
+	_, err = DataStubOverride(dir, []DataStub{{Address: "azurerm_subnet.subnet", Instances: map[string]map[string]interface{}{}}})
+	assert.ErrorContains(t, err, "data.azurerm_subnet.subnet is referenced in a nested ip_configuration block")
+}
+
+// TestBYONetworkStubs verifies that the stubs of the existing network of the BYO variables replace every
+// reference to the data sources of the root module and of the vnet module.
+func TestBYONetworkStubs(t *testing.T) {
+	variables := map[string]interface{}{
+		"prefix":                   "byo",
+		"location":                 "eastus",
+		"resource_group_name":      "byo-rg",
+		"vnet_resource_group_name": "byo-network-rg",
+		"vnet_name":                "byo-vnet",
+		"subnet_names":             map[string]interface{}{"aks": "byo-aks-subnet", "misc": "byo-misc-subnet"},
+		"nsg_name":                 "byo-nsg",
+		"aks_uai_name":             "byo-uai",
+	}
+	stubs := BYONetworkStubs(variables)
+
+	var addresses []string
+	modules := make(map[string][]DataStub)
+	for _, stub := range stubs {
+		addresses = append(addresses, filepath.Join(stub.Module, stub.Address))
+		modules[stub.Module] = append(modules[stub.Module], stub)
+	}
+	assert.Equal(t, []string{
+		"azurerm_resource_group.aks_rg",
+		"azurerm_resource_group.network_rg",
+		"azurerm_network_security_group.nsg",
+		"azurerm_user_assigned_identity.uai",
+		"modules/azurerm_vnet/azurerm_virtual_network.vnet",
+		"modules/azurerm_vnet/azurerm_subnet.subnet",
+	}, addresses)
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/byo-network-rg/providers/Microsoft.Network/virtualNetworks/byo-vnet/subnets/byo-misc-subnet",
+		stubs[5].Instances["misc"]["id"])
+	assert.Equal(t, []string{"192.168.2.0/24"}, stubs[5].Instances["misc"]["address_prefixes"])
+
+	for module, moduleStubs := range modules {
+		override, err := DataStubOverride(filepath.Join("../..", module), moduleStubs)
+		require.NoError(t, err)
+		assert.NotContains(t, string(override), "data.azurerm", "%s still references a stubbed data source", module)
+	}
+
+	assert.Empty(t, BYONetworkStubs(map[string]interface{}{"prefix": "default", "location": "eastus"}))
+}
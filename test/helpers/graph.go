@@ -0,0 +1,219 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// dotID matches a quoted ID of the DOT language, with escaped quotes
+	dotID = `"((?:[^"\\]|\\.)*)"`
+	// dotEdge matches an edge statement, "from" -> "to"
+	dotEdge = regexp.MustCompile(`^\s*` + dotID + `\s*->\s*` + dotID)
+	// dotNode matches a node statement, "node" with optional attributes
+	dotNode = regexp.MustCompile(`^\s*` + dotID + `\s*(?:\[.*\])?\s*;?\s*$`)
+	// instanceKey matches the count index or for_each key of an address, e.g. [0] or ["cas"]
+	instanceKey = regexp.MustCompile(`\[(?:\d+|\*|"(?:[^"\\]|\\.)*")\]`)
+)
+
+// Graph is the dependency graph of a configuration, as drawn by terraform graph. The nodes are the addresses
+// of the resources, data sources, module calls, variables, locals and outputs of the configuration, without
+// instance keys, so the instances of a resource or module call are one node. Terraform destroys the resources in
+// the reverse order of their dependencies.
+type Graph struct {
+	// dependencies holds the nodes each node depends on
+	dependencies map[string]map[string]bool
+}
+
+// ParseGraph parses the DOT output of terraform graph or tofu graph. Both the resource graph of terraform 1.7
+// and later, and the full graph of the earlier versions and of -type or -plan, are read. The "[root] " prefix,
+// the " (expand)" suffix and the instance keys of the node names are removed. Other suffixes, such as
+// " (close)" or " (destroy)", are kept, so those nodes match no address.
+func ParseGraph(dot string) (*Graph, error) {
+	if !strings.Contains(dot, "digraph") {
+		return nil, fmt.Errorf("the output of terraform graph is not a DOT digraph: %q", dot)
+	}
+	graph := &Graph{dependencies: make(map[string]map[string]bool)}
+	for _, line := range strings.Split(dot, "\n") {
+		if match := dotEdge.FindStringSubmatch(line); match != nil {
+			from, to := graphNode(match[1]), graphNode(match[2])
+			graph.addNode(from)
+			graph.addNode(to)
+			// the instances of a node depend on its expansion, which is the same node once the keys are removed
+			if from != to {
+				graph.dependencies[from][to] = true
+			}
+			continue
+		}
+		if match := dotNode.FindStringSubmatch(line); match != nil {
+			graph.addNode(graphNode(match[1]))
+		}
+	}
+	if len(graph.dependencies) == 0 {
+		return nil, fmt.Errorf("the graph has no nodes")
+	}
+	return graph, nil
+}
+
+func (g *Graph) addNode(node string) {
+	if g.dependencies[node] == nil {
+		g.dependencies[node] = make(map[string]bool)
+	}
+}
+
+// graphNode returns the address of a node of the DOT output of terraform graph.
+func graphNode(id string) string {
+	node := strings.ReplaceAll(id, `\"`, `"`)
+	node = strings.TrimSuffix(strings.TrimPrefix(node, "[root] "), " (expand)")
+	if strings.HasPrefix(node, "provider[") {
+		return node
+	}
+	return instanceKey.ReplaceAllString(node, "")
+}
+
+// Nodes returns the nodes of the graph, sorted.
+func (g *Graph) Nodes() []string {
+	nodes := make([]string, 0, len(g.dependencies))
+	for node := range g.dependencies {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Resources returns the resources and data sources of the graph at the address, or in the module at the
+// address, e.g. module.node_pools[*] or azurerm_network_security_group.nsg. Instance keys are ignored.
+func (g *Graph) Resources(address string) []string {
+	address = instanceKey.ReplaceAllString(address, "")
+	var resources []string
+	for _, node := range g.Nodes() {
+		if isResourceNode(node) && hasAddressPrefix(node, address) {
+			resources = append(resources, node)
+		}
+	}
+	return resources
+}
+
+// isResourceNode reports whether the node is the address of a resource or data source, not of a module call,
+// variable, local, output or provider.
+func isResourceNode(node string) bool {
+	parts := strings.Split(node, ".")
+	for len(parts) > 2 && parts[0] == "module" {
+		parts = parts[2:]
+	}
+	if len(parts) == 3 && parts[0] == "data" {
+		parts = parts[1:]
+	}
+	if len(parts) != 2 || strings.ContainsAny(node, " []") {
+		return false
+	}
+	switch parts[0] {
+	case "module", "var", "local", "output", "provider", "meta", "data":
+		return false
+	}
+	return true
+}
+
+// HasEdge reports whether the graph has an edge from the node at the address from to the node at the address
+// to, that is from depends directly on to. Instance keys are ignored.
+func (g *Graph) HasEdge(from string, to string) bool {
+	return g.dependencies[instanceKey.ReplaceAllString(from, "")][instanceKey.ReplaceAllString(to, "")]
+}
+
+// Path returns the shortest path of dependencies from the node from to a node at the address to, or in the
+// module at the address to, or nil if from does not depend on it.
+func (g *Graph) Path(from string, to string) []string {
+	to = instanceKey.ReplaceAllString(to, "")
+	from = instanceKey.ReplaceAllString(from, "")
+	if _, ok := g.dependencies[from]; !ok {
+		return nil
+	}
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, dependency := range sortedKeys(g.dependencies[node]) {
+			if _, seen := previous[dependency]; seen {
+				continue
+			}
+			previous[dependency] = node
+			if hasAddressPrefix(dependency, to) {
+				path := []string{dependency}
+				for step := node; step != ""; step = previous[step] {
+					path = append([]string{step}, path...)
+				}
+				return path
+			}
+			queue = append(queue, dependency)
+		}
+	}
+	return nil
+}
+
+// MissingDependencies returns the resources at the address from, or in the module at the address from, that do
+// not depend on a node at the address to, or in the module at the address to, directly or through other nodes.
+func (g *Graph) MissingDependencies(from string, to string) []string {
+	var missing []string
+	for _, resource := range g.Resources(from) {
+		if g.Path(resource, to) == nil {
+			missing = append(missing, resource)
+		}
+	}
+	return missing
+}
+
+func sortedKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DrawGraph returns the graph of the plan file of the terraform options, drawn by terraform graph -plan. The graph
+// only has the resources of the plan, so the branches of the configuration that the variables do not use are
+// left out.
+func DrawGraph(t *testing.T, terraformOptions *terraform.Options) (*Graph, error) {
+	dot, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "graph", "-plan="+terraformOptions.PlanFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGraph(dot)
+}
+
+// GetGraph returns the graph of the cached plan of the variables, see GetPlanFromCache. The graph is drawn from
+// the plan file when the plan is cached, so it does not plan the variables again. Terraform does not plan a
+// configuration with a dependency cycle, in which case the plan fails with the "Cycle:" error of terraform,
+// which names the nodes of the cycle.
+func GetGraph(t *testing.T, variables map[string]interface{}) *Graph {
+	_, graph := getPlanAndGraphFromCache(t, variables)
+	require.NotNil(t, graph, "The plan of %s was cached without its graph", variables["prefix"])
+	require.NoError(t, graph.err)
+	return graph.graph
+}
+
+// AssertDependsOn asserts that each resource at the address from, or in the module at the address from, depends
+// on a node at the address to, or in the module at the address to, e.g. that module.node_pools[*] depends on
+// module.aks, so terraform destroys them first.
+func AssertDependsOn(t *testing.T, graph *Graph, from string, to string, msgAndArgs ...interface{}) bool {
+	if len(graph.Resources(from)) == 0 {
+		return assert.Fail(t, fmt.Sprintf("The graph has no resource at %s", from), msgAndArgs...)
+	}
+	if missing := graph.MissingDependencies(from, to); len(missing) > 0 {
+		return assert.Fail(t, fmt.Sprintf("%d resources do not depend on %s:\n  %s", len(missing), to,
+			strings.Join(missing, "\n  ")), msgAndArgs...)
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resourceGraph is the resource graph drawn by terraform graph since 1.7.
+const resourceGraph = `digraph G {
+  rankdir = "RL";
+  node [shape = rect, fontname = "sans-serif"];
+  "azurerm_network_security_group.nsg" [label="azurerm_network_security_group.nsg"];
+  "azurerm_resource_group.aks_rg" [label="azurerm_resource_group.aks_rg"];
+  subgraph "cluster_module.aks" {
+    label = "module.aks"
+    fontname = "sans-serif"
+    "module.aks.azurerm_kubernetes_cluster.aks" [label="azurerm_kubernetes_cluster.aks"];
+  }
+  subgraph "cluster_module.node_pools" {
+    label = "module.node_pools"
+    fontname = "sans-serif"
+    "module.node_pools.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool" [label="azurerm_kubernetes_cluster_node_pool.autoscale_node_pool"];
+    "module.node_pools.azurerm_kubernetes_cluster_node_pool.static_node_pool" [label="azurerm_kubernetes_cluster_node_pool.static_node_pool"];
+  }
+  "azurerm_network_security_group.nsg" -> "azurerm_resource_group.aks_rg";
+  "module.aks.azurerm_kubernetes_cluster.aks" -> "azurerm_resource_group.aks_rg";
+  "module.node_pools.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool" -> "module.aks.azurerm_kubernetes_cluster.aks";
+}
+`
+
+// fullGraph is the full graph drawn by tofu graph, terraform graph before 1.7 and terraform graph -plan.
+const fullGraph = `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] azurerm_network_security_group.nsg (expand)" [label = "azurerm_network_security_group.nsg", shape = "box"]
+		"[root] local.nsg (expand)" [label = "local.nsg", shape = "note"]
+		"[root] module.jump.azurerm_network_interface_security_group_association.vm_nic_sg (expand)" [label = "module.jump.azurerm_network_interface_security_group_association.vm_nic_sg", shape = "box"]
+		"[root] module.jump.var.azure_nsg_id (expand)" [label = "module.jump.var.azure_nsg_id", shape = "note"]
+		"[root] provider[\"registry.terraform.io/hashicorp/azurerm\"]" [label = "provider[\"registry.terraform.io/hashicorp/azurerm\"]", shape = "diamond"]
+		"[root] azurerm_network_security_group.nsg (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/azurerm\"]"
+		"[root] local.nsg (expand)" -> "[root] azurerm_network_security_group.nsg (expand)"
+		"[root] module.jump (close)" -> "[root] module.jump.azurerm_network_interface_security_group_association.vm_nic_sg (expand)"
+		"[root] module.jump.azurerm_network_interface_security_group_association.vm_nic_sg (expand)" -> "[root] module.jump.var.azure_nsg_id (expand)"
+		"[root] module.jump.azurerm_network_interface_security_group_association.vm_nic_sg[0]" -> "[root] module.jump.azurerm_network_interface_security_group_association.vm_nic_sg (expand)"
+		"[root] module.jump.var.azure_nsg_id (expand)" -> "[root] local.nsg (expand)"
+		"[root] module.jump.var.azure_nsg_id (expand)" -> "[root] module.jump (expand)"
+	}
+}
+`
+
+// TestParseGraph verifies that the nodes of both formats of the graph are read as addresses without instance
+// keys, and that the dependencies of a module are found through its resources.
+func TestParseGraph(t *testing.T) {
+	graph, err := ParseGraph(resourceGraph)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"module.node_pools.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool",
+		"module.node_pools.azurerm_kubernetes_cluster_node_pool.static_node_pool",
+	}, graph.Resources(`module.node_pools["cas"]`))
+	assert.True(t, graph.HasEdge("module.aks.azurerm_kubernetes_cluster.aks", "azurerm_resource_group.aks_rg[0]"))
+	assert.False(t, graph.HasEdge("module.node_pools.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool", "azurerm_resource_group.aks_rg"))
+	assert.Equal(t, []string{
+		"module.node_pools.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool",
+		"module.aks.azurerm_kubernetes_cluster.aks",
+		"azurerm_resource_group.aks_rg",
+	}, graph.Path("module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]", "azurerm_resource_group.aks_rg"))
+	assert.Equal(t, []string{"module.node_pools.azurerm_kubernetes_cluster_node_pool.static_node_pool"},
+		graph.MissingDependencies("module.node_pools[*]", "module.aks"))
+	assert.Empty(t, graph.MissingDependencies("module.aks", "azurerm_resource_group.aks_rg"))
+
+	graph, err = ParseGraph(fullGraph)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"azurerm_network_security_group.nsg", "module.jump.azurerm_network_interface_security_group_association.vm_nic_sg"},
+		graph.Resources(""))
+	assert.Contains(t, graph.Nodes(), "module.jump (close)")
+	assert.Contains(t, graph.Nodes(), `provider["registry.terraform.io/hashicorp/azurerm"]`)
+	assert.True(t, AssertDependsOn(t, graph, "module.jump[0].azurerm_network_interface_security_group_association.vm_nic_sg",
+		"azurerm_network_security_group.nsg"))
+	assert.Nil(t, graph.Path("azurerm_network_security_group.nsg", "module.jump"))
+
+	_, err = ParseGraph("Error: Cycle: azurerm_network_security_group.nsg, local.nsg")
+	assert.Error(t, err)
+}
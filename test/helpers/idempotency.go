@@ -0,0 +1,199 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// DriftAllowance allows changes to an attribute, and anything nested in it, of the resources whose address
+// matches the Address regular expression in the plan after apply.
+type DriftAllowance struct {
+	Address   string
+	Attribute string
+	// Reason documents the provider quirk that causes the change.
+	Reason string
+}
+
+// IdempotencyAllowlist holds the known provider quirks that cause changes in the plan after apply. Add an entry
+// only for a change that cannot be avoided in the configuration, and say why in Reason.
+var IdempotencyAllowlist = []DriftAllowance{
+	{
+		Address:   `^module\.aks\.azurerm_kubernetes_cluster\.aks$`,
+		Attribute: "default_node_pool[0].upgrade_settings",
+		Reason:    "AKS sets a default max_surge on node pools without upgrade_settings, which the provider then proposes to remove",
+	},
+	{
+		Address:   `^module\.node_pools\[".*"\]\.azurerm_kubernetes_cluster_node_pool\.(autoscale|static)_node_pool\[0\]$`,
+		Attribute: "upgrade_settings",
+		Reason:    "AKS sets a default max_surge on node pools without upgrade_settings, which the provider then proposes to remove",
+	},
+}
+
+// Drift is a resource that a plan after apply proposes to change, with the attributes that differ.
+type Drift struct {
+	Address    string
+	Actions    tfjson.Actions
+	Attributes []string
+}
+
+func (d Drift) String() string {
+	actions := make([]string, len(d.Actions))
+	for i, action := range d.Actions {
+		actions[i] = string(action)
+	}
+	return fmt.Sprintf("%s (%s): %s", d.Address, strings.Join(actions, ", "), strings.Join(d.Attributes, ", "))
+}
+
+// FindDrift returns the resources the plan proposes to change, ignoring the allowed attribute changes.
+func FindDrift(plan *terraform.PlanStruct, allowlist []DriftAllowance) []Drift {
+	var drifts []Drift
+	for address, change := range plan.ResourceChangesMap {
+		if change.Change == nil || change.Change.Actions.NoOp() || change.Change.Actions.Read() {
+			continue
+		}
+		attributes := diffAttributes("", change.Change.Before, change.Change.After, change.Change.AfterUnknown)
+		if change.Change.Actions.Update() {
+			attributes = filterAllowed(address, attributes, allowlist)
+			if len(attributes) == 0 {
+				continue
+			}
+		}
+		drifts = append(drifts, Drift{Address: address, Actions: change.Change.Actions, Attributes: attributes})
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Address < drifts[j].Address
+	})
+	return drifts
+}
+
+// CheckIdempotency runs a detailed-exitcode plan against the applied configuration and fails the test with the
+// drifting attributes of each resource if the plan is not empty.
+func CheckIdempotency(t *testing.T, terraformOptions *terraform.Options) {
+	options := *terraformOptions
+	options.PlanFilePath = terraformOptions.PlanFilePath + ".idempotency"
+
+	defer os.Remove(options.PlanFilePath)
+
+	// -detailed-exitcode exits with 0 for an empty plan, 2 for a plan with changes and 1 for an error
+	exitCode, err := terraform.PlanExitCodeE(t, &options)
+	if err == nil && exitCode == terraform.DefaultSuccessExitCode {
+		return
+	}
+	if err != nil || exitCode != 2 {
+		t.Errorf("Error running the plan after apply: exit code %d, %v", exitCode, err)
+		return
+	}
+
+	plan, err := terraform.ShowWithStructE(t, &options)
+	if err != nil {
+		t.Errorf("Error reading the plan after apply: %s", err)
+		return
+	}
+	drifts := FindDrift(plan, IdempotencyAllowlist)
+	if len(drifts) == 0 {
+		return
+	}
+	lines := make([]string, len(drifts))
+	for i, drift := range drifts {
+		lines[i] = "  " + drift.String()
+	}
+	t.Errorf("The plan after apply is not empty, %d resources would change:\n%s", len(drifts), strings.Join(lines, "\n"))
+}
+
+// diffAttributes returns the paths of the attributes that differ between before and after, including the
+// ones only known after apply.
+func diffAttributes(path string, before interface{}, after interface{}, unknown interface{}) []string {
+	if isUnknown(unknown) {
+		return []string{path + " (known after apply)"}
+	}
+
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if beforeIsMap && afterIsMap {
+		unknownMap, _ := unknown.(map[string]interface{})
+		keys := make(map[string]bool)
+		for key := range beforeMap {
+			keys[key] = true
+		}
+		for key := range afterMap {
+			keys[key] = true
+		}
+		for key := range unknownMap {
+			keys[key] = true
+		}
+		var paths []string
+		for key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			paths = append(paths, diffAttributes(childPath, beforeMap[key], afterMap[key], unknownMap[key])...)
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+	if beforeIsList && afterIsList && len(beforeList) == len(afterList) {
+		unknownList, _ := unknown.([]interface{})
+		var paths []string
+		for i := range beforeList {
+			var childUnknown interface{}
+			if i < len(unknownList) {
+				childUnknown = unknownList[i]
+			}
+			paths = append(paths, diffAttributes(fmt.Sprintf("%s[%d]", path, i), beforeList[i], afterList[i], childUnknown)...)
+		}
+		return paths
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	if path == "" {
+		path = "(resource)"
+	}
+	return []string{path}
+}
+
+// isUnknown reports whether an after_unknown value marks the whole value as unknown.
+func isUnknown(unknown interface{}) bool {
+	value, ok := unknown.(bool)
+	return ok && value
+}
+
+func filterAllowed(address string, attributes []string, allowlist []DriftAllowance) []string {
+	var remaining []string
+	for _, attribute := range attributes {
+		if !isAllowed(address, attribute, allowlist) {
+			remaining = append(remaining, attribute)
+		}
+	}
+	return remaining
+}
+
+func isAllowed(address string, attribute string, allowlist []DriftAllowance) bool {
+	attribute = strings.TrimSuffix(attribute, " (known after apply)")
+	for _, allowance := range allowlist {
+		if !regexp.MustCompile(allowance.Address).MatchString(address) {
+			continue
+		}
+		if attribute == allowance.Attribute || strings.HasPrefix(attribute, allowance.Attribute+".") ||
+			strings.HasPrefix(attribute, allowance.Attribute+"[") {
+			return true
+		}
+	}
+	return false
+}
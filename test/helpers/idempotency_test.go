@@ -0,0 +1,63 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+)
+
+func resourceChange(actions tfjson.Actions, before, after, afterUnknown interface{}) *tfjson.ResourceChange {
+	return &tfjson.ResourceChange{
+		Change: &tfjson.Change{Actions: actions, Before: before, After: after, AfterUnknown: afterUnknown},
+	}
+}
+
+// TestFindDrift verifies that the drifting attributes of each changed resource are reported and that
+// allowlisted attributes and unchanged resources are ignored.
+func TestFindDrift(t *testing.T) {
+	update := tfjson.Actions{tfjson.ActionUpdate}
+	plan := &terraform.PlanStruct{
+		ResourceChangesMap: map[string]*tfjson.ResourceChange{
+			"module.aks.azurerm_kubernetes_cluster.aks": resourceChange(update,
+				map[string]interface{}{
+					"tags":              map[string]interface{}{"env": "test"},
+					"default_node_pool": []interface{}{map[string]interface{}{"upgrade_settings": []interface{}{map[string]interface{}{"max_surge": "10%"}}}},
+				},
+				map[string]interface{}{
+					"tags":              map[string]interface{}{"env": "test", "created": nil},
+					"default_node_pool": []interface{}{map[string]interface{}{"upgrade_settings": []interface{}{}}},
+				},
+				map[string]interface{}{"tags": map[string]interface{}{"created": true}},
+			),
+			"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": resourceChange(update,
+				map[string]interface{}{"upgrade_settings": []interface{}{map[string]interface{}{"max_surge": "10%"}}},
+				map[string]interface{}{"upgrade_settings": []interface{}{}},
+				map[string]interface{}{},
+			),
+			"azurerm_network_security_group.nsg[0]": resourceChange(update,
+				map[string]interface{}{"security_rule": []interface{}{map[string]interface{}{"priority": float64(180)}}},
+				map[string]interface{}{"security_rule": []interface{}{map[string]interface{}{"priority": float64(181)}}},
+				map[string]interface{}{},
+			),
+			"module.kubeconfig.local_file.kubeconfig": resourceChange(tfjson.Actions{tfjson.ActionCreate}, nil,
+				map[string]interface{}{"filename": "/tmp/kubeconfig"}, map[string]interface{}{"id": true}),
+			"azurerm_resource_group.aks_rg[0]": resourceChange(tfjson.Actions{tfjson.ActionNoop},
+				map[string]interface{}{"name": "rg"}, map[string]interface{}{"name": "rg"}, map[string]interface{}{}),
+		},
+	}
+
+	var messages []string
+	for _, drift := range FindDrift(plan, IdempotencyAllowlist) {
+		messages = append(messages, drift.String())
+	}
+	assert.Equal(t, []string{
+		"azurerm_network_security_group.nsg[0] (update): security_rule[0].priority",
+		"module.aks.azurerm_kubernetes_cluster.aks (update): tags.created (known after apply)",
+		"module.kubeconfig.local_file.kubeconfig (create): (resource)",
+	}, messages)
+}
@@ -5,6 +5,12 @@ package helpers
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
 	tfjson "github.com/hashicorp/terraform-json"
 	"k8s.io/client-go/util/jsonpath"
 )
@@ -12,18 +18,59 @@ import (
 // GetJsonPathFromStateResource retrieves the value of a jsonpath query on a given *tfjson.StateResource
 // map is visited in random order
 func GetJsonPathFromStateResource(resource *tfjson.StateResource, jsonPath string) (string, error) {
-	return getJsonPath(resource.AttributeValues, jsonPath)
+	return getJsonPath(resource.AttributeValues, jsonPath, false)
 }
 
 // GetJsonPathFromPlannedVariablesMap retrieves the value of a jsonpath query on a given *tfjson.PlanVariable
 // map is visited in random order
 func GetJsonPathFromPlannedVariablesMap(resourceMap *tfjson.PlanVariable, jsonPath string) (string, error) {
-	return getJsonPath(resourceMap, jsonPath)
+	return getJsonPath(resourceMap, jsonPath, false)
+}
+
+// GetStrictJsonPathFromStateResource is GetJsonPathFromStateResource in strict mode, where a query that does not
+// resolve returns a *JsonPathNotFoundError instead of an empty string.
+func GetStrictJsonPathFromStateResource(resource *tfjson.StateResource, jsonPath string) (string, error) {
+	return getJsonPath(resource.AttributeValues, jsonPath, true)
+}
+
+// GetStrictJsonPathFromPlannedVariablesMap is GetJsonPathFromPlannedVariablesMap in strict mode, where a query
+// that does not resolve returns a *JsonPathNotFoundError instead of an empty string.
+func GetStrictJsonPathFromPlannedVariablesMap(resourceMap *tfjson.PlanVariable, jsonPath string) (string, error) {
+	return getJsonPath(resourceMap, jsonPath, true)
+}
+
+// JsonPathNotFoundError is returned in strict mode by a jsonpath query that does not resolve, such as a
+// misspelled attribute name. Attributes that are unknown until apply are not in the plan either.
+type JsonPathNotFoundError struct {
+	JsonPath string
+	// Resolved is the deepest part of the query that resolved, empty if it could not be determined
+	Resolved string
+	// Available describes the keys, or the length of the list, at Resolved
+	Available string
+	Err       error
 }
 
-func getJsonPath(resource interface{}, jsonPath string) (string, error) {
+func (e *JsonPathNotFoundError) Error() string {
+	if e.Resolved == "" {
+		return fmt.Sprintf("jsonpath %s does not resolve: %s", e.JsonPath, e.Err)
+	}
+	return fmt.Sprintf("jsonpath %s does not resolve: %s, available at %s: %s", e.JsonPath, e.Err, e.Resolved, e.Available)
+}
+
+func (e *JsonPathNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// ParentResolved reports whether the query resolves up to the parent of its last segment, e.g. {$.os_disk[0]}
+// for {$.os_disk[0].caching}, so only the attribute or list element of the last segment is not in the plan.
+func (e *JsonPathNotFoundError) ParentResolved() bool {
+	parent, ok := parentJsonPath(e.JsonPath)
+	return ok && e.Resolved == parent
+}
+
+func getJsonPath(resource interface{}, jsonPath string, strict bool) (string, error) {
 	j := jsonpath.New("PlanParser")
-	j.AllowMissingKeys(true)
+	j.AllowMissingKeys(!strict)
 	err := j.Parse(jsonPath)
 	if err != nil {
 		return "", err
@@ -31,8 +78,89 @@ func getJsonPath(resource interface{}, jsonPath string) (string, error) {
 	buf := new(bytes.Buffer)
 	err = j.Execute(buf, resource)
 	if err != nil {
+		if strict && (strings.HasSuffix(err.Error(), " is not found") || strings.HasPrefix(err.Error(), "array index out of bounds")) {
+			notFound := &JsonPathNotFoundError{JsonPath: jsonPath, Err: err}
+			notFound.Resolved, notFound.Available = deepestResolved(resource, jsonPath)
+			return "", notFound
+		}
 		return "", err
 	}
 	out := buf.String()
 	return out, nil
 }
+
+// jsonPathSegment matches a field, an index or a quoted field at the start of a simple jsonpath query
+var jsonPathSegment = regexp.MustCompile(`^(?:\.([^.\[\]]+)|\[(\d+)\]|\['([^']*)'\])`)
+
+// parentJsonPath returns the query of the parent of the last segment of a simple jsonpath query, made of fields
+// and indexes only, in the form returned by deepestResolved, or false if the query is not simple.
+func parentJsonPath(jsonPath string) (string, bool) {
+	rest := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(jsonPath, "{"), "}"), "$")
+	parent, last := "$", ""
+	for rest != "" {
+		match := jsonPathSegment.FindStringSubmatch(rest)
+		if match == nil {
+			return "", false
+		}
+		parent += last
+		last = match[0]
+		rest = rest[len(match[0]):]
+	}
+	if last == "" {
+		return "", false
+	}
+	return "{" + parent + "}", true
+}
+
+// deepestResolved follows a simple jsonpath query, made of fields and indexes only, into the JSON form of the
+// resource and returns the deepest part that resolves with a description of what is available there.
+func deepestResolved(resource interface{}, jsonPath string) (string, string) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", ""
+	}
+	var current interface{}
+	if json.Unmarshal(data, &current) != nil {
+		return "", ""
+	}
+
+	rest := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(jsonPath, "{"), "}"), "$")
+	resolved := "$"
+	for rest != "" {
+		match := jsonPathSegment.FindStringSubmatch(rest)
+		if match == nil {
+			return "", ""
+		}
+		switch typed := current.(type) {
+		case map[string]interface{}:
+			key := match[1] + match[3]
+			value, ok := typed[key]
+			if match[2] != "" || !ok {
+				return "{" + resolved + "}", describeKeys(typed)
+			}
+			current = value
+		case []interface{}:
+			var index int
+			if _, err := fmt.Sscan(match[2], &index); err != nil || index >= len(typed) {
+				return "{" + resolved + "}", fmt.Sprintf("a list of length %d", len(typed))
+			}
+			current = typed[index]
+		case nil:
+			return "{" + resolved + "}", "null"
+		default:
+			return "{" + resolved + "}", fmt.Sprintf("a %T", typed)
+		}
+		resolved += match[0]
+		rest = rest[len(match[0]):]
+	}
+	return "", ""
+}
+
+func describeKeys(values map[string]interface{}) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return "keys " + strings.Join(keys, ", ")
+}
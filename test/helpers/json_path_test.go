@@ -0,0 +1,121 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jsonPathResource = &tfjson.StateResource{
+	AttributeValues: map[string]interface{}{
+		"name":          "default-nfs-vm",
+		"zone":          nil,
+		"os_disk":       []interface{}{map[string]interface{}{"caching": "ReadOnly", "disk_size_gb": 64}},
+		"data_disks":    []interface{}{},
+		"admin_ssh_key": []interface{}{},
+	},
+}
+
+// TestGetStrictJsonPath verifies that a query that does not resolve fails with the keys available at the deepest
+// resolved level in strict mode, and returns an empty string otherwise.
+func TestGetStrictJsonPath(t *testing.T) {
+	tests := map[string]struct {
+		jsonPath string
+		expected string
+		err      string
+	}{
+		"resolved":    {"{$.os_disk[0].caching}", "ReadOnly", ""},
+		"null":        {"{$.zone}", "null", ""},
+		"typo":        {"{$.vm_zone}", "", "jsonpath {$.vm_zone} does not resolve: vm_zone is not found, available at {$}: keys admin_ssh_key, data_disks, name, os_disk, zone"},
+		"nestedTypo":  {"{$.os_disk[0].disk_size}", "", "jsonpath {$.os_disk[0].disk_size} does not resolve: disk_size is not found, available at {$.os_disk[0]}: keys caching, disk_size_gb"},
+		"outOfBounds": {"{$.data_disks[0].lun}", "", "jsonpath {$.data_disks[0].lun} does not resolve: array index out of bounds: index 0, length 0, available at {$.data_disks}: a list of length 0"},
+		"throughNull": {"{$.zone.name}", "", "jsonpath {$.zone.name} does not resolve: name is not found, available at {$.zone}: null"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := GetStrictJsonPathFromStateResource(jsonPathResource, tc.jsonPath)
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+				return
+			}
+			var notFound *JsonPathNotFoundError
+			require.ErrorAs(t, err, &notFound)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+
+	actual, err := GetJsonPathFromStateResource(jsonPathResource, "{$.vm_zone}")
+	require.NoError(t, err)
+	assert.Equal(t, "", actual)
+}
+
+// TestRunTestExpectAbsent verifies that attributes that are not in the plan or are null pass an absence check.
+func TestRunTestExpectAbsent(t *testing.T) {
+	plan := &terraform.PlanStruct{
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{"module.nfs[0].azurerm_linux_virtual_machine.vm": jsonPathResource},
+	}
+
+	RunTests(t, map[string]TestCase{
+		"missing": {
+			ResourceMapName:   "module.nfs[0].azurerm_linux_virtual_machine.vm",
+			AttributeJsonPath: "{$.identity}",
+			ExpectAbsent:      true,
+		},
+		"emptyBlock": {
+			ResourceMapName:   "module.nfs[0].azurerm_linux_virtual_machine.vm",
+			AttributeJsonPath: "{$.admin_ssh_key[0]}",
+			ExpectAbsent:      true,
+		},
+		"null": {
+			ResourceMapName:   "module.nfs[0].azurerm_linux_virtual_machine.vm",
+			AttributeJsonPath: "{$.zone}",
+			ExpectAbsent:      true,
+		},
+	}, plan)
+}
+
+// TestJsonPathNotFoundParentResolved verifies that only a query whose parent resolves is an absent attribute, so
+// a misspelled block does not pass an absence check.
+func TestJsonPathNotFoundParentResolved(t *testing.T) {
+	tests := map[string]struct {
+		jsonPath string
+		expected bool
+	}{
+		"attribute":      {"{$.os_disk[0].write_accelerator}", true},
+		"emptyBlock":     {"{$.data_disks[0]}", true},
+		"throughNull":    {"{$.zone.name}", true},
+		"missingBlock":   {"{$.identity[0].type}", false},
+		"emptyBlockAttr": {"{$.data_disks[0].lun}", false},
+		"notSimple":      {"{$.os_disk[*].write_accelerator}", false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := GetStrictJsonPathFromStateResource(jsonPathResource, tc.jsonPath)
+			var notFound *JsonPathNotFoundError
+			require.ErrorAs(t, err, &notFound)
+			assert.Equal(t, tc.expected, notFound.ParentResolved())
+		})
+	}
+}
+
+// TestRetrieveFromRawPlanResource verifies that a variable that is not in the plan is an error, not an empty value.
+func TestRetrieveFromRawPlanResource(t *testing.T) {
+	plan := &terraform.PlanStruct{
+		RawPlan: tfjson.Plan{Variables: map[string]*tfjson.PlanVariable{"default_public_access_cidrs": {Value: []interface{}{"10.0.0.0/8"}}}},
+	}
+
+	actual, err := RetrieveFromRawPlanResource(plan, "default_public_access_cidrs", "{$.value}")
+	require.NoError(t, err)
+	assert.Equal(t, `["10.0.0.0/8"]`, actual)
+	_, err = RetrieveFromRawPlanResource(plan, "default_public_acess_cidrs", "{$.value}")
+	assert.EqualError(t, err, "the plan has no variable default_public_acess_cidrs")
+}
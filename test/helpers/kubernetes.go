@@ -0,0 +1,236 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeconfigNamespace is the namespace the kubeconfig module creates its service account in.
+const KubeconfigNamespace = "kube-system"
+
+// AgentPoolLabel is the label AKS sets on every node to the name of its node pool.
+const AgentPoolLabel = "kubernetes.azure.com/agentpool"
+
+// DefaultStorageClasses are the StorageClasses AKS creates in every cluster.
+var DefaultStorageClasses = []string{"default", "managed-csi", "managed-csi-premium", "azurefile-csi", "azurefile-csi-premium"}
+
+// GetKubernetesClientset returns a clientset for the cluster in the kube_config output of an applied configuration.
+// The output is read without logging it, and its credentials are registered as secrets.
+func GetKubernetesClientset(t *testing.T, options *terraform.Options) kubernetes.Interface {
+	quiet := *options
+	quiet.Logger = logger.Discard
+	kubeconfig := terraform.Output(t, &quiet, "kube_config")
+	RegisterSecrets(kubeconfig)
+	RegisterSecrets(kubeconfigSecrets(kubeconfig)...)
+	client, err := NewClientsetFromKubeconfig(kubeconfig)
+	require.NoError(t, err)
+	return client
+}
+
+// kubeconfigSecrets returns the tokens, passwords and client keys of the users in the kubeconfig file contents,
+// with the keys in the base64 form of the file.
+func kubeconfigSecrets(kubeconfig string) []string {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil
+	}
+	var secrets []string
+	for _, user := range config.AuthInfos {
+		secrets = append(secrets, user.Token, user.Password)
+		if len(user.ClientKeyData) > 0 {
+			secrets = append(secrets, base64.StdEncoding.EncodeToString(user.ClientKeyData))
+		}
+	}
+	return secrets
+}
+
+// NewClientsetFromKubeconfig returns a clientset for the current context of the kubeconfig file contents.
+func NewClientsetFromKubeconfig(kubeconfig string) (kubernetes.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// KubernetesNodePoolTests returns test cases checking that the nodes of each planned node pool carry the
+// node_labels and node_taints of the pool, and that each pool has at least its minimum number of nodes.
+// Pools that are scaled to zero have no nodes to check.
+func KubernetesNodePoolTests(client kubernetes.Interface, plan *terraform.PlanStruct) map[string]ApplyTestCase {
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return map[string]ApplyTestCase{
+			"nodesListTest": {Expected: nil, Actual: err, Message: "Error listing nodes"},
+		}
+	}
+	nodesByPool := make(map[string][]corev1.Node)
+	for _, node := range nodes.Items {
+		pool := node.Labels[AgentPoolLabel]
+		nodesByPool[pool] = append(nodesByPool[pool], node)
+	}
+
+	tests := make(map[string]ApplyTestCase)
+	for _, resource := range plan.ResourcePlannedValuesMap {
+		if resource.Type != "azurerm_kubernetes_cluster_node_pool" {
+			continue
+		}
+		values := resource.AttributeValues
+		pool, _ := values["name"].(string)
+		minNodes, ok := values["min_count"].(float64)
+		if !ok {
+			minNodes, _ = values["node_count"].(float64)
+		}
+		tests[pool+"NodeCountTest"] = ApplyTestCase{
+			Expected:       int(minNodes),
+			Actual:         len(nodesByPool[pool]),
+			AssertFunction: assert.LessOrEqual,
+			Message:        fmt.Sprintf("Node pool %s has fewer nodes than its minimum", pool),
+		}
+
+		labels, _ := values["node_labels"].(map[string]interface{})
+		taints, _ := values["node_taints"].([]interface{})
+		for _, node := range nodesByPool[pool] {
+			for key, value := range labels {
+				tests[fmt.Sprintf("%sLabel[%s]Test", node.Name, key)] = ApplyTestCase{
+					Expected: value,
+					Actual:   node.Labels[key],
+					Message:  fmt.Sprintf("Node %s label %s is incorrect", node.Name, key),
+				}
+			}
+			nodeTaints := make([]string, 0, len(node.Spec.Taints))
+			for _, taint := range node.Spec.Taints {
+				nodeTaints = append(nodeTaints, taint.ToString())
+			}
+			for _, taint := range taints {
+				tests[fmt.Sprintf("%sTaint[%s]Test", node.Name, taint)] = ApplyTestCase{
+					Expected:       taint,
+					Actual:         nodeTaints,
+					AssertFunction: assert.Contains,
+					Message:        fmt.Sprintf("Node %s is missing a taint", node.Name),
+				}
+			}
+		}
+	}
+	return tests
+}
+
+// KubernetesServiceAccountTests returns test cases checking that the service account and ClusterRoleBinding
+// of the static kubeconfig exist. There are none when create_static_kubeconfig is false.
+func KubernetesServiceAccountTests(client kubernetes.Interface, plan *terraform.PlanStruct) map[string]ApplyTestCase {
+	if planVariable(plan, "create_static_kubeconfig") == false {
+		return map[string]ApplyTestCase{}
+	}
+	prefix, _ := planVariable(plan, "prefix").(string)
+	serviceAccountName := prefix + "-cluster-admin-sa"
+	clusterRoleBindingName := prefix + "-cluster-admin-crb"
+
+	ctx := context.Background()
+	_, saErr := client.CoreV1().ServiceAccounts(KubeconfigNamespace).Get(ctx, serviceAccountName, metav1.GetOptions{})
+	crb, crbErr := client.RbacV1().ClusterRoleBindings().Get(ctx, clusterRoleBindingName, metav1.GetOptions{})
+
+	var roleRef string
+	var subjects []string
+	if crbErr == nil {
+		roleRef = crb.RoleRef.Kind + "/" + crb.RoleRef.Name
+		for _, subject := range crb.Subjects {
+			subjects = append(subjects, fmt.Sprintf("%s %s/%s", subject.Kind, subject.Namespace, subject.Name))
+		}
+	}
+
+	return map[string]ApplyTestCase{
+		"serviceAccountExistsTest": {
+			Expected: nil,
+			Actual:   saErr,
+			Message:  "Kubeconfig service account does not exist",
+		},
+		"clusterRoleBindingExistsTest": {
+			Expected: nil,
+			Actual:   crbErr,
+			Message:  "Kubeconfig ClusterRoleBinding does not exist",
+		},
+		"clusterRoleBindingRoleTest": {
+			Expected: "ClusterRole/cluster-admin",
+			Actual:   roleRef,
+			Message:  "Kubeconfig ClusterRoleBinding role is incorrect",
+		},
+		"clusterRoleBindingSubjectTest": {
+			Expected:       fmt.Sprintf("ServiceAccount %s/%s", KubeconfigNamespace, serviceAccountName),
+			Actual:         subjects,
+			AssertFunction: assert.Contains,
+			Message:        "Kubeconfig ClusterRoleBinding is not bound to the service account",
+		},
+	}
+}
+
+// KubernetesStorageClassTests returns test cases checking that the named StorageClasses exist.
+func KubernetesStorageClassTests(client kubernetes.Interface, names []string) map[string]ApplyTestCase {
+	storageClasses, err := client.StorageV1().StorageClasses().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return map[string]ApplyTestCase{
+			"storageClassesListTest": {Expected: nil, Actual: err, Message: "Error listing StorageClasses"},
+		}
+	}
+	var existing []string
+	for _, storageClass := range storageClasses.Items {
+		existing = append(existing, storageClass.Name)
+	}
+	sort.Strings(existing)
+
+	tests := make(map[string]ApplyTestCase)
+	for _, name := range names {
+		tests[name+"StorageClassTest"] = ApplyTestCase{
+			Expected:       name,
+			Actual:         existing,
+			AssertFunction: assert.Contains,
+			Message:        fmt.Sprintf("StorageClass %s does not exist", name),
+		}
+	}
+	return tests
+}
+
+// KubernetesVersionTests returns a test case checking that the server version matches the kubernetes_version
+// variable. Only the components given in the variable are compared, so "1.30" matches a v1.30.4 server.
+func KubernetesVersionTests(client kubernetes.Interface, plan *terraform.PlanStruct) map[string]ApplyTestCase {
+	expected, _ := planVariable(plan, "kubernetes_version").(string)
+	return map[string]ApplyTestCase{
+		"kubernetesVersionTest": {
+			Expected: expected,
+			ActualRetriever: func() string {
+				info, err := client.Discovery().ServerVersion()
+				if err != nil {
+					return err.Error()
+				}
+				components := strings.Split(strings.TrimPrefix(info.GitVersion, "v"), ".")
+				if count := len(strings.Split(expected, ".")); count < len(components) {
+					components = components[:count]
+				}
+				return strings.Join(components, ".")
+			},
+			Message: "Kubernetes version is incorrect",
+		},
+	}
+}
+
+// planVariable returns the value of the input variable in the plan, or nil if it is not set.
+func planVariable(plan *terraform.PlanStruct, name string) interface{} {
+	if variable, ok := plan.RawPlan.Variables[name]; ok && variable != nil {
+		return variable.Value
+	}
+	return nil
+}
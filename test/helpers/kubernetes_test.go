@@ -0,0 +1,144 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func kubernetesTestPlan() *terraform.PlanStruct {
+	return &terraform.PlanStruct{
+		RawPlan: tfjson.Plan{
+			Variables: map[string]*tfjson.PlanVariable{
+				"prefix":             {Value: "test"},
+				"kubernetes_version": {Value: "1.30"},
+			},
+		},
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{
+			"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": {
+				Type: "azurerm_kubernetes_cluster_node_pool",
+				AttributeValues: map[string]interface{}{
+					"name":        "cas",
+					"min_count":   float64(1),
+					"node_labels": map[string]interface{}{"workload.sas.com/class": "cas"},
+					"node_taints": []interface{}{"workload.sas.com/class=cas:NoSchedule"},
+				},
+			},
+			"module.node_pools[\"stateless\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": {
+				Type: "azurerm_kubernetes_cluster_node_pool",
+				AttributeValues: map[string]interface{}{
+					"name":        "stateless",
+					"min_count":   float64(0),
+					"node_labels": map[string]interface{}{"workload.sas.com/class": "stateless"},
+				},
+			},
+		},
+	}
+}
+
+func kubernetesTestNode(name string, pool string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+	nodeLabels := map[string]string{AgentPoolLabel: pool}
+	for key, value := range labels {
+		nodeLabels[key] = value
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+	}
+}
+
+func kubernetesTestClient(serverVersion string, objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: serverVersion}
+	return client
+}
+
+// failedTests returns the sorted names of the test cases whose assertion fails.
+func failedTests(tests map[string]ApplyTestCase) []string {
+	var failed []string
+	for name, tc := range tests {
+		assertFn := tc.AssertFunction
+		if assertFn == nil {
+			assertFn = assert.Equal
+		}
+		actual := tc.Actual
+		if tc.ActualRetriever != nil {
+			actual = tc.ActualRetriever()
+		}
+		if !compare(&probeT{}, assertFn, tc.Expected, actual) {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// TestKubernetesTestsPass verifies that a cluster matching the plan passes every Kubernetes test case.
+func TestKubernetesTestsPass(t *testing.T) {
+	plan := kubernetesTestPlan()
+	client := kubernetesTestClient("v1.30.4",
+		kubernetesTestNode("aks-cas-0", "cas", map[string]string{"workload.sas.com/class": "cas"},
+			corev1.Taint{Key: "workload.sas.com/class", Value: "cas", Effect: corev1.TaintEffectNoSchedule}),
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-admin-sa", Namespace: "kube-system"}},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-admin-crb"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "test-cluster-admin-sa", Namespace: "kube-system"}},
+		},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "managed-csi"}},
+	)
+
+	RunApplyTests(t, KubernetesNodePoolTests(client, plan))
+	RunApplyTests(t, KubernetesServiceAccountTests(client, plan))
+	RunApplyTests(t, KubernetesStorageClassTests(client, []string{"default", "managed-csi"}))
+	RunApplyTests(t, KubernetesVersionTests(client, plan))
+}
+
+// TestKubernetesTestsFail verifies that missing labels, taints, nodes, kubeconfig objects and StorageClasses
+// and a mismatched version are reported.
+func TestKubernetesTestsFail(t *testing.T) {
+	plan := kubernetesTestPlan()
+	client := kubernetesTestClient("v1.31.1",
+		kubernetesTestNode("aks-cas-0", "cas", map[string]string{"workload.sas.com/class": "compute"}),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	)
+
+	assert.Equal(t, []string{
+		"aks-cas-0Label[workload.sas.com/class]Test",
+		"aks-cas-0Taint[workload.sas.com/class=cas:NoSchedule]Test",
+	}, failedTests(KubernetesNodePoolTests(client, plan)))
+	assert.Equal(t, []string{
+		"clusterRoleBindingExistsTest",
+		"clusterRoleBindingRoleTest",
+		"clusterRoleBindingSubjectTest",
+		"serviceAccountExistsTest",
+	}, failedTests(KubernetesServiceAccountTests(client, plan)))
+	assert.Equal(t, []string{"managed-csiStorageClassTest"},
+		failedTests(KubernetesStorageClassTests(client, []string{"default", "managed-csi"})))
+	assert.Equal(t, []string{"kubernetesVersionTest"}, failedTests(KubernetesVersionTests(client, plan)))
+
+	empty := kubernetesTestClient("v1.30.0")
+	assert.Equal(t, []string{"casNodeCountTest"}, failedTests(KubernetesNodePoolTests(empty, plan)))
+}
+
+// TestKubernetesServiceAccountTestsDisabled verifies that no kubeconfig objects are expected without a static kubeconfig.
+func TestKubernetesServiceAccountTestsDisabled(t *testing.T) {
+	plan := kubernetesTestPlan()
+	plan.RawPlan.Variables["create_static_kubeconfig"] = &tfjson.PlanVariable{Value: false}
+	assert.Empty(t, KubernetesServiceAccountTests(kubernetesTestClient("v1.30.0"), plan))
+}
@@ -0,0 +1,124 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"sort"
+	"strings"
+	"test/sku"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// LocationsEnvVar holds a comma separated list of the locations to run the plan tests in, e.g. "eastus,westus".
+// It overrides the locations of the test profile. The first location is also used by the apply tests.
+const LocationsEnvVar = "TEST_LOCATIONS"
+
+// DefaultLocation is used when neither the test profile nor TEST_LOCATIONS set the locations.
+const DefaultLocation = "eastus"
+
+// TestLocation is a location in the test matrix along with its availability zones.
+type TestLocation struct {
+	Name  string
+	Zones []string
+}
+
+// HasZones reports whether the location has availability zones.
+func (l TestLocation) HasZones() bool {
+	return len(l.Zones) > 0
+}
+
+// SetVariables sets the location and, for locations without availability zones, clears the zone
+// variables so that no zonal resources are requested.
+func (l TestLocation) SetVariables(variables map[string]interface{}) {
+	variables["location"] = l.Name
+	if l.HasZones() {
+		return
+	}
+	variables["default_nodepool_availability_zones"] = []string{}
+	variables["node_pools_availability_zone"] = ""
+	for _, name := range []string{"node_pools_availability_zones", "jump_vm_zone", "nfs_vm_zone", "nfs_raid_disk_zone"} {
+		delete(variables, name)
+	}
+}
+
+// GetTestLocations returns the locations of the test profile, see GetTestConfig. The zones of each location
+// are taken from the offline SKU snapshot, so every location must be in it.
+func GetTestLocations(t *testing.T) []TestLocation {
+	names := GetTestConfig(t).Locations
+
+	snapshot, err := sku.LoadSnapshot("../sku/vm_skus_snapshot.json")
+	if err != nil {
+		t.Fatalf("Error loading SKU snapshot: %s", err)
+	}
+
+	locations := make([]TestLocation, 0, len(names))
+	for _, name := range names {
+		zones, found := snapshotZones(snapshot, name)
+		if !found {
+			t.Fatalf("Location %s is not in the SKU snapshot, regenerate it with 'go run ./cmd/skusnapshot -locations %s'",
+				name, strings.Join(names, ","))
+		}
+		locations = append(locations, TestLocation{Name: name, Zones: zones})
+	}
+	return locations
+}
+
+// ForEachLocation runs fn as a parallel subtest for each test location.
+func ForEachLocation(t *testing.T, fn func(t *testing.T, location TestLocation)) {
+	for _, location := range GetTestLocations(t) {
+		t.Run(location.Name, func(t *testing.T) {
+			t.Parallel()
+			fn(t, location)
+		})
+	}
+}
+
+// GetDefaultPlanForLocation returns the cached default plan for the location.
+func GetDefaultPlanForLocation(t *testing.T, location TestLocation) *terraform.PlanStruct {
+	variables := GetDefaultPlanVars(t)
+	location.SetVariables(variables)
+	return GetPlanFromCache(t, variables)
+}
+
+// RunDefaultPlanTests runs the test cases against the default plan of each test location.
+func RunDefaultPlanTests(t *testing.T, tests map[string]TestCase) {
+	ForEachLocation(t, func(t *testing.T, location TestLocation) {
+		RunTests(t, tests, GetDefaultPlanForLocation(t, location))
+	})
+}
+
+// RunDefaultPlanTupleTests runs the tuple test cases against the default plan of each test location.
+func RunDefaultPlanTupleTests(t *testing.T, resourceMapNameFmt string, tests map[string]TupleTestCase) {
+	ForEachLocation(t, func(t *testing.T, location TestLocation) {
+		RunTupleTests(t, resourceMapNameFmt, tests, GetDefaultPlanForLocation(t, location))
+	})
+}
+
+// snapshotZones returns the union of the VM zones in the location.
+func snapshotZones(snapshot sku.Snapshot, location string) ([]string, bool) {
+	found := false
+	zoneSet := make(map[string]bool)
+	for i := range snapshot {
+		if snapshot[i].ResourceType != "virtualMachines" {
+			continue
+		}
+		zones, ok := snapshot[i].Zones(location)
+		if !ok {
+			continue
+		}
+		found = true
+		for _, zone := range zones {
+			zoneSet[zone] = true
+		}
+	}
+
+	zones := make([]string, 0, len(zoneSet))
+	for zone := range zoneSet {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones, found
+}
@@ -0,0 +1,34 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetTestLocations verifies that the location matrix is read from TEST_LOCATIONS and that
+// locations without availability zones clear the zone variables.
+func TestGetTestLocations(t *testing.T) {
+	t.Setenv(LocationsEnvVar, "eastus, westus")
+
+	locations := GetTestLocations(t)
+	require.Len(t, locations, 2)
+	assert.Equal(t, TestLocation{Name: "eastus", Zones: []string{"1", "2", "3"}}, locations[0])
+	assert.Equal(t, "westus", locations[1].Name)
+	assert.False(t, locations[1].HasZones())
+
+	variables := map[string]interface{}{"nfs_vm_zone": "1"}
+	locations[0].SetVariables(variables)
+	assert.Equal(t, map[string]interface{}{"location": "eastus", "nfs_vm_zone": "1"}, variables)
+
+	locations[1].SetVariables(variables)
+	assert.Equal(t, map[string]interface{}{
+		"location":                            "westus",
+		"default_nodepool_availability_zones": []string{},
+		"node_pools_availability_zone":        "",
+	}, variables)
+}
@@ -0,0 +1,181 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moduleTestDir is the directory of the temporary root module of a ModulePlan, in the copy of the repository.
+const moduleTestDir = "moduletest"
+
+// StubProviders are the provider blocks of the temporary root module of a ModulePlan, keyed by provider name.
+// The azurerm provider authenticates with the ARM_* variables of the environment, like the root module in the
+// plan tests, and does not register resource providers. The kubernetes provider points to a cluster that does
+// not exist, which is enough to plan the resources of the kubeconfig module.
+var StubProviders = map[string]string{
+	"azurerm": `provider "azurerm" {
+  features {}
+  resource_provider_registrations = "none"
+}`,
+	"kubernetes": `provider "kubernetes" {
+  host = "https://localhost:6443"
+}`,
+}
+
+// ModulePlan is a plan of a single module of the modules directory, called with Inputs by a temporary root
+// module that configures the StubProviders. The module is called by its directory name, so the planned values
+// of its resources are at addresses such as module.aks_node_pool.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0].
+type ModulePlan struct {
+	// Module is the directory of the module in the modules directory, e.g. aks_node_pool
+	Module string
+	// Inputs are the arguments of the module call. They are written as JSON, so strings are templates and a
+	// literal "${" must be written as "$${".
+	Inputs map[string]interface{}
+	// Providers replaces the StubProviders blocks or adds provider blocks, keyed by provider name
+	Providers map[string]string
+}
+
+// Address returns the address of a resource of the module, e.g. azurerm_managed_disk.vm_data_disk[0].
+func (p ModulePlan) Address(resource string) string {
+	return "module." + p.Module + "." + resource
+}
+
+// providers returns the provider blocks of the temporary root module, sorted by provider name.
+func (p ModulePlan) providers() string {
+	blocks := make(map[string]string)
+	for name, block := range StubProviders {
+		blocks[name] = block
+	}
+	for name, block := range p.Providers {
+		blocks[name] = block
+	}
+	names := make([]string, 0, len(blocks))
+	for name := range blocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var providers strings.Builder
+	for _, name := range names {
+		providers.WriteString(blocks[name] + "\n\n")
+	}
+	return providers.String()
+}
+
+// WriteModuleRoot writes the temporary root module of the plan to dir, a directory of a copy of the repository
+// next to its modules directory. The versions.tf of the repository is copied, so the providers are the versions
+// that the root module uses.
+func WriteModuleRoot(dir string, repository string, plan ModulePlan) error {
+	if _, err := os.Stat(filepath.Join(repository, "modules", plan.Module)); err != nil {
+		return fmt.Errorf("module %s does not exist: %w", plan.Module, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	versions, err := os.ReadFile(filepath.Join(repository, "versions.tf"))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), versions, 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "providers.tf"), []byte(plan.providers()), 0644); err != nil {
+		return err
+	}
+
+	source, err := filepath.Rel(dir, filepath.Join(repository, "modules", plan.Module))
+	if err != nil {
+		return err
+	}
+	source = filepath.ToSlash(source)
+	if !strings.HasPrefix(source, "../") {
+		source = "./" + source
+	}
+	call := map[string]interface{}{"source": source}
+	for name, value := range plan.Inputs {
+		call[name] = value
+	}
+	main, err := json.MarshalIndent(map[string]interface{}{"module": map[string]interface{}{plan.Module: call}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "main.tf.json"), main, 0644)
+}
+
+// InitModulePlan plans the module in a temporary copy of the repository and returns the plan, or the error of
+// terraform, e.g. for an input that fails a validation block.
+func InitModulePlan(t *testing.T, plan ModulePlan) (*terraform.PlanStruct, error) {
+	RequireSuite(t, PlanSuite)
+	resolveProcessAzureCredential(t)
+	planFilePath := filepath.Join(t.TempDir(), "moduleplan.tfplan")
+
+	// Copy the terraform folder to a temp folder
+	tempTestFolder := copyRepository(t, os.TempDir())
+	defer removeRepositoryCopy(t, tempTestFolder)
+	rootDir := filepath.Join(tempTestFolder, moduleTestDir)
+	if err := WriteModuleRoot(rootDir, tempTestFolder, plan); err != nil {
+		return nil, err
+	}
+
+	RegisterSensitiveVariables(plan.Inputs)
+	terraformOptions := &terraform.Options{
+		TerraformDir: rootDir,
+		PlanFilePath: planFilePath,
+		NoColor:      true,
+		Logger:       RedactingLogger,
+	}
+	result, err := terraform.InitAndPlanAndShowWithStructE(t, terraformOptions)
+	if result != nil {
+		RegisterPlanSecrets(result)
+	}
+	return result, err
+}
+
+// GetModulePlan returns the cached plan of the module with the inputs, creating it if needed.
+func GetModulePlan(t *testing.T, plan ModulePlan) *terraform.PlanStruct {
+	RequireSuite(t, PlanSuite)
+	key, err := json.Marshal(plan)
+	require.NoError(t, err)
+	return getCache().get("module/"+string(key), func() *terraform.PlanStruct {
+		result, err := InitModulePlan(t, plan)
+		require.NotNil(t, result)
+		require.NoError(t, err)
+		return result
+	})
+}
+
+// RunModulePlanTests runs the test cases against the plan of the module.
+func RunModulePlanTests(t *testing.T, plan ModulePlan, tests map[string]TestCase) {
+	RunTests(t, tests, GetModulePlan(t, plan))
+}
+
+// AssertPlanError asserts that the plan failed with an error containing message. Terraform wraps the diagnostics
+// to the width of the terminal and frames them, so the words of the error are compared, without the frame.
+func AssertPlanError(t *testing.T, err error, message string, msgAndArgs ...interface{}) bool {
+	if !assert.Error(t, err, msgAndArgs...) {
+		return false
+	}
+	return assert.Contains(t, planErrorWords(err.Error()), strings.Join(strings.Fields(message), " "), msgAndArgs...)
+}
+
+// planErrorWords returns the words of the output of terraform, separated by a single space.
+func planErrorWords(output string) string {
+	var words []string
+	for _, word := range strings.Fields(output) {
+		if strings.Trim(word, "│╷╵─") != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
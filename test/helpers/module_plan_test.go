@@ -0,0 +1,62 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteModuleRoot verifies that the temporary root module calls the module by its directory name with the
+// inputs, copies versions.tf and writes the stub providers with their overrides.
+func TestWriteModuleRoot(t *testing.T) {
+	repository := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repository, "modules", "azurerm_vm"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repository, "versions.tf"), []byte("terraform {}\n"), 0644))
+	dir := filepath.Join(repository, moduleTestDir)
+
+	plan := ModulePlan{
+		Module:    "azurerm_vm",
+		Inputs:    map[string]interface{}{"name": "test-jump", "data_disk_count": 2, "tags": map[string]string{}},
+		Providers: map[string]string{"kubernetes": `provider "kubernetes" {}`},
+	}
+	require.NoError(t, WriteModuleRoot(dir, repository, plan))
+
+	var main map[string]map[string]map[string]interface{}
+	data, err := os.ReadFile(filepath.Join(dir, "main.tf.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &main))
+	assert.Equal(t, map[string]interface{}{
+		"source":          "../modules/azurerm_vm",
+		"name":            "test-jump",
+		"data_disk_count": float64(2),
+		"tags":            map[string]interface{}{},
+	}, main["module"]["azurerm_vm"])
+
+	versions, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "terraform {}\n", string(versions))
+	providers, err := os.ReadFile(filepath.Join(dir, "providers.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, StubProviders["azurerm"]+"\n\n"+`provider "kubernetes" {}`+"\n\n", string(providers))
+
+	assert.Equal(t, "module.azurerm_vm.azurerm_linux_virtual_machine.vm", plan.Address("azurerm_linux_virtual_machine.vm"))
+	assert.ErrorContains(t, WriteModuleRoot(dir, repository, ModulePlan{Module: "vm"}), "module vm does not exist")
+}
+
+// TestPlanErrorWords verifies that a diagnostic wrapped and framed by terraform is compared as its words.
+func TestPlanErrorWords(t *testing.T) {
+	output := "error while running command: exit status 1; ╷\n│ Error: Invalid value for variable\n│ \n" +
+		"│ community_eviction_policy can only be specified when\n│ community_priority is set to 'Spot'.\n╵\n"
+
+	assert.Equal(t, "error while running command: exit status 1; Error: Invalid value for variable "+
+		"community_eviction_policy can only be specified when community_priority is set to 'Spot'.", planErrorWords(output))
+	assert.True(t, AssertPlanError(t, errors.New(output), "community_eviction_policy can only be specified when community_priority"))
+}
@@ -0,0 +1,157 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// NodePoolsSeedEnvVar can be set to replay a single generated node_pools sample from a failing test log.
+const NodePoolsSeedEnvVar = "NODE_POOLS_SEED"
+
+var nodePoolMachineTypes = []string{
+	"Standard_D4s_v5",
+	"Standard_D4ds_v5",
+	"Standard_D8s_v5",
+	"Standard_E8s_v5",
+	"Standard_E16ds_v5",
+}
+
+var nodePoolTaintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+var nodePoolZones = []string{"1", "2", "3"}
+
+// NodePool is a single generated entry of the node_pools input variable.
+type NodePool struct {
+	MachineType       string
+	OsDiskSize        int
+	MinNodes          int
+	MaxNodes          int
+	MaxPods           int
+	NodeTaints        []string
+	NodeLabels        map[string]string
+	AvailabilityZones []string
+}
+
+// NodePoolsSample is a randomly generated, valid set of node pool input variables.
+type NodePoolsSample struct {
+	Seed               int64
+	NodePools          map[string]NodePool
+	ProximityPlacement bool
+}
+
+// NodePoolsSeeds returns the seeds to generate node_pools samples from. If NODE_POOLS_SEED
+// is set only that seed is returned, otherwise count seeds are derived from the current time.
+// Each seed is logged so a failing sample can be replayed.
+func NodePoolsSeeds(t *testing.T, count int) []int64 {
+	if value := os.Getenv(NodePoolsSeedEnvVar); value != "" {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			t.Fatalf("Environment variable %s must be an integer: %s", NodePoolsSeedEnvVar, err)
+		}
+		t.Logf("Using node_pools seed %d from %s", seed, NodePoolsSeedEnvVar)
+		return []int64{seed}
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	seeds := make([]int64, count)
+	for i := range seeds {
+		seeds[i] = r.Int63()
+		t.Logf("Generated node_pools seed %d, rerun with %s=%d to reproduce", seeds[i], NodePoolsSeedEnvVar, seeds[i])
+	}
+	return seeds
+}
+
+// GenerateNodePools returns a random but valid node_pools sample. The same seed always
+// produces the same sample.
+func GenerateNodePools(seed int64) NodePoolsSample {
+	r := rand.New(rand.NewSource(seed))
+
+	sample := NodePoolsSample{
+		Seed:               seed,
+		NodePools:          make(map[string]NodePool),
+		ProximityPlacement: r.Intn(4) == 0,
+	}
+
+	poolCount := 1 + r.Intn(4)
+	for i := 0; i < poolCount; i++ {
+		name := fmt.Sprintf("pool%d%s", i, randomLowerAlnum(r, 1+r.Intn(5)))
+
+		minNodes := r.Intn(4)
+		maxNodes := minNodes + r.Intn(5)
+
+		pool := NodePool{
+			MachineType: nodePoolMachineTypes[r.Intn(len(nodePoolMachineTypes))],
+			OsDiskSize:  64 * (1 + r.Intn(4)),
+			MinNodes:    minNodes,
+			MaxNodes:    maxNodes,
+			MaxPods:     30 + r.Intn(221),
+			NodeTaints:  []string{},
+			NodeLabels:  map[string]string{},
+		}
+
+		for j := r.Intn(3); j > 0; j-- {
+			effect := nodePoolTaintEffects[r.Intn(len(nodePoolTaintEffects))]
+			pool.NodeTaints = append(pool.NodeTaints, fmt.Sprintf("test.sas.com/%s=%s:%s", randomLowerAlnum(r, 6), randomLowerAlnum(r, 6), effect))
+		}
+		for j := r.Intn(4); j > 0; j-- {
+			pool.NodeLabels["test.sas.com/"+randomLowerAlnum(r, 6)] = randomLowerAlnum(r, 8)
+		}
+		if r.Intn(2) == 0 {
+			zones := r.Perm(len(nodePoolZones))[:1+r.Intn(len(nodePoolZones))]
+			sort.Ints(zones)
+			for _, z := range zones {
+				pool.AvailabilityZones = append(pool.AvailabilityZones, nodePoolZones[z])
+			}
+		}
+
+		sample.NodePools[name] = pool
+	}
+
+	return sample
+}
+
+// Prefix returns a valid, seed specific prefix so that each sample is cached separately.
+func (s NodePoolsSample) Prefix() string {
+	return "np-" + strconv.FormatInt(s.Seed, 36)
+}
+
+// SetVariables adds the sample to a set of terraform input variables.
+func (s NodePoolsSample) SetVariables(variables map[string]interface{}) {
+	nodePools := make(map[string]interface{})
+	for name, pool := range s.NodePools {
+		value := map[string]interface{}{
+			"machine_type": pool.MachineType,
+			"os_disk_size": pool.OsDiskSize,
+			"min_nodes":    pool.MinNodes,
+			"max_nodes":    pool.MaxNodes,
+			"max_pods":     pool.MaxPods,
+			"node_taints":  pool.NodeTaints,
+			"node_labels":  pool.NodeLabels,
+		}
+		if pool.AvailabilityZones != nil {
+			value["availability_zones"] = pool.AvailabilityZones
+		}
+		nodePools[name] = value
+	}
+
+	variables["prefix"] = s.Prefix()
+	variables["node_pools"] = nodePools
+	variables["node_pools_proximity_placement"] = s.ProximityPlacement
+}
+
+func randomLowerAlnum(r *rand.Rand, length int) string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = chars[r.Intn(len(chars))]
+	}
+	return string(b)
+}
@@ -0,0 +1,38 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerateNodePools verifies that generated node_pools samples are reproducible from their seed
+// and only contain values accepted by the node_pools variable.
+func TestGenerateNodePools(t *testing.T) {
+	nodePoolName := regexp.MustCompile(`^[a-z][a-z0-9]{0,11}$`)
+
+	for seed := int64(0); seed < 100; seed++ {
+		sample := GenerateNodePools(seed)
+		assert.Equal(t, sample, GenerateNodePools(seed), "Sample is not reproducible from seed %d", seed)
+		assert.NotEmpty(t, sample.NodePools, "Sample for seed %d has no node pools", seed)
+
+		for name, pool := range sample.NodePools {
+			assert.Regexp(t, nodePoolName, name, "Invalid node pool name for seed %d", seed)
+			assert.LessOrEqual(t, pool.MinNodes, pool.MaxNodes, "min_nodes exceeds max_nodes for seed %d", seed)
+			assert.Contains(t, nodePoolMachineTypes, pool.MachineType)
+			for _, zone := range pool.AvailabilityZones {
+				assert.Contains(t, nodePoolZones, zone)
+			}
+		}
+
+		variables := map[string]interface{}{}
+		sample.SetVariables(variables)
+		assert.Regexp(t, `^[a-z][-0-9a-z]*[0-9a-z]$`, variables["prefix"])
+		assert.Less(t, len(variables["prefix"].(string)), 21)
+		assert.Len(t, variables["node_pools"], len(sample.NodePools))
+	}
+}
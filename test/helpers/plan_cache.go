@@ -4,13 +4,12 @@
 package helpers
 
 import (
+	"fmt"
 	"github.com/gruntwork-io/terratest/modules/terraform"
-	test_structure "github.com/gruntwork-io/terratest/modules/test-structure"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 	"testing"
 )
@@ -19,8 +18,16 @@ var lock = &sync.Mutex{}
 var CACHE *PlanCache
 
 type PlanCache struct {
-	plans map[string]*terraform.PlanStruct
-	lock  sync.Mutex
+	plans  map[string]*terraform.PlanStruct
+	graphs map[string]*planGraph
+	lock   sync.Mutex
+}
+
+// planGraph is the graph of a cached plan, drawn from the plan file before it is removed, or the error of
+// terraform graph.
+type planGraph struct {
+	graph *Graph
+	err   error
 }
 
 func getCache() *PlanCache {
@@ -29,7 +36,8 @@ func getCache() *PlanCache {
 		defer lock.Unlock()
 		if CACHE == nil {
 			CACHE = &PlanCache{
-				plans: make(map[string]*terraform.PlanStruct),
+				plans:  make(map[string]*terraform.PlanStruct),
+				graphs: make(map[string]*planGraph),
 			}
 		}
 	}
@@ -38,28 +46,50 @@ func getCache() *PlanCache {
 
 // Not worrying about expiration since this is for a single run of tests.
 func (c *PlanCache) get(key string, planFn func() *terraform.PlanStruct) *terraform.PlanStruct {
+	plan, _ := c.getWithGraph(key, func() (*terraform.PlanStruct, *planGraph) {
+		return planFn(), nil
+	})
+	return plan
+}
+
+// getWithGraph returns the cached plan of the key and its graph, which is nil if planFn did not draw it.
+func (c *PlanCache) getWithGraph(key string, planFn func() (*terraform.PlanStruct, *planGraph)) (*terraform.PlanStruct, *planGraph) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
 	plan, ok := c.plans[key]
 	if !ok {
-		c.plans[key] = planFn()
-		return c.plans[key]
+		plan, c.graphs[key] = planFn()
+		c.plans[key] = plan
 	}
-	return plan
+	return plan, c.graphs[key]
 }
 
 func GetDefaultPlan(t *testing.T) *terraform.PlanStruct {
 	return GetPlanFromCache(t, GetDefaultPlanVars(t))
 }
 
+// GetPlanFromCache returns the cached plan for the prefix and location of the variables, creating it if needed.
 func GetPlanFromCache(t *testing.T, variables map[string]interface{}) *terraform.PlanStruct {
-	return getCache().get(variables["prefix"].(string), func() *terraform.PlanStruct {
-		return GetPlan(t, variables)
+	plan, _ := getPlanAndGraphFromCache(t, variables)
+	return plan
+}
+
+// getPlanAndGraphFromCache returns the cached plan for the prefix and location of the variables and its graph,
+// creating them if needed. The graph is drawn from the plan file while it exists, see GetGraph.
+func getPlanAndGraphFromCache(t *testing.T, variables map[string]interface{}) (*terraform.PlanStruct, *planGraph) {
+	RequireSuite(t, PlanSuite)
+	key := fmt.Sprintf("%s/%v", variables["prefix"], variables["location"])
+	return getCache().getWithGraph(key, func() (*terraform.PlanStruct, *planGraph) {
+		plan, graph, err := initPlan(t, variables, nil, true)
+		require.NotNil(t, plan)
+		require.NoError(t, err)
+		return plan, graph
 	})
 }
 
 func GetPlan(t *testing.T, variables map[string]interface{}) *terraform.PlanStruct {
+	RequireSuite(t, PlanSuite)
 	plan, err := InitPlanWithVariables(t, variables)
 	require.NotNil(t, plan)
 	require.NoError(t, err)
@@ -68,40 +98,85 @@ func GetPlan(t *testing.T, variables map[string]interface{}) *terraform.PlanStru
 
 // InitPlanWithVariables returns a *terraform.PlanStruct
 func InitPlanWithVariables(t *testing.T, variables map[string]interface{}) (*terraform.PlanStruct, error) {
+	return InitPlanWithDataStubs(t, variables, nil)
+}
+
+// GetPlanWithDataStubs returns the plan of the variables with the data sources of the stubs replaced by their
+// values, see WriteDataStubs.
+func GetPlanWithDataStubs(t *testing.T, variables map[string]interface{}, stubs []DataStub) *terraform.PlanStruct {
+	RequireSuite(t, PlanSuite)
+	plan, err := InitPlanWithDataStubs(t, variables, stubs)
+	require.NotNil(t, plan)
+	require.NoError(t, err)
+	return plan
+}
+
+// InitPlanWithDataStubs returns the plan of the variables, with the override files of the stubs written to the
+// temporary copy of the repository.
+func InitPlanWithDataStubs(t *testing.T, variables map[string]interface{}, stubs []DataStub) (*terraform.PlanStruct, error) {
+	plan, _, err := initPlan(t, variables, stubs, false)
+	return plan, err
+}
+
+// initPlan returns the plan of the variables, see InitPlanWithDataStubs, and when drawGraph is set, the graph
+// of the plan file drawn by terraform graph -plan before the plan file and the copy of the repository are removed.
+func initPlan(t *testing.T, variables map[string]interface{}, stubs []DataStub, drawGraph bool) (*terraform.PlanStruct, *planGraph, error) {
+	resolveProcessAzureCredential(t)
+
 	// Create a temporary plan file
 	planFileName := "testplan-" + variables["prefix"].(string) + ".tfplan"
 	planFilePath := filepath.Join(os.TempDir(), planFileName)
 	defer os.Remove(planFilePath)
 
 	// Copy the terraform folder to a temp folder
-	tempTestFolder := test_structure.CopyTerraformFolderToTemp(t, "../../", "")
-	// Get the path to the parent folder for clean up
-	tempTestFolderSlice := strings.Split(tempTestFolder, string(os.PathSeparator))
-	tempTestFolderPath := strings.Join(tempTestFolderSlice[:len(tempTestFolderSlice)-1], string(os.PathSeparator))
-	defer os.RemoveAll(tempTestFolderPath)
+	tempTestFolder := copyRepository(t, os.TempDir())
+	defer removeRepositoryCopy(t, tempTestFolder)
+	if err := WriteDataStubs(tempTestFolder, stubs); err != nil {
+		return nil, nil, err
+	}
 
-	// Set up Terraform options
+	// Set up Terraform options, redacting the secrets of the variables in the terraform command logs
+	RegisterSensitiveVariables(variables)
 	terraformOptions := &terraform.Options{
 		TerraformDir: tempTestFolder,
 		Vars:         variables,
 		PlanFilePath: planFilePath,
 		NoColor:      true,
+		Logger:       RedactingLogger,
 	}
 
-	return terraform.InitAndPlanAndShowWithStructE(t, terraformOptions)
+	plan, err := terraform.InitAndPlanAndShowWithStructE(t, terraformOptions)
+	if plan != nil {
+		RegisterPlanSecrets(plan)
+	}
+	if err != nil || !drawGraph {
+		return plan, nil, err
+	}
+	graph := &planGraph{}
+	graph.graph, graph.err = DrawGraph(t, terraformOptions)
+	return plan, graph, nil
 }
 
-// GetDefaultPlanVars returns a map of default terratest variables
+// GetDefaultPlanVars returns a map of default terratest variables, read from the tfvars file of the test profile
 func GetDefaultPlanVars(t *testing.T) map[string]interface{} {
-	tfVarsPath := "../../examples/sample-input-defaults.tfvars"
+	variables := GetExamplePlanVars(t, GetTestConfig(t).TfvarsFile)
+	variables["prefix"] = "default"
+
+	return variables
+}
+
+// GetExamplePlanVars returns the variables of the given file in the examples folder with the
+// location and public access cidrs replaced by the ones of the test profile. The location is the
+// first test location, see GetTestLocations. The caller must set a unique prefix.
+func GetExamplePlanVars(t *testing.T, exampleFileName string) map[string]interface{} {
+	tfVarsPath := filepath.Join("../../examples", exampleFileName)
 
 	variables := make(map[string]interface{})
 	err := terraform.GetAllVariablesFromVarFileE(t, tfVarsPath, &variables)
 	assert.NoError(t, err)
 
-	variables["prefix"] = "default"
-	variables["location"] = "eastus"
-	variables["default_public_access_cidrs"] = []string{"123.45.67.89/16"}
+	GetTestLocations(t)[0].SetVariables(variables)
+	variables["default_public_access_cidrs"] = requirePublicCIDRs(t)
 
 	return variables
 }
@@ -0,0 +1,242 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+)
+
+// Actions of ResourceAction. A replacement is a delete and a create in either order, and ActionReplace is
+// returned for both.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionReplace = "replace"
+	ActionRead    = "read"
+	ActionNoOp    = "no-op"
+)
+
+// RetrieveFromResourceChanges Retriever that gets the value of a jsonpath query on the JSON form of the planned
+// change of a resource, e.g. {$.change.actions}, {$.change.after_unknown.fqdn} or {$.change.replace_paths}.
+// It returns "nil" if the plan has no change for the address, and a *JsonPathNotFoundError if the query does
+// not resolve.
+func RetrieveFromResourceChanges(plan *terraform.PlanStruct, resourceMapName string, jsonPath string) (string, error) {
+	change, exists := plan.ResourceChangesMap[resourceMapName]
+	if !exists {
+		return "nil", nil
+	}
+	data, err := json.Marshal(change)
+	if err != nil {
+		return "", err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", err
+	}
+	return getJsonPath(value, jsonPath, true)
+}
+
+// RetrieveResourceAction Retriever that gets the action of the planned change of a resource, one of the
+// Action constants, or "nil" if the plan has no change for the address. The jsonPath is not used.
+func RetrieveResourceAction(plan *terraform.PlanStruct, resourceMapName string, jsonPath string) (string, error) {
+	change, exists := plan.ResourceChangesMap[resourceMapName]
+	if !exists {
+		return "nil", nil
+	}
+	return ResourceAction(change), nil
+}
+
+// ResourceAction returns the action of the planned change of a resource, one of the Action constants.
+func ResourceAction(change *tfjson.ResourceChange) string {
+	if change.Change == nil {
+		return ActionNoOp
+	}
+	actions := change.Change.Actions
+	switch {
+	case actions.Replace():
+		return ActionReplace
+	case actions.Create():
+		return ActionCreate
+	case actions.Update():
+		return ActionUpdate
+	case actions.Delete():
+		return ActionDelete
+	case actions.Read():
+		return ActionRead
+	}
+	return ActionNoOp
+}
+
+// ReplacePaths returns the attributes of a resource that force its replacement, e.g. default_node_pool[0].name,
+// or nil if the plan has no change for the address.
+func ReplacePaths(plan *terraform.PlanStruct, address string) []string {
+	change, exists := plan.ResourceChangesMap[address]
+	if !exists || change.Change == nil {
+		return nil
+	}
+	return replacePaths(change.Change)
+}
+
+func replacePaths(change *tfjson.Change) []string {
+	var paths []string
+	for _, path := range change.ReplacePaths {
+		steps, ok := path.([]interface{})
+		if !ok {
+			continue
+		}
+		var attribute strings.Builder
+		for _, step := range steps {
+			switch typed := step.(type) {
+			case string:
+				if attribute.Len() > 0 {
+					attribute.WriteString(".")
+				}
+				attribute.WriteString(typed)
+			case float64:
+				fmt.Fprintf(&attribute, "[%d]", int(typed))
+			default:
+				fmt.Fprintf(&attribute, "[%v]", typed)
+			}
+		}
+		paths = append(paths, attribute.String())
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Replacements returns the resources whose address starts with prefix that the plan replaces, with the
+// attributes that force the replacement, e.g. "module.aks.azurerm_kubernetes_cluster.aks (delete, create): default_node_pool[0].name".
+// The prefix matches whole address parts, so module.aks does not match module.aks_node_pool.
+func Replacements(plan *terraform.PlanStruct, prefix string) []string {
+	var replacements []string
+	for address, change := range plan.ResourceChangesMap {
+		if change.Change == nil || !change.Change.Actions.Replace() || !hasAddressPrefix(address, prefix) {
+			continue
+		}
+		replacements = append(replacements, describeChange(address, change.Change))
+	}
+	sort.Strings(replacements)
+	return replacements
+}
+
+// describeChange formats the address of a change with its actions and the attributes that force a replacement.
+func describeChange(address string, change *tfjson.Change) string {
+	if change == nil {
+		return address
+	}
+	actions := make([]string, len(change.Actions))
+	for i, action := range change.Actions {
+		actions[i] = string(action)
+	}
+	description := fmt.Sprintf("%s (%s)", address, strings.Join(actions, ", "))
+	if paths := replacePaths(change); len(paths) > 0 {
+		description += ": " + strings.Join(paths, ", ")
+	}
+	return description
+}
+
+// hasAddressPrefix reports whether the address is prefix, or starts with it followed by a separator.
+func hasAddressPrefix(address string, prefix string) bool {
+	if prefix == "" || address == prefix {
+		return true
+	}
+	return strings.HasPrefix(address, prefix) && strings.ContainsAny(address[len(prefix):len(prefix)+1], ".[")
+}
+
+// UnknownAttributes returns the attributes of the planned change of a resource that are unknown until apply,
+// e.g. fqdn or kube_config, or nil if the plan has no change for the address.
+func UnknownAttributes(plan *terraform.PlanStruct, address string) []string {
+	change, exists := plan.ResourceChangesMap[address]
+	if !exists || change.Change == nil {
+		return nil
+	}
+	return unknownPaths("", change.Change.AfterUnknown)
+}
+
+// unknownPaths returns the paths that after_unknown marks as unknown, without descending into them.
+func unknownPaths(path string, unknown interface{}) []string {
+	if isUnknown(unknown) {
+		return []string{path}
+	}
+	var paths []string
+	switch typed := unknown.(type) {
+	case map[string]interface{}:
+		for key, value := range typed {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			paths = append(paths, unknownPaths(childPath, value)...)
+		}
+	case []interface{}:
+		for i, value := range typed {
+			paths = append(paths, unknownPaths(fmt.Sprintf("%s[%d]", path, i), value)...)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// AssertResourceAction asserts that the plan has a change for the address with the action, one of the Action
+// constants.
+func AssertResourceAction(t *testing.T, plan *terraform.PlanStruct, address string, action string, msgAndArgs ...interface{}) bool {
+	change, exists := plan.ResourceChangesMap[address]
+	if !exists {
+		return assert.Fail(t, fmt.Sprintf("The plan has no change for %s", address), msgAndArgs...)
+	}
+	if actual := ResourceAction(change); actual != action {
+		return assert.Fail(t, fmt.Sprintf("The change of %s is a %s, not a %s", describeChange(address, change.Change), actual, action), msgAndArgs...)
+	}
+	return true
+}
+
+// AssertNoReplacements asserts that the plan replaces no resource whose address starts with prefix, e.g.
+// module.aks, and lists the attributes that force each replacement otherwise.
+func AssertNoReplacements(t *testing.T, plan *terraform.PlanStruct, prefix string, msgAndArgs ...interface{}) bool {
+	replacements := Replacements(plan, prefix)
+	if len(replacements) == 0 {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("The plan replaces %d resources under %s:\n  %s", len(replacements), prefix,
+		strings.Join(replacements, "\n  ")), msgAndArgs...)
+}
+
+// AssertUnknownUntilApply asserts that the attributes of the planned change of a resource, e.g. fqdn or
+// kube_config[0].host, are unknown until apply, as a whole or as part of an unknown parent attribute.
+func AssertUnknownUntilApply(t *testing.T, plan *terraform.PlanStruct, address string, attributes []string, msgAndArgs ...interface{}) bool {
+	if _, exists := plan.ResourceChangesMap[address]; !exists {
+		return assert.Fail(t, fmt.Sprintf("The plan has no change for %s", address), msgAndArgs...)
+	}
+	unknown := UnknownAttributes(plan, address)
+	var known []string
+	for _, attribute := range attributes {
+		if !isUnknownAttribute(attribute, unknown) {
+			known = append(known, attribute)
+		}
+	}
+	if len(known) == 0 {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("%s has attributes known before apply: %s, the unknown attributes are: %s", address,
+		strings.Join(known, ", "), strings.Join(unknown, ", ")), msgAndArgs...)
+}
+
+// isUnknownAttribute reports whether the attribute is one of the unknown paths or nested in one of them.
+func isUnknownAttribute(attribute string, unknown []string) bool {
+	for _, path := range unknown {
+		if path == "" || attribute == path || strings.HasPrefix(attribute, path+".") || strings.HasPrefix(attribute, path+"[") {
+			return true
+		}
+	}
+	return false
+}
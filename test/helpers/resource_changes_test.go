@@ -0,0 +1,87 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+)
+
+// resourceChangesPlan is a plan of an upgrade that replaces the cluster, creates a node pool and updates the
+// virtual network in place.
+func resourceChangesPlan() *terraform.PlanStruct {
+	cluster := resourceChange(tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}, nil, nil,
+		map[string]interface{}{"fqdn": true, "kube_config": true, "default_node_pool": []interface{}{map[string]interface{}{"node_count": false}}})
+	cluster.Change.ReplacePaths = []interface{}{[]interface{}{"default_node_pool", float64(0), "name"}, []interface{}{"dns_prefix"}}
+	return &terraform.PlanStruct{
+		ResourceChangesMap: map[string]*tfjson.ResourceChange{
+			"module.aks.azurerm_kubernetes_cluster.aks": cluster,
+			"module.aks_node_pool.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": resourceChange(
+				tfjson.Actions{tfjson.ActionCreate, tfjson.ActionDelete}, nil, nil, nil),
+			"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": resourceChange(
+				tfjson.Actions{tfjson.ActionCreate}, nil, nil, map[string]interface{}{"id": true}),
+			"module.vnet.azurerm_virtual_network.vnet[0]": resourceChange(tfjson.Actions{tfjson.ActionUpdate}, nil, nil, nil),
+			"module.vnet.azurerm_subnet.subnet[\"aks\"]":  resourceChange(tfjson.Actions{tfjson.ActionNoop}, nil, nil, nil),
+		},
+	}
+}
+
+// TestResourceChangeRetrievers verifies that the actions, unknown attributes and replace paths of a change are
+// retrieved, and that a missing address retrieves "nil".
+func TestResourceChangeRetrievers(t *testing.T) {
+	plan := resourceChangesPlan()
+	cluster := "module.aks.azurerm_kubernetes_cluster.aks"
+
+	actions, err := RetrieveFromResourceChanges(plan, cluster, "{$.change.actions}")
+	assert.NoError(t, err)
+	assert.Equal(t, `["delete","create"]`, actions)
+	fqdn, err := RetrieveFromResourceChanges(plan, cluster, "{$.change.after_unknown.fqdn}")
+	assert.NoError(t, err)
+	assert.Equal(t, "true", fqdn)
+	missing, err := RetrieveFromResourceChanges(plan, "module.aks.azurerm_kubernetes_cluster.missing", "{$.change.actions}")
+	assert.NoError(t, err)
+	assert.Equal(t, "nil", missing)
+	_, err = RetrieveFromResourceChanges(plan, cluster, "{$.change.after_unknown.id}")
+	assert.IsType(t, &JsonPathNotFoundError{}, err)
+
+	for address, action := range map[string]string{
+		cluster: ActionReplace,
+		"module.aks_node_pool.azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]":       ActionReplace,
+		"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": ActionCreate,
+		"module.vnet.azurerm_virtual_network.vnet[0]":                                            ActionUpdate,
+		"module.vnet.azurerm_subnet.subnet[\"aks\"]":                                             ActionNoOp,
+		"azurerm_resource_group.aks_rg[0]":                                                       "nil",
+	} {
+		actual, err := RetrieveResourceAction(plan, address, "")
+		assert.NoError(t, err)
+		assert.Equal(t, action, actual, address)
+	}
+
+	assert.Equal(t, []string{"default_node_pool[0].name", "dns_prefix"}, ReplacePaths(plan, cluster))
+	assert.Equal(t, []string{"fqdn", "kube_config"}, UnknownAttributes(plan, cluster))
+}
+
+// TestResourceChangeAssertions verifies that the replacements are listed with the attributes that force them,
+// that the assertions pass on the expected changes and that nested attributes of unknown ones are unknown.
+func TestResourceChangeAssertions(t *testing.T) {
+	plan := resourceChangesPlan()
+	cluster := "module.aks.azurerm_kubernetes_cluster.aks"
+
+	assert.Equal(t, []string{"module.aks.azurerm_kubernetes_cluster.aks (delete, create): default_node_pool[0].name, dns_prefix"},
+		Replacements(plan, "module.aks"))
+	assert.Len(t, Replacements(plan, ""), 2)
+	assert.Empty(t, Replacements(plan, "module.vnet"))
+
+	assert.True(t, AssertResourceAction(t, plan, "module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]", ActionCreate))
+	assert.True(t, AssertNoReplacements(t, plan, "module.node_pools"))
+	assert.True(t, AssertUnknownUntilApply(t, plan, cluster, []string{"fqdn", "kube_config[0].host"}))
+
+	unknown := UnknownAttributes(plan, cluster)
+	assert.True(t, isUnknownAttribute("kube_config[0].host", unknown))
+	assert.False(t, isUnknownAttribute("default_node_pool[0].node_count", unknown))
+	assert.False(t, isUnknownAttribute("fqdn_suffix", unknown))
+}
@@ -0,0 +1,245 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"test/report"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// SecretHashesFileEnvVar names the file that the hashes of the secret values found by the helpers are appended
+// to. The test runner sets it and fails the run if one of the secrets appears in the test output.
+const SecretHashesFileEnvVar = "SECRET_HASHES_FILE"
+
+// Redacted replaces secret values in failure messages, logs and dumped plans.
+const Redacted = "(sensitive value)"
+
+// SensitiveNamePattern matches the names of variables and attributes whose string values are secrets even if
+// they are not marked sensitive, such as the client_secret variable and the postgres_servers passwords.
+var SensitiveNamePattern = regexp.MustCompile(`(?i)(secret|password|token|private_key|kube_config|kube_admin_config|access_key|connection_string)`)
+
+// minSecretLength is the length below which values are not redacted, since they would mangle unrelated
+// output, e.g. an empty client_secret or a sensitive "true".
+const minSecretLength = 6
+
+var secrets = struct {
+	sync.Mutex
+	values map[string]bool
+}{values: make(map[string]bool)}
+
+// RegisterSecrets adds values to the secrets that Redact replaces and records their hashes in
+// SECRET_HASHES_FILE.
+func RegisterSecrets(values ...string) {
+	secrets.Lock()
+	defer secrets.Unlock()
+	hashesFile := os.Getenv(SecretHashesFileEnvVar)
+	for _, value := range values {
+		if len(value) < minSecretLength || secrets.values[value] {
+			continue
+		}
+		secrets.values[value] = true
+		if hashesFile != "" {
+			if err := report.AppendSecretHash(hashesFile, value); err != nil {
+				fmt.Fprintf(os.Stderr, "Error recording a secret hash in %s: %s\n", hashesFile, err)
+			}
+		}
+	}
+}
+
+// RegisterSensitiveVariables registers the string values of the variables, at any depth, whose name matches
+// SensitiveNamePattern. Call it before the variables are passed to terraform.
+func RegisterSensitiveVariables(variables map[string]interface{}) {
+	RegisterSecrets(sensitiveByName(variables, false)...)
+}
+
+// RegisterPlanSecrets registers the sensitive values of the plan.
+func RegisterPlanSecrets(plan *terraform.PlanStruct) {
+	RegisterSecrets(SensitiveValues(plan)...)
+}
+
+// SensitiveValues returns the string values that the plan marks as sensitive in the sensitive_values of the
+// planned resources, the before_sensitive and after_sensitive of the resource and output changes and the
+// variable declarations, along with the values of the variables whose name matches SensitiveNamePattern.
+func SensitiveValues(plan *terraform.PlanStruct) []string {
+	var values []string
+	for _, resource := range plan.ResourcePlannedValuesMap {
+		var markers interface{}
+		if len(resource.SensitiveValues) > 0 && json.Unmarshal(resource.SensitiveValues, &markers) == nil {
+			values = append(values, markedValues(resource.AttributeValues, markers)...)
+		}
+	}
+	for _, change := range plan.RawPlan.ResourceChanges {
+		if change.Change != nil {
+			values = append(values, markedValues(change.Change.Before, change.Change.BeforeSensitive)...)
+			values = append(values, markedValues(change.Change.After, change.Change.AfterSensitive)...)
+		}
+	}
+	for _, change := range plan.RawPlan.OutputChanges {
+		values = append(values, markedValues(change.Before, change.BeforeSensitive)...)
+		values = append(values, markedValues(change.After, change.AfterSensitive)...)
+	}
+	for name, variable := range plan.RawPlan.Variables {
+		if variable == nil {
+			continue
+		}
+		declared := plan.RawPlan.Config != nil && plan.RawPlan.Config.RootModule != nil &&
+			plan.RawPlan.Config.RootModule.Variables[name] != nil && plan.RawPlan.Config.RootModule.Variables[name].Sensitive
+		values = append(values, sensitiveByName(map[string]interface{}{name: variable.Value}, declared)...)
+	}
+
+	sort.Strings(values)
+	unique := values[:0]
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// Redact replaces the registered secret values in the text, along with their JSON encoded form.
+func Redact(text string) string {
+	secrets.Lock()
+	values := make([]string, 0, 2*len(secrets.values))
+	for value := range secrets.values {
+		values = append(values, value)
+		if encoded, err := json.Marshal(value); err == nil && string(encoded[1:len(encoded)-1]) != value {
+			values = append(values, string(encoded[1:len(encoded)-1]))
+		}
+	}
+	secrets.Unlock()
+
+	// Replace longer values first so a secret containing another one is replaced whole
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, value := range values {
+		text = strings.ReplaceAll(text, value, Redacted)
+	}
+	return text
+}
+
+// SecretHash returns the hash of a secret value, so tests can compare secrets without showing them.
+func SecretHash(value string) string {
+	return report.SecretHash(value)
+}
+
+// HashedRetriever returns a Retriever that returns the SecretHash of the value of retriever, or of
+// RetrieveFromResourcePlannedValuesMap if it is nil. Use it with the expected hash for secret attributes.
+func HashedRetriever(retriever Retriever) Retriever {
+	if retriever == nil {
+		retriever = RetrieveFromResourcePlannedValuesMap
+	}
+	return func(plan *terraform.PlanStruct, resourceMapName string, jsonPath string) (string, error) {
+		value, err := retriever(plan, resourceMapName, jsonPath)
+		if err != nil {
+			return "", err
+		}
+		return SecretHash(value), nil
+	}
+}
+
+// RedactingLogger logs like the default terratest logger with the registered secrets redacted. It is set on
+// the Terraform options of the helpers, since terratest logs the -var arguments of each command.
+var RedactingLogger = logger.New(redactingLogger{})
+
+type redactingLogger struct{}
+
+func (redactingLogger) Logf(t terratesting.TestingT, format string, args ...interface{}) {
+	logger.DoLog(t, 3, os.Stdout, Redact(fmt.Sprintf(format, args...)))
+}
+
+// redactingT is an assert.TestingT that redacts the registered secrets in failure messages.
+type redactingT struct {
+	t *testing.T
+}
+
+func (r redactingT) Errorf(format string, args ...interface{}) {
+	r.t.Helper()
+	r.t.Errorf("%s", Redact(fmt.Sprintf(format, args...)))
+}
+
+func (r redactingT) Helper() {
+	r.t.Helper()
+}
+
+// markedValues returns the string values of value that the sensitivity markers mark as sensitive. markers is
+// true for a sensitive value, or an object or list with the markers of the nested values.
+func markedValues(value interface{}, markers interface{}) []string {
+	switch marker := markers.(type) {
+	case bool:
+		if marker {
+			return stringLeaves(value)
+		}
+	case map[string]interface{}:
+		valueMap, _ := value.(map[string]interface{})
+		var values []string
+		for key, nested := range marker {
+			values = append(values, markedValues(valueMap[key], nested)...)
+		}
+		return values
+	case []interface{}:
+		valueList, _ := value.([]interface{})
+		var values []string
+		for i, nested := range marker {
+			if i < len(valueList) {
+				values = append(values, markedValues(valueList[i], nested)...)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// sensitiveByName returns the string values under the keys that match SensitiveNamePattern, or all string values
+// if sensitive is set.
+func sensitiveByName(value interface{}, sensitive bool) []string {
+	if sensitive {
+		return stringLeaves(value)
+	}
+	var values []string
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range typed {
+			values = append(values, sensitiveByName(nested, SensitiveNamePattern.MatchString(key))...)
+		}
+	case []interface{}:
+		for _, nested := range typed {
+			values = append(values, sensitiveByName(nested, false)...)
+		}
+	}
+	return values
+}
+
+// stringLeaves returns the strings in the value at any depth.
+func stringLeaves(value interface{}) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case map[string]interface{}:
+		var values []string
+		for _, nested := range typed {
+			values = append(values, stringLeaves(nested)...)
+		}
+		return values
+	case []interface{}:
+		var values []string
+		for _, nested := range typed {
+			values = append(values, stringLeaves(nested)...)
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// SensitiveOutputPolicy defines the outputs that carry secrets and must be marked sensitive.
+type SensitiveOutputPolicy struct {
+	// NamePattern matches the names of the outputs that carry secrets
+	NamePattern *regexp.Regexp
+	// Sensitive lists the outputs that carry secrets whatever their name. They must be declared, so a renamed
+	// output is not silently dropped from the policy
+	Sensitive []string
+	// Exempt lists the outputs that match NamePattern but carry no secret
+	Exempt []string
+}
+
+// DefaultSensitiveOutputPolicy is the policy for the outputs of outputs.tf. postgres_servers carries the admin
+// passwords of the servers, aks_host comes from the kube_config and rwx_filestore_config carries the client
+// secret of the service principal.
+var DefaultSensitiveOutputPolicy = SensitiveOutputPolicy{
+	NamePattern: SensitiveNamePattern,
+	Sensitive: []string{
+		"aks_cluster_password",
+		"aks_host",
+		"cr_admin_password",
+		"kube_config",
+		"postgres_servers",
+		"rwx_filestore_config",
+	},
+}
+
+// RequiresSensitive returns whether the output must be marked sensitive.
+func (p SensitiveOutputPolicy) RequiresSensitive(name string) bool {
+	if containsString(p.Sensitive, name) {
+		return true
+	}
+	return p.NamePattern != nil && p.NamePattern.MatchString(name) && !containsString(p.Exempt, name)
+}
+
+// AuditSensitiveOutputs returns the outputs of the plan that the policy requires to be sensitive but that are
+// not declared sensitive in the configuration, or not sensitive in the output changes or planned values, along
+// with the outputs listed in the policy that are not declared and the outputs that are not sensitive but whose
+// value contains one of the SensitiveValues of the plan, whatever the policy.
+func AuditSensitiveOutputs(plan *terraform.PlanStruct, policy SensitiveOutputPolicy) []string {
+	var violations []string
+	for _, name := range leakingOutputs(plan) {
+		violations = append(violations, fmt.Sprintf("output %s is not sensitive, but its value contains a sensitive value", name))
+	}
+	raw := plan.RawPlan
+	var declared map[string]bool
+	if raw.Config != nil && raw.Config.RootModule != nil {
+		declared = make(map[string]bool)
+		for name, output := range raw.Config.RootModule.Outputs {
+			declared[name] = true
+			if policy.RequiresSensitive(name) && (output == nil || !output.Sensitive) {
+				violations = append(violations, fmt.Sprintf("output %s is not declared sensitive", name))
+			}
+		}
+		for _, name := range policy.Sensitive {
+			if !declared[name] {
+				violations = append(violations, fmt.Sprintf("output %s of the sensitive output policy is not declared", name))
+			}
+		}
+	}
+	for name, change := range raw.OutputChanges {
+		if policy.RequiresSensitive(name) && change != nil && change.AfterSensitive != true {
+			violations = append(violations, fmt.Sprintf("output %s is not sensitive in the output changes", name))
+		}
+	}
+	if raw.PlannedValues != nil {
+		for name, output := range raw.PlannedValues.Outputs {
+			if policy.RequiresSensitive(name) && output != nil && !output.Sensitive {
+				violations = append(violations, fmt.Sprintf("output %s is not sensitive in the planned values", name))
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// leakingOutputs returns the outputs that are not sensitive in the output changes or planned values, but whose
+// value contains one of the SensitiveValues of the plan, e.g. a secret variable encoded in a JSON string.
+func leakingOutputs(plan *terraform.PlanStruct) []string {
+	var secrets []string
+	for _, value := range SensitiveValues(plan) {
+		if len(value) >= minSecretLength {
+			secrets = append(secrets, value)
+		}
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	values := make(map[string][]string)
+	for name, change := range plan.RawPlan.OutputChanges {
+		if change != nil && change.AfterSensitive != true {
+			values[name] = append(values[name], stringLeaves(change.After)...)
+		}
+	}
+	if plan.RawPlan.PlannedValues != nil {
+		for name, output := range plan.RawPlan.PlannedValues.Outputs {
+			if output != nil && !output.Sensitive {
+				values[name] = append(values[name], stringLeaves(output.Value)...)
+			}
+		}
+	}
+	var leaking []string
+	for name, leaves := range values {
+		if containsSecret(leaves, secrets) {
+			leaking = append(leaking, name)
+		}
+	}
+	sort.Strings(leaking)
+	return leaking
+}
+
+func containsSecret(values []string, secrets []string) bool {
+	for _, value := range values {
+		for _, secret := range secrets {
+			if strings.Contains(value, secret) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditSensitiveOutputs verifies that outputs carrying secrets by name or by the explicit list are reported
+// when exposed in clear text, and that exempt and other outputs are not.
+func TestAuditSensitiveOutputs(t *testing.T) {
+	plan := &terraform.PlanStruct{
+		RawPlan: tfjson.Plan{
+			Config: &tfjson.Config{
+				RootModule: &tfjson.ConfigModule{
+					Outputs: map[string]*tfjson.ConfigOutput{
+						"kube_config":          {Sensitive: true},
+						"aks_cluster_password": {Sensitive: false},
+						"postgres_servers":     {Sensitive: true},
+						"token_audience":       {Sensitive: false},
+						"location":             {Sensitive: false},
+					},
+				},
+			},
+			OutputChanges: map[string]*tfjson.Change{
+				"kube_config":      {AfterSensitive: true},
+				"postgres_servers": {AfterSensitive: false},
+				"location":         {AfterSensitive: false},
+			},
+			PlannedValues: &tfjson.StateValues{
+				Outputs: map[string]*tfjson.StateOutput{
+					"kube_config":      {Sensitive: true},
+					"postgres_servers": {Sensitive: false},
+				},
+			},
+		},
+	}
+	policy := SensitiveOutputPolicy{
+		NamePattern: SensitiveNamePattern,
+		Sensitive:   []string{"postgres_servers", "cr_admin_password"},
+		Exempt:      []string{"token_audience"},
+	}
+
+	assert.Equal(t, []string{
+		"output aks_cluster_password is not declared sensitive",
+		"output cr_admin_password of the sensitive output policy is not declared",
+		"output postgres_servers is not sensitive in the output changes",
+		"output postgres_servers is not sensitive in the planned values",
+	}, AuditSensitiveOutputs(plan, policy))
+	assert.True(t, DefaultSensitiveOutputPolicy.RequiresSensitive("cr_admin_password"))
+	assert.True(t, DefaultSensitiveOutputPolicy.RequiresSensitive("rwx_filestore_config"))
+	assert.False(t, DefaultSensitiveOutputPolicy.RequiresSensitive("cr_admin_user"))
+}
+
+// TestAuditLeakingOutputs verifies that an output that is not sensitive is reported when its value contains a
+// secret of the plan, whatever its name, and that short values are ignored.
+func TestAuditLeakingOutputs(t *testing.T) {
+	plan := &terraform.PlanStruct{
+		RawPlan: tfjson.Plan{
+			Variables: map[string]*tfjson.PlanVariable{
+				"client_secret": {Value: "s3cr3t-client-value"},
+				"admin_token":   {Value: "abc"},
+			},
+			OutputChanges: map[string]*tfjson.Change{
+				"storage_config": {After: `{"clientID":"id","clientSecret":"s3cr3t-client-value"}`, AfterSensitive: false},
+				"kube_config":    {After: "s3cr3t-client-value", AfterSensitive: true},
+				"location":       {After: "abc", AfterSensitive: false},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"output storage_config is not sensitive, but its value contains a sensitive value"},
+		AuditSensitiveOutputs(plan, SensitiveOutputPolicy{}))
+}
@@ -0,0 +1,99 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"test/report"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSensitiveValues verifies that the values marked sensitive in the plan and the values of secret variables
+// are found, and nothing else.
+func TestSensitiveValues(t *testing.T) {
+	plan := &terraform.PlanStruct{
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{
+			"module.flex_postgresql[\"default\"].azurerm_postgresql_flexible_server.flexpsql": {
+				AttributeValues: map[string]interface{}{
+					"administrator_login":    "pgadmin",
+					"administrator_password": "my$up3rS3cretPassw0rd",
+				},
+				SensitiveValues: json.RawMessage(`{"administrator_password":true}`),
+			},
+		},
+		RawPlan: tfjson.Plan{
+			OutputChanges: map[string]*tfjson.Change{
+				"kube_config": {After: "apiVersion: v1 kubeconfig", AfterSensitive: true},
+				"prefix":      {After: "terratest-1234", AfterSensitive: false},
+			},
+			Variables: map[string]*tfjson.PlanVariable{
+				"client_secret": {Value: "client-secret-value"},
+				"ssh_key":       {Value: "ssh-rsa declared-sensitive"},
+				"location":      {Value: "eastus2"},
+			},
+			Config: &tfjson.Config{
+				RootModule: &tfjson.ConfigModule{
+					Variables: map[string]*tfjson.ConfigVariable{
+						"ssh_key": {Sensitive: true},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"apiVersion: v1 kubeconfig",
+		"client-secret-value",
+		"my$up3rS3cretPassw0rd",
+		"ssh-rsa declared-sensitive",
+	}, SensitiveValues(plan))
+}
+
+// TestRedact verifies that the registered secrets, their JSON encoded form and the nested passwords of the
+// variables are redacted and their hashes recorded, and that short values are left alone.
+func TestRedact(t *testing.T) {
+	hashesFile := filepath.Join(t.TempDir(), "secret_hashes")
+	t.Setenv(SecretHashesFileEnvVar, hashesFile)
+
+	RegisterSecrets("redact\"quoted", "short")
+	RegisterSensitiveVariables(map[string]interface{}{
+		"postgres_servers": map[string]interface{}{
+			"default": map[string]interface{}{
+				"administrator_password": "nested-password",
+				"sku_name":               "GP_Standard_D4ds_v5",
+			},
+		},
+	})
+
+	assert.Equal(t, "password=(sensitive value) sku=GP_Standard_D4ds_v5 short",
+		Redact("password=nested-password sku=GP_Standard_D4ds_v5 short"))
+	assert.Equal(t, `{"value":"(sensitive value)"}`, Redact(`{"value":"redact\"quoted"}`))
+
+	hashes, err := report.LoadSecretHashes(hashesFile)
+	require.NoError(t, err)
+	leaks, err := hashes.Scan(hashesFile)
+	require.NoError(t, err)
+	assert.Empty(t, leaks)
+	assert.True(t, hashes[len("nested-password")][SecretHash("nested-password")])
+	assert.Nil(t, hashes[len("short")])
+}
+
+// TestHashedRetriever verifies that a secret attribute is compared by its hash.
+func TestHashedRetriever(t *testing.T) {
+	plan := &terraform.PlanStruct{
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{
+			"server": {AttributeValues: map[string]interface{}{"administrator_password": "my$up3rS3cretPassw0rd"}},
+		},
+	}
+
+	value, err := HashedRetriever(nil)(plan, "server", "{$.administrator_password}")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:4be699f80cb74b18c4a27f34d1e0ffa1ff144073a928cf0fa26686996f671c2d", value)
+}
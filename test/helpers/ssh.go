@@ -0,0 +1,280 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHPrivateKeyFileEnvVar names the private key matching the ssh_public_key variable. Defaults to ~/.ssh/id_rsa.
+const SSHPrivateKeyFileEnvVar = "SSH_PRIVATE_KEY_FILE"
+
+// NFSRaidVolume is the logical volume the NFS cloud-init creates from the vm_data_disk disks.
+const NFSRaidVolume = "data-vg01/data-lv01"
+
+// SSHHost is a host and the user to log in as. Address is "host" or "host:port".
+type SSHHost struct {
+	Address string
+	User    string
+}
+
+// SSHRunner runs a command on a host and returns its combined output.
+type SSHRunner func(command string) (string, error)
+
+// SSHHarness runs commands on the jump VM and, through the jump VM, on VMs with only a private IP.
+type SSHHarness struct {
+	Jump            SSHHost
+	Signer          ssh.Signer
+	HostKeyCallback ssh.HostKeyCallback
+	Timeout         time.Duration
+}
+
+// NewSSHHarness returns an SSHHarness that authenticates with the PEM encoded private key. Host keys are
+// not verified since the VMs are created by the test.
+func NewSSHHarness(jump SSHHost, privateKey []byte) (*SSHHarness, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("parsing SSH private key: %w", err)
+	}
+	return &SSHHarness{
+		Jump:            jump,
+		Signer:          signer,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         30 * time.Second,
+	}, nil
+}
+
+// GetSSHHarness returns an SSHHarness for the jump VM of an applied configuration, using the key in SSH_PRIVATE_KEY_FILE.
+func GetSSHHarness(t *testing.T, options *terraform.Options) *SSHHarness {
+	keyFile := os.Getenv(SSHPrivateKeyFileEnvVar)
+	if keyFile == "" {
+		home, err := os.UserHomeDir()
+		require.NoError(t, err)
+		keyFile = filepath.Join(home, ".ssh", "id_rsa")
+	}
+	privateKey, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	jump := SSHHost{
+		Address: terraform.Output(t, options, "jump_public_ip"),
+		User:    terraform.Output(t, options, "jump_admin_username"),
+	}
+	harness, err := NewSSHHarness(jump, privateKey)
+	require.NoError(t, err)
+	return harness
+}
+
+// JumpRunner returns an SSHRunner for the jump VM.
+func (h *SSHHarness) JumpRunner() SSHRunner {
+	return func(command string) (string, error) {
+		client, err := ssh.Dial("tcp", sshAddress(h.Jump.Address), h.clientConfig(h.Jump.User))
+		if err != nil {
+			return "", fmt.Errorf("connecting to jump VM %s: %w", h.Jump.Address, err)
+		}
+		defer client.Close()
+		return runSSHCommand(client, command)
+	}
+}
+
+// PrivateRunner returns an SSHRunner for a VM that is reached by tunneling through the jump VM.
+func (h *SSHHarness) PrivateRunner(host SSHHost) SSHRunner {
+	return func(command string) (string, error) {
+		jumpClient, err := ssh.Dial("tcp", sshAddress(h.Jump.Address), h.clientConfig(h.Jump.User))
+		if err != nil {
+			return "", fmt.Errorf("connecting to jump VM %s: %w", h.Jump.Address, err)
+		}
+		defer jumpClient.Close()
+
+		address := sshAddress(host.Address)
+		conn, err := jumpClient.Dial("tcp", address)
+		if err != nil {
+			return "", fmt.Errorf("connecting to %s through the jump VM: %w", host.Address, err)
+		}
+		clientConn, channels, requests, err := ssh.NewClientConn(conn, address, h.clientConfig(host.User))
+		if err != nil {
+			conn.Close()
+			return "", fmt.Errorf("connecting to %s through the jump VM: %w", host.Address, err)
+		}
+		client := ssh.NewClient(clientConn, channels, requests)
+		defer client.Close()
+		return runSSHCommand(client, command)
+	}
+}
+
+func (h *SSHHarness) clientConfig(user string) *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(h.Signer)},
+		HostKeyCallback: h.HostKeyCallback,
+		Timeout:         h.Timeout,
+	}
+}
+
+func runSSHCommand(client *ssh.Client, command string) (string, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+	output, err := session.CombinedOutput(command)
+	return strings.TrimSpace(string(output)), err
+}
+
+// sshAddress adds the default SSH port to an address without one.
+func sshAddress(address string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return net.JoinHostPort(address, "22")
+}
+
+// retrieveFromSSH returns a retriever for the output of the command, or the error if there is no output.
+func retrieveFromSSH(run SSHRunner, command string) func() string {
+	return func() string {
+		output, err := run(command)
+		if output == "" && err != nil {
+			return err.Error()
+		}
+		return output
+	}
+}
+
+// CloudInitTests returns a test case checking that cloud-init has finished on the VM. cloud-init may still be
+// running right after the apply, so the status is polled.
+func CloudInitTests(run SSHRunner, vm string) map[string]ApplyTestCase {
+	return map[string]ApplyTestCase{
+		vm + "CloudInitStatusTest": {
+			Expected:        "status: done",
+			ActualRetriever: retrieveFromSSH(run, "cloud-init status"),
+			Message:         fmt.Sprintf("cloud-init has not finished on the %s VM", vm),
+			Eventually: &Eventually{
+				Timeout:      20 * time.Minute,
+				PollInterval: 15 * time.Second,
+				Backoff:      1.5,
+				MaxInterval:  time.Minute,
+			},
+		},
+	}
+}
+
+// NFSServerTests returns test cases checking that the NFS VM assembled its RAID array from every planned
+// vm_data_disk and exports / and /export to the aks and misc subnets.
+func NFSServerTests(run SSHRunner, plan *terraform.PlanStruct) map[string]ApplyTestCase {
+	dataDisks := 0
+	for address := range plan.ResourcePlannedValuesMap {
+		if strings.HasPrefix(address, "module.nfs[0].azurerm_managed_disk.vm_data_disk[") {
+			dataDisks++
+		}
+	}
+	volumeGroup := strings.Split(NFSRaidVolume, "/")[0]
+
+	var expectedExports []string
+	for _, subnet := range []string{"aks", "misc"} {
+		resource, ok := plan.ResourcePlannedValuesMap[fmt.Sprintf("module.vnet.azurerm_subnet.subnet[%q]", subnet)]
+		if !ok {
+			continue
+		}
+		prefixes, _ := resource.AttributeValues["address_prefixes"].([]interface{})
+		if len(prefixes) == 0 {
+			continue
+		}
+		for _, path := range []string{"/", "/export"} {
+			export := fmt.Sprintf("%s %v", path, prefixes[0])
+			if !containsString(expectedExports, export) {
+				expectedExports = append(expectedExports, export)
+			}
+		}
+	}
+	sort.Strings(expectedExports)
+
+	return map[string]ApplyTestCase{
+		"nfsRaidVolumeTest": {
+			Expected:        "raid5",
+			ActualRetriever: retrieveFromSSH(run, "sudo lvs --noheadings -o segtype "+NFSRaidVolume),
+			Message:         "NFS RAID volume is not assembled",
+		},
+		"nfsRaidDiskCountTest": {
+			Expected: fmt.Sprintf("%d", dataDisks),
+			ActualRetriever: retrieveFromSSH(run, fmt.Sprintf(
+				"sudo pvs --noheadings -o pv_name --select vg_name=%s | wc -l", volumeGroup)),
+			Message: "NFS RAID volume group does not contain every data disk",
+		},
+		"nfsExportsTest": {
+			Expected: strings.Join(expectedExports, "\n"),
+			ActualRetriever: func() string {
+				output, err := run("sudo exportfs -s")
+				if err != nil {
+					return err.Error()
+				}
+				return strings.Join(parseExports(output), "\n")
+			},
+			Message: "NFS exports are incorrect",
+		},
+	}
+}
+
+// JumpMountTests returns test cases checking that jump_rwx_filestore_path is mounted from the NFS export
+// and is writable.
+func JumpMountTests(run SSHRunner, plan *terraform.PlanStruct, nfsPrivateIP string) map[string]ApplyTestCase {
+	path, _ := planVariable(plan, "jump_rwx_filestore_path").(string)
+	quoted := shellQuote(path)
+	probe := shellQuote(path + "/.terratest-write-probe")
+
+	return map[string]ApplyTestCase{
+		"jumpMountSourceTest": {
+			Expected: nfsPrivateIP + ":/export",
+			// List the path first so the systemd automount is triggered
+			ActualRetriever: retrieveFromSSH(run, fmt.Sprintf("ls %s > /dev/null && findmnt -n -o SOURCE -t nfs,nfs4 %s", quoted, quoted)),
+			Message:         "Jump VM NFS mount source is incorrect",
+		},
+		"jumpMountWritableTest": {
+			Expected:        "writable",
+			ActualRetriever: retrieveFromSSH(run, fmt.Sprintf("touch %s && rm %s && echo writable", probe, probe)),
+			Message:         "Jump VM NFS mount is not writable",
+		},
+	}
+}
+
+// parseExports converts 'exportfs -s' output into sorted "path client" pairs, dropping the export options.
+func parseExports(output string) []string {
+	var exports []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, client := range fields[1:] {
+			if i := strings.Index(client, "("); i >= 0 {
+				client = client[:i]
+			}
+			exports = append(exports, fields[0]+" "+client)
+		}
+	}
+	sort.Strings(exports)
+	return exports
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// testSSHServer is an in-process stand-in for a VM. It accepts the authorized key for the user, answers
+// exec requests from a table of command outputs and forwards direct-tcpip channels like a jump host.
+type testSSHServer struct {
+	listener net.Listener
+	config   *ssh.ServerConfig
+	outputs  map[string]string
+	mutex    sync.Mutex
+	commands []string
+}
+
+func newTestSSHServer(t *testing.T, user string, authorizedKey ssh.PublicKey, outputs map[string]string) *testSSHServer {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	server := &testSSHServer{outputs: outputs}
+	server.config = &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == user && bytes.Equal(key.Marshal(), authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unauthorized key for %s", conn.User())
+		},
+	}
+	server.config.AddHostKey(hostSigner)
+
+	server.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { server.listener.Close() })
+	go server.serve()
+	return server
+}
+
+func (s *testSSHServer) address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *testSSHServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *testSSHServer) handle(conn net.Conn) {
+	_, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		switch newChannel.ChannelType() {
+		case "session":
+			go s.handleSession(newChannel)
+		case "direct-tcpip":
+			go s.handleForward(newChannel)
+		default:
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+		}
+	}
+}
+
+func (s *testSSHServer) handleSession(newChannel ssh.NewChannel) {
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+	for request := range requests {
+		if request.Type != "exec" {
+			request.Reply(false, nil)
+			continue
+		}
+		var payload struct{ Command string }
+		ssh.Unmarshal(request.Payload, &payload)
+		request.Reply(true, nil)
+		s.mutex.Lock()
+		s.commands = append(s.commands, payload.Command)
+		s.mutex.Unlock()
+
+		status := uint32(0)
+		output, ok := s.outputs[payload.Command]
+		if !ok {
+			output, status = "command not found\n", 127
+		}
+		io.WriteString(channel, output)
+		channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+		return
+	}
+}
+
+func (s *testSSHServer) handleForward(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, fmt.Sprintf("%d", payload.Port)))
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		target.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		io.Copy(target, channel)
+		target.Close()
+	}()
+	io.Copy(channel, target)
+	channel.Close()
+}
+
+func testSSHKey(t *testing.T) ([]byte, ssh.PublicKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.NoError(t, err)
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(block), sshPublicKey
+}
+
+func sshTestPlan() *terraform.PlanStruct {
+	return &terraform.PlanStruct{
+		RawPlan: tfjson.Plan{
+			Variables: map[string]*tfjson.PlanVariable{"jump_rwx_filestore_path": {Value: "/viya-share"}},
+		},
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{
+			"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]": {},
+			"module.nfs[0].azurerm_managed_disk.vm_data_disk[1]": {},
+			"module.nfs[0].azurerm_managed_disk.vm_data_disk[2]": {},
+			"module.nfs[0].azurerm_managed_disk.vm_data_disk[3]": {},
+			"module.vnet.azurerm_subnet.subnet[\"aks\"]": {
+				AttributeValues: map[string]interface{}{"address_prefixes": []interface{}{"192.168.0.0/23"}},
+			},
+			"module.vnet.azurerm_subnet.subnet[\"misc\"]": {
+				AttributeValues: map[string]interface{}{"address_prefixes": []interface{}{"192.168.2.0/24"}},
+			},
+		},
+	}
+}
+
+// TestSSHHarness verifies that the NFS and jump VM checks pass against in-process SSH servers, with the
+// NFS server reached through the jump server.
+func TestSSHHarness(t *testing.T) {
+	privateKey, publicKey := testSSHKey(t)
+	jump := newTestSSHServer(t, "jumpuser", publicKey, map[string]string{
+		"cloud-init status": "status: done\n",
+		"ls '/viya-share' > /dev/null && findmnt -n -o SOURCE -t nfs,nfs4 '/viya-share'":                         "10.0.0.4:/export\n",
+		"touch '/viya-share/.terratest-write-probe' && rm '/viya-share/.terratest-write-probe' && echo writable": "writable\n",
+	})
+	nfs := newTestSSHServer(t, "nfsuser", publicKey, map[string]string{
+		"cloud-init status": "status: done\n",
+		"sudo lvs --noheadings -o segtype data-vg01/data-lv01":                "  raid5\n",
+		"sudo pvs --noheadings -o pv_name --select vg_name=data-vg01 | wc -l": "4\n",
+		"sudo exportfs -s": "/export  192.168.0.0/23(rw,async,wdelay,no_root_squash)\n" +
+			"/export  192.168.2.0/24(rw,async,wdelay,no_root_squash)\n" +
+			"/  192.168.0.0/23(ro,fsid=0)\n" +
+			"/  192.168.2.0/24(ro,fsid=0)\n",
+	})
+
+	harness, err := NewSSHHarness(SSHHost{Address: jump.address(), User: "jumpuser"}, privateKey)
+	require.NoError(t, err)
+	jumpRunner := harness.JumpRunner()
+	nfsRunner := harness.PrivateRunner(SSHHost{Address: nfs.address(), User: "nfsuser"})
+	plan := sshTestPlan()
+
+	RunApplyTests(t, CloudInitTests(jumpRunner, "jump"))
+	RunApplyTests(t, CloudInitTests(nfsRunner, "nfs"))
+	RunApplyTests(t, NFSServerTests(nfsRunner, plan))
+	RunApplyTests(t, JumpMountTests(jumpRunner, plan, "10.0.0.4"))
+	jump.mutex.Lock()
+	defer jump.mutex.Unlock()
+	assert.NotContains(t, jump.commands, "sudo exportfs -s", "NFS commands must run on the NFS server")
+}
+
+// TestSSHHarnessFailures verifies that a wrong key and unexpected command output are reported.
+func TestSSHHarnessFailures(t *testing.T) {
+	privateKey, publicKey := testSSHKey(t)
+	_, otherKey := testSSHKey(t)
+	nfs := newTestSSHServer(t, "nfsuser", publicKey, map[string]string{
+		"sudo lvs --noheadings -o segtype data-vg01/data-lv01":                "  linear\n",
+		"sudo pvs --noheadings -o pv_name --select vg_name=data-vg01 | wc -l": "3\n",
+		"sudo exportfs -s": "/export  192.168.0.0/23(rw)\n",
+	})
+	jump := newTestSSHServer(t, "jumpuser", otherKey, map[string]string{})
+
+	harness, err := NewSSHHarness(SSHHost{Address: nfs.address(), User: "nfsuser"}, privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nfsExportsTest", "nfsRaidDiskCountTest", "nfsRaidVolumeTest"},
+		failedTests(NFSServerTests(harness.JumpRunner(), sshTestPlan())))
+
+	harness.Jump = SSHHost{Address: jump.address(), User: "jumpuser"}
+	_, err = harness.JumpRunner()("cloud-init status")
+	assert.ErrorContains(t, err, "unable to authenticate")
+}
@@ -4,6 +4,7 @@
 package helpers
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -22,7 +23,11 @@ type AttrTuple struct {
 	JsonPath      string
 }
 
-// TestCase struct defines the attributes for a test case
+// TestCase struct defines the attributes for a test case. The AttributeJsonPath must resolve, unless
+// ExpectAbsent is set to verify that the attribute is not in the plan or is null, in which case Expected
+// is not used. An absent attribute must still have a parent in the plan, e.g. {$.network_profile[0]} for
+// {$.network_profile[0].network_policy}, and an empty block is checked with the query of its first element,
+// e.g. {$.linux_profile[0]}.
 type TestCase struct {
 	Expected          interface{}
 	Retriever         Retriever
@@ -30,6 +35,7 @@ type TestCase struct {
 	AttributeJsonPath string
 	AssertFunction    assert.ComparisonAssertionFunc
 	Message           string
+	ExpectAbsent      bool
 }
 
 // A Retriever retrieves the value from a *terraform.PlanStruct plan,
@@ -56,22 +62,24 @@ func RetrieveFromRawPlanOutputChanges(plan *terraform.PlanStruct, outputName str
 	return value, nil
 }
 
-// RetrieveFromResourcePlannedValuesMap Retriever that gets the value of a jsonpath query on a given *terraform.PlanStruct
+// RetrieveFromResourcePlannedValuesMap Retriever that gets the value of a jsonpath query on a given *terraform.PlanStruct.
+// A query that does not resolve returns a *JsonPathNotFoundError.
 func RetrieveFromResourcePlannedValuesMap(plan *terraform.PlanStruct, resourceMapName string, jsonPath string) (string, error) {
 	valuesMap, exists := plan.ResourcePlannedValuesMap[resourceMapName]
 	if !exists {
 		return "nil", nil
 	}
-	return GetJsonPathFromStateResource(valuesMap, jsonPath)
+	return GetStrictJsonPathFromStateResource(valuesMap, jsonPath)
 }
 
-// RetrieveFromRawPlanResource Retriever that gets the value from 'Variables' using variablesMapName and jsonPath
+// RetrieveFromRawPlanResource Retriever that gets the value from 'Variables' using variablesMapName and jsonPath.
+// A query that does not resolve returns a *JsonPathNotFoundError, and a variable that is not in the plan an error.
 func RetrieveFromRawPlanResource(plan *terraform.PlanStruct, resourceMapName string, jsonPath string) (string, error) {
 	variables, exists := plan.RawPlan.Variables[resourceMapName]
 	if !exists {
-		return "", nil
+		return "", fmt.Errorf("the plan has no variable %s", resourceMapName)
 	}
-	return GetJsonPathFromPlannedVariablesMap(variables, jsonPath)
+	return GetStrictJsonPathFromPlannedVariablesMap(variables, jsonPath)
 }
 
 // RetrieveFromPlan is used by the apply logic to retrieve a value to compare the deployed resources against
@@ -96,13 +104,24 @@ func RunTest(t *testing.T, tc TestCase, plan *terraform.PlanStruct) {
 		retrieverFn = RetrieveFromResourcePlannedValuesMap
 	}
 	actual, err := retrieverFn(plan, tc.ResourceMapName, tc.AttributeJsonPath)
+	if tc.ExpectAbsent {
+		var notFound *JsonPathNotFoundError
+		if errors.As(err, &notFound) && notFound.ParentResolved() {
+			return
+		}
+		require.NoError(t, err)
+		if actual != "null" {
+			redactingT{t}.Errorf("%s of %s should be absent, got %s. %s", tc.AttributeJsonPath, tc.ResourceMapName, actual, tc.Message)
+		}
+		return
+	}
 	require.NoError(t, err)
 	assertFn := tc.AssertFunction
 	if assertFn == nil {
 		assertFn = assert.Equal
 	}
-	validateFn := AssertComparison(assertFn, tc.Expected)
-	validateFn(t, actual, tc.Message)
+	// Secrets of the plan are redacted in the failure message
+	compare(redactingT{t}, assertFn, tc.Expected, actual, tc.Message)
 }
 
 // RunTests ranges over a set of test cases and runs them
@@ -0,0 +1,196 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// UpgradeFromTagEnvVar names the release tag the upgrade apply starts from. Defaults to the latest tag
+// before HEAD.
+const UpgradeFromTagEnvVar = "UPGRADE_FROM_TAG"
+
+// UpgradeProtectedResources match the addresses of resources that an upgrade must not replace or delete,
+// since that would lose data or take the cluster down.
+var UpgradeProtectedResources = []string{
+	`^azurerm_resource_group\.aks_rg\[0\]$`,
+	`^module\.vnet\.`,
+	`^module\.aks\.azurerm_kubernetes_cluster\.aks$`,
+	`^module\.node_pools\[".*"\]\.azurerm_kubernetes_cluster_node_pool\.`,
+	`^module\.nfs\[0\]\.azurerm_linux_virtual_machine\.vm$`,
+	`^module\.nfs\[0\]\.azurerm_managed_disk\.`,
+	`^module\.flex_postgresql\[".*"\]\.`,
+	`^module\.netapp\[0\]\.`,
+}
+
+// RunUpgradeApply applies the release tag in UPGRADE_FROM_TAG, or the latest tag before HEAD, then plans the
+// current code against the same state, fails if a protected resource would be replaced, applies the upgrade and
+// runs validate against it. The deployment is destroyed with the current code afterwards. It is skipped unless
+// the upgrade suite is enabled in the test profile.
+func RunUpgradeApply(t *testing.T, overrides map[string]interface{}, validate ApplyValidation) {
+	RequireSuite(t, UpgradeSuite)
+	ResolveAzureCredential(t)
+
+	tag := os.Getenv(UpgradeFromTagEnvVar)
+	if tag == "" {
+		tag = previousReleaseTag(t, "../../")
+	}
+
+	// Both configurations are kept in one temporary folder, named like the copies of copyRepository so that
+	// DestroyDouble can remove the release configuration.
+	rootFolder, err := os.MkdirTemp("", repositoryCopyPrefix+"upgrade-")
+	require.NoError(t, err)
+	prefix := "terratest-" + strings.ToLower(random.UniqueId())
+
+	// deferred cleanup routine, destroying with whichever configuration last held the state. The folder is only
+	// removed once the destroy succeeded, DestroyDouble fails the test otherwise and the state is kept.
+	var options *terraform.Options
+	defer func() {
+		if options != nil {
+			DestroyDouble(t, options)
+		}
+		require.NoError(t, os.RemoveAll(rootFolder))
+	}()
+
+	releaseDir := filepath.Join(rootFolder, "release")
+	require.NoError(t, extractGitRef("../../", tag, releaseDir))
+	options = &terraform.Options{
+		TerraformDir: releaseDir,
+		Vars:         getApplyVariables(t, releaseDir, prefix, overrides),
+		PlanFilePath: filepath.Join(rootFolder, "release.tfplan"),
+		NoColor:      true,
+		Logger:       RedactingLogger,
+	}
+	t.Logf("Applying release %s", tag)
+	terraform.InitAndPlan(t, options)
+	terraform.Apply(t, options)
+
+	// Switch to the current code with the state of the release
+	currentDir := copyRepository(t, rootFolder)
+	require.NoError(t, os.Rename(filepath.Join(releaseDir, "terraform.tfstate"), filepath.Join(currentDir, "terraform.tfstate")))
+	options = &terraform.Options{
+		TerraformDir: currentDir,
+		Vars:         getApplyVariables(t, "../../", prefix, overrides),
+		PlanFilePath: filepath.Join(rootFolder, "upgrade.tfplan"),
+		NoColor:      true,
+		Logger:       RedactingLogger,
+	}
+	require.NoError(t, os.RemoveAll(releaseDir))
+
+	t.Logf("Upgrading from release %s to the current code", tag)
+	plan := terraform.InitAndPlanAndShowWithStruct(t, options)
+	RegisterPlanSecrets(plan)
+	if replacements := ForbiddenReplacements(plan, UpgradeProtectedResources); len(replacements) > 0 {
+		t.Fatalf("The upgrade from %s would replace or delete protected resources:\n  %s", tag, strings.Join(replacements, "\n  "))
+	}
+	checkVCPUQuota(t, plan)
+	terraform.Apply(t, options)
+	CheckIdempotency(t, options)
+
+	validate(t, options, plan)
+}
+
+// ForbiddenReplacements returns the planned replacements and deletions of resources whose address matches one
+// of the protected patterns, with the attributes that force each replacement.
+func ForbiddenReplacements(plan *terraform.PlanStruct, protected []string) []string {
+	var replacements []string
+	for address, change := range plan.ResourceChangesMap {
+		if change.Change == nil || !change.Change.Actions.Delete() && !change.Change.Actions.Replace() {
+			continue
+		}
+		for _, pattern := range protected {
+			if !regexp.MustCompile(pattern).MatchString(address) {
+				continue
+			}
+			replacements = append(replacements, describeChange(address, change.Change))
+			break
+		}
+	}
+	sort.Strings(replacements)
+	return replacements
+}
+
+// previousReleaseTag returns the latest tag reachable from HEAD that does not point at HEAD, skipping the
+// test if there is none.
+func previousReleaseTag(t *testing.T, repoFolder string) string {
+	out, err := gitCommand(repoFolder, "tag", "--merged", "HEAD", "--no-contains", "HEAD", "--sort=-v:refname").Output()
+	require.NoError(t, err)
+	tags := strings.Fields(string(out))
+	if len(tags) == 0 {
+		t.Skipf("No release tag before HEAD to upgrade from, fetch the tags or set %s", UpgradeFromTagEnvVar)
+	}
+	return tags[0]
+}
+
+// extractGitRef writes the files of the git ref to destFolder.
+func extractGitRef(repoFolder string, ref string, destFolder string) error {
+	var stderr bytes.Buffer
+	cmd := gitCommand(repoFolder, "archive", "--format=tar", ref)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("archiving %s: %w: %s", ref, err, stderr.String())
+	}
+
+	reader := tar.NewReader(bytes.NewReader(out))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive of %s: %w", ref, err)
+		}
+		path := filepath.Join(destFolder, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(destFolder)+string(os.PathSeparator)) {
+			return fmt.Errorf("archive of %s has an entry outside the destination: %s", ref, header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, data, os.FileMode(header.Mode)&0777); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, path); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// gitCommand returns a git command run in repoFolder. The folder is marked safe since the repository is
+// mounted into the test container with a different owner.
+func gitCommand(repoFolder string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", append([]string{"-c", "safe.directory=*"}, args...)...)
+	cmd.Dir = repoFolder
+	return cmd
+}
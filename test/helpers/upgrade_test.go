@@ -0,0 +1,64 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForbiddenReplacements verifies that only replacements and deletions of protected resources are reported.
+func TestForbiddenReplacements(t *testing.T) {
+	plan := &terraform.PlanStruct{
+		ResourceChangesMap: map[string]*tfjson.ResourceChange{
+			"module.aks.azurerm_kubernetes_cluster.aks": resourceChange(
+				tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}, nil, nil, nil),
+			"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]": resourceChange(
+				tfjson.Actions{tfjson.ActionDelete}, nil, nil, nil),
+			"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": resourceChange(
+				tfjson.Actions{tfjson.ActionUpdate}, nil, nil, nil),
+			"module.jump[0].azurerm_linux_virtual_machine.vm": resourceChange(
+				tfjson.Actions{tfjson.ActionCreate, tfjson.ActionDelete}, nil, nil, nil),
+		},
+	}
+
+	assert.Equal(t, []string{
+		"module.aks.azurerm_kubernetes_cluster.aks (delete, create)",
+		"module.nfs[0].azurerm_managed_disk.vm_data_disk[0] (delete)",
+	}, ForbiddenReplacements(plan, UpgradeProtectedResources))
+}
+
+// TestExtractGitRef verifies that the previous release tag is found and its files are extracted.
+func TestExtractGitRef(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		out, err := gitCommand(repo, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "-q")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "modules", "aks"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "modules", "aks", "main.tf"), []byte("# v1\n"), 0644))
+	git("add", "-A")
+	git("commit", "-q", "-m", "v1")
+	git("tag", "v1.0.0")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "modules", "aks", "main.tf"), []byte("# v2\n"), 0644))
+	git("commit", "-q", "-am", "v2")
+	git("tag", "v2.0.0")
+
+	assert.Equal(t, "v1.0.0", previousReleaseTag(t, repo))
+
+	dest := filepath.Join(t.TempDir(), "release")
+	require.NoError(t, extractGitRef(repo, "v1.0.0", dest))
+	data, err := os.ReadFile(filepath.Join(dest, "modules", "aks", "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "# v1\n", string(data))
+
+	assert.Error(t, extractGitRef(repo, "v9.9.9", dest))
+}
@@ -0,0 +1,334 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package modulelint checks the variable blocks of the root module and of the local modules, and the inputs and
+// outputs of the module calls between them, against the conventions of the repository without running terraform.
+package modulelint
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"test/tfconfig"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// enumPattern matches the descriptions of the variables that list the values they accept.
+var enumPattern = regexp.MustCompile(`(?i)\b(possible|valid|supported|available) (values|options)\b`)
+
+// MissingValidations are the enumerated string variables that have no validation block yet, as the directory of
+// their module relative to the root module and their name. Remove a variable when adding its validation block.
+var MissingValidations = []string{
+	"aks_network_dataplane",
+	"aks_network_policy",
+	"container_registry_sku",
+	"log_analytics_workspace_sku",
+	"os_disk_storage_account_type",
+	"modules/aks_node_pool/community_kubelet_disk_type",
+	"modules/aks_node_pool/community_os_disk_type",
+	"modules/aks_node_pool/os_type",
+	"modules/azure_aks/aks_network_dataplane",
+	"modules/azure_aks/aks_network_plugin",
+	"modules/azure_aks/aks_network_policy",
+	"modules/azure_aks/cluster_egress_type",
+	"modules/azure_aks/cluster_support_tier",
+	"modules/azurerm_netapp/service_level",
+	"modules/azurerm_postgresql_flex/connectivity_method",
+	"modules/azurerm_postgresql_flex/server_version",
+	"modules/azurerm_vm/data_disk_caching",
+	"modules/azurerm_vm/data_disk_storage_account_type",
+	"modules/azurerm_vm/os_disk_caching",
+	"modules/azurerm_vm/os_disk_storage_account_type",
+}
+
+// UnusedOutputs are the outputs of the local modules that no module call uses yet, as the directory of their
+// module relative to the root module and their name. Remove an output when using or deleting it.
+var UnusedOutputs = []string{
+	"modules/azurerm_netapp/netapp_account_id",
+	"modules/azurerm_netapp/netapp_dns_record_id",
+	"modules/azurerm_netapp/netapp_pool_id",
+	"modules/azurerm_netapp/replica_volume_id",
+}
+
+// Lint loads the root module in dir and the local modules it calls, and returns the findings of CheckVariables
+// for each module and of CheckModuleCall for each module call, along with the entries of MissingValidations and
+// UnusedOutputs that no longer apply.
+func Lint(dir string) ([]string, error) {
+	config, err := tfconfig.LoadConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	var findings []string
+	missingValidations := make(map[string]bool)
+	unusedOutputs := make(map[string]bool)
+	modules := []*tfconfig.Module{config.Root}
+	checked := map[string]bool{filepath.Clean(config.Root.Dir): true}
+	for len(modules) > 0 {
+		module := modules[0]
+		modules = modules[1:]
+		prefix, err := filepath.Rel(dir, module.Dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, finding := range CheckVariables(module) {
+			if name := filepath.Join(prefix, finding.Variable); containsString(MissingValidations, name) && finding.MissingValidation {
+				missingValidations[name] = true
+				continue
+			}
+			findings = append(findings, finding.String())
+		}
+
+		for _, call := range module.ModuleCalls {
+			callee, err := config.Module(call)
+			if err != nil {
+				return nil, err
+			}
+			if callee == nil {
+				continue
+			}
+			findings = append(findings, CheckModuleCall(module, call, callee)...)
+			if !checked[filepath.Clean(callee.Dir)] {
+				checked[filepath.Clean(callee.Dir)] = true
+				modules = append(modules, callee)
+			}
+		}
+		for _, finding := range unusedCalleeOutputs(config, module) {
+			calleePrefix, err := filepath.Rel(dir, finding.module.Dir)
+			if err != nil {
+				return nil, err
+			}
+			if name := filepath.Join(calleePrefix, finding.output); containsString(UnusedOutputs, name) {
+				unusedOutputs[name] = true
+				continue
+			}
+			findings = append(findings, finding.String())
+		}
+	}
+
+	for _, name := range MissingValidations {
+		if !missingValidations[name] {
+			findings = append(findings, fmt.Sprintf("%s has a validation block or is not an enumerated string variable, remove it from modulelint.MissingValidations", name))
+		}
+	}
+	for _, name := range UnusedOutputs {
+		if !unusedOutputs[name] {
+			findings = append(findings, fmt.Sprintf("%s is used or no longer exists, remove it from modulelint.UnusedOutputs", name))
+		}
+	}
+	sort.Strings(findings)
+	return findings, nil
+}
+
+// VariableFinding is a variable block that does not follow the conventions.
+type VariableFinding struct {
+	Variable string
+	Range    hcl.Range
+	Message  string
+	// MissingValidation is set for an enumerated string variable without a validation block
+	MissingValidation bool
+}
+
+func (f VariableFinding) String() string {
+	return fmt.Sprintf("%s:%d: %s %s", f.Range.Filename, f.Range.Start.Line, f.Variable, f.Message)
+}
+
+// CheckVariables returns the variables of the module that have no description or type, the string variables
+// whose description lists the values they accept but that have no validation block, and the variables that are
+// not nullable but default to null.
+func CheckVariables(module *tfconfig.Module) []VariableFinding {
+	var findings []VariableFinding
+	for _, name := range variableNames(module.Variables) {
+		variable := module.Variables[name]
+		if variable.Description == "" {
+			findings = append(findings, VariableFinding{Variable: name, Range: variable.Range, Message: "has no description"})
+		}
+		if variable.Type == "" {
+			findings = append(findings, VariableFinding{Variable: name, Range: variable.Range, Message: "has no type"})
+		}
+		if variable.Constraint == cty.String && variable.Validations == 0 && enumPattern.MatchString(variable.Description) {
+			findings = append(findings, VariableFinding{Variable: name, Range: variable.Range,
+				Message: "lists the values it accepts in its description, but has no validation block", MissingValidation: true})
+		}
+		if variable.Nullable != nil && !*variable.Nullable && variable.HasDefault && variable.Default.IsNull() {
+			findings = append(findings, VariableFinding{Variable: name, Range: variable.Range, Message: "is not nullable, but defaults to null"})
+		}
+	}
+	return findings
+}
+
+// CheckModuleCall returns the inputs of a module call that are not variables of the module, whose variable or
+// literal value cannot be converted to the type of the module variable, or that pass a variable defaulting to
+// null to a module variable that is not nullable, and the variables of the module without a default that the
+// call does not set.
+func CheckModuleCall(caller *tfconfig.Module, call *tfconfig.ModuleCall, callee *tfconfig.Module) []string {
+	var findings []string
+	for _, name := range inputNames(call.Inputs) {
+		input := call.Inputs[name]
+		position := fmt.Sprintf("%s:%d: module %s", input.Range.Filename, input.Range.Start.Line, call.Name)
+		variable, ok := callee.Variables[name]
+		if !ok {
+			findings = append(findings, fmt.Sprintf("%s sets %s, which is not a variable of %s", position, name, call.Source))
+			continue
+		}
+
+		if source := callerVariable(caller, input.Expr); source != nil {
+			if !source.Constraint.Equals(variable.Constraint) && convert.GetConversionUnsafe(source.Constraint, variable.Constraint) == nil {
+				findings = append(findings, fmt.Sprintf("%s passes var.%s of type %s to %s of type %s", position, source.Name,
+					typeName(source), name, typeName(variable)))
+			}
+			if variable.Nullable != nil && !*variable.Nullable && (source.Nullable == nil || *source.Nullable) &&
+				source.HasDefault && source.Default.IsNull() {
+				findings = append(findings, fmt.Sprintf("%s passes var.%s, which defaults to null, to %s, which is not nullable",
+					position, source.Name, name))
+			}
+			continue
+		}
+		if value, diags := input.Expr.Value(nil); !diags.HasErrors() && value.IsWhollyKnown() {
+			if _, err := convert.Convert(value, variable.Constraint); err != nil {
+				findings = append(findings, fmt.Sprintf("%s sets %s of type %s to a %s: %s", position, name,
+					typeName(variable), value.Type().FriendlyName(), err))
+			}
+		}
+	}
+
+	for _, name := range variableNames(callee.Variables) {
+		if _, ok := call.Inputs[name]; !ok && !callee.Variables[name].HasDefault {
+			findings = append(findings, fmt.Sprintf("%s:%d: module %s does not set %s, which has no default", call.Range.Filename,
+				call.Range.Start.Line, call.Name, name))
+		}
+	}
+	return findings
+}
+
+type outputFinding struct {
+	module *tfconfig.Module
+	output string
+	call   string
+}
+
+func (f outputFinding) String() string {
+	outputRange := f.module.Outputs[f.output]
+	return fmt.Sprintf("%s:%d: output %s of module %s is not used", outputRange.Filename, outputRange.Start.Line, f.output, f.call)
+}
+
+// unusedCalleeOutputs returns the outputs of the local modules called by the module that its expressions do not
+// reference. A reference to a module as a whole uses all of its outputs.
+func unusedCalleeOutputs(config *tfconfig.Config, module *tfconfig.Module) []outputFinding {
+	used := make(map[string]map[string]bool)
+	whole := make(map[string]bool)
+	for _, traversal := range module.References {
+		if traversal.RootName() != "module" || len(traversal) < 2 {
+			continue
+		}
+		call, ok := traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+		output, ok := outputName(traversal[2:])
+		if !ok {
+			whole[call.Name] = true
+			continue
+		}
+		if used[call.Name] == nil {
+			used[call.Name] = make(map[string]bool)
+		}
+		used[call.Name][output] = true
+	}
+
+	var findings []outputFinding
+	for _, name := range callNames(module.ModuleCalls) {
+		callee, err := config.Module(module.ModuleCalls[name])
+		if err != nil || callee == nil || whole[name] {
+			continue
+		}
+		for _, output := range outputNames(callee.Outputs) {
+			if !used[name][output] {
+				findings = append(findings, outputFinding{module: callee, output: output, call: name})
+			}
+		}
+	}
+	return findings
+}
+
+// outputName returns the output name of the traversal that follows module.<name>, skipping an index.
+func outputName(traversal hcl.Traversal) (string, bool) {
+	if len(traversal) > 0 {
+		if _, ok := traversal[0].(hcl.TraverseIndex); ok {
+			traversal = traversal[1:]
+		}
+	}
+	if len(traversal) == 0 {
+		return "", false
+	}
+	attribute, ok := traversal[0].(hcl.TraverseAttr)
+	return attribute.Name, ok
+}
+
+// callerVariable returns the variable of the caller that an expression references as a whole, e.g. var.prefix.
+func callerVariable(caller *tfconfig.Module, expr hcl.Expression) *tfconfig.Variable {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() || traversal.RootName() != "var" || len(traversal) != 2 {
+		return nil
+	}
+	attribute, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return nil
+	}
+	return caller.Variables[attribute.Name]
+}
+
+func typeName(variable *tfconfig.Variable) string {
+	if variable.Type == "" {
+		return "any"
+	}
+	return variable.Type
+}
+
+func variableNames(variables map[string]*tfconfig.Variable) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func inputNames(inputs hcl.Attributes) []string {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func callNames(calls map[string]*tfconfig.ModuleCall) []string {
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func outputNames(outputs map[string]hcl.Range) []string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package modulelint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = map[string]string{
+	"variables.tf": `variable "prefix" {
+  description = "A prefix used in the name of all resources"
+  type        = string
+}
+
+variable "zones" {
+  description = "The availability zones"
+  type        = list(string)
+  default     = []
+}
+
+variable "subnet_id" {
+  description = "The ID of an existing subnet"
+  type        = string
+  default     = null
+}
+
+variable "sku" {
+  description = "The SKU. Possible values are Basic and Standard."
+  type        = string
+  default     = "Basic"
+}
+
+variable "mode" {
+  description = "The mode. Possible values are public and private."
+  type        = string
+  default     = "public"
+
+  validation {
+    condition     = contains(["public", "private"], var.mode)
+    error_message = "ERROR: Supported values for mode are: public, private."
+  }
+}
+
+variable "size" {
+  type    = number
+  default = 1
+}
+
+variable "name" {
+  description = "The name"
+  nullable    = false
+  default     = null
+}
+`,
+	"main.tf": `module "vm" {
+  source = "./modules/vm"
+  count  = 1
+
+  prefix    = var.prefix
+  zone      = var.zones
+  subnet_id = var.subnet_id
+  disk_size = "large"
+  vm_size   = "Standard_D4s_v5"
+  unknown   = var.sku
+}
+
+module "other" {
+  source = "./modules/vm"
+
+  prefix       = "${var.prefix}-other"
+  machine_type = "Standard_B2s"
+}
+
+output "vm_ip" {
+  value = module.vm[0].private_ip_address
+}
+
+output "other" {
+  value = module.other
+}
+`,
+	"modules/vm/variables.tf": `variable "prefix" {
+  description = "A prefix used in the name of all resources"
+  type        = string
+}
+
+variable "machine_type" {
+  description = "The VM type"
+  type        = string
+}
+
+variable "zone" {
+  description = "The availability zone"
+  type        = string
+  default     = ""
+}
+
+variable "subnet_id" {
+  description = "The ID of the subnet"
+  type        = string
+  nullable    = false
+  default     = ""
+}
+
+variable "disk_size" {
+  description = "The size of the disk in GB"
+  type        = number
+  default     = 64
+}
+
+variable "vm_size" {
+  description = "The VM size"
+  type        = string
+  default     = "Standard_B2s"
+}
+`,
+	"modules/vm/outputs.tf": `output "private_ip_address" {
+  value = "10.0.0.1"
+}
+
+output "admin_username" {
+  value = "azureuser"
+}
+`,
+}
+
+// TestLint verifies that variables without a description, a type or a validation block, inconsistent nullable
+// variables, unknown, incompatible and missing module inputs and unused module outputs are reported.
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range testFiles {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	all, err := Lint(dir)
+	require.NoError(t, err)
+	var findings, stale []string
+	for _, finding := range all {
+		if strings.Contains(finding, "remove it from modulelint.") {
+			stale = append(stale, finding)
+		} else {
+			findings = append(findings, strings.TrimPrefix(finding, dir+string(filepath.Separator)))
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"main.tf:10: module vm sets unknown, which is not a variable of ./modules/vm",
+		"main.tf:1: module vm does not set machine_type, which has no default",
+		"main.tf:6: module vm passes var.zones of type list(string) to zone of type string",
+		"main.tf:7: module vm passes var.subnet_id, which defaults to null, to subnet_id, which is not nullable",
+		`main.tf:8: module vm sets disk_size of type number to a string: a number is required`,
+		"modules/vm/outputs.tf:5: output admin_username of module vm is not used",
+		"variables.tf:18: sku lists the values it accepts in its description, but has no validation block",
+		"variables.tf:35: size has no description",
+		"variables.tf:40: name has no type",
+		"variables.tf:40: name is not nullable, but defaults to null",
+	}, findings)
+	// The baselines of the repository do not apply to the test module
+	assert.Len(t, stale, len(MissingValidations)+len(UnusedOutputs))
+}
+
+// Verify that the variables and module interfaces of the root module and the local modules follow the
+// conventions, without running terraform.
+func TestPlanModuleInterfaces(t *testing.T) {
+	findings, err := Lint("../..")
+	require.NoError(t, err)
+	for _, finding := range findings {
+		assert.Fail(t, "The terraform sources do not follow the module conventions", finding)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nodePoolPlan returns a plan of the aks_node_pool module with its required inputs. The linux_os_config is null,
+// as the root module passes it for a node pool without one.
+func nodePoolPlan() helpers.ModulePlan {
+	return helpers.ModulePlan{
+		Module: "aks_node_pool",
+		Inputs: map[string]interface{}{
+			"node_pool_name":       "compute",
+			"aks_cluster_id":       clusterID,
+			"machine_type":         "Standard_D4ds_v5",
+			"orchestrator_version": "1.35",
+			"vnet_subnet_id":       subnetID("aks"),
+			"tags":                 map[string]string{"project_name": "viya"},
+			"linux_os_config":      nil,
+		},
+	}
+}
+
+// Test that a node pool without auto scaling is a static node pool with the module defaults.
+func TestPlanAksNodePoolStatic(t *testing.T) {
+	t.Parallel()
+
+	plan := nodePoolPlan()
+	tests := map[string]helpers.TestCase{
+		"staticNodePoolExists": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"),
+			AttributeJsonPath: "{$}",
+			AssertFunction:    assert.NotEqual,
+		},
+		"autoscaleNodePoolAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$}",
+		},
+		"nodeCount": {
+			Expected:          `1`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"),
+			AttributeJsonPath: "{$.node_count}",
+		},
+		"temporaryNameForRotation": {
+			Expected:          `tcompute`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"),
+			AttributeJsonPath: "{$.temporary_name_for_rotation}",
+		},
+		"proximityPlacementGroupId": {
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"),
+			AttributeJsonPath: "{$.proximity_placement_group_id}",
+			ExpectAbsent:      true,
+			Message:           "An empty proximity_placement_group_id should not be set",
+		},
+		"priority": {
+			Expected:          `Regular`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"),
+			AttributeJsonPath: "{$.priority}",
+		},
+		"linuxOsConfig": {
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"),
+			AttributeJsonPath: "{$.linux_os_config[0]}",
+			ExpectAbsent:      true,
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test an auto scaling spot node pool with a long name and a linux_os_config.
+func TestPlanAksNodePoolAutoscaleSpot(t *testing.T) {
+	t.Parallel()
+
+	plan := nodePoolPlan()
+	plan.Inputs["node_pool_name"] = "stateless01"
+	plan.Inputs["auto_scaling_enabled"] = true
+	plan.Inputs["min_nodes"] = 0
+	plan.Inputs["max_nodes"] = 5
+	plan.Inputs["community_priority"] = "Spot"
+	plan.Inputs["community_eviction_policy"] = "Delete"
+	plan.Inputs["community_spot_max_price"] = "-1"
+	plan.Inputs["proximity_placement_group_id"] = resourceGroupID + "/providers/Microsoft.Compute/proximityPlacementGroups/test-ppg"
+	plan.Inputs["linux_os_config"] = map[string]interface{}{"sysctl_config": map[string]interface{}{"vm_max_map_count": 262144}}
+
+	tests := map[string]helpers.TestCase{
+		"autoscaleNodePoolExists": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$}",
+			AssertFunction:    assert.NotEqual,
+		},
+		"staticNodePoolAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"),
+			AttributeJsonPath: "{$}",
+		},
+		"minCount": {
+			Expected:          `0`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.min_count}",
+		},
+		"maxCount": {
+			Expected:          `5`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.max_count}",
+		},
+		"autoscaleTemporaryNameForRotation": {
+			Expected:          `tstateless01`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.temporary_name_for_rotation}",
+			Message:           "The temporary name for rotation should be truncated to 12 characters",
+		},
+		"spotPriority": {
+			Expected:          `Spot`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.priority}",
+		},
+		"evictionPolicy": {
+			Expected:          `Delete`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.eviction_policy}",
+		},
+		"spotMaxPrice": {
+			Expected:          `-1`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.spot_max_price}",
+		},
+		"autoscaleProximityPlacementGroupId": {
+			Expected:          plan.Inputs["proximity_placement_group_id"],
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.proximity_placement_group_id}",
+		},
+		"vmMaxMapCount": {
+			Expected:          `262144`,
+			ResourceMapName:   plan.Address("azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]"),
+			AttributeJsonPath: "{$.linux_os_config[0].sysctl_config[0].vm_max_map_count}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test that the eviction policy and the maximum price are rejected for a node pool that is not a spot node pool.
+func TestPlanAksNodePoolSpotValidation(t *testing.T) {
+	t.Parallel()
+
+	plan := nodePoolPlan()
+	plan.Inputs["community_eviction_policy"] = "Deallocate"
+	_, err := helpers.InitModulePlan(t, plan)
+	helpers.AssertPlanError(t, err, "community_eviction_policy can only be specified when community_priority is set to 'Spot'")
+
+	plan = nodePoolPlan()
+	plan.Inputs["community_spot_max_price"] = "0.5"
+	_, err = helpers.InitModulePlan(t, plan)
+	helpers.AssertPlanError(t, err, "community_spot_max_price can only be specified when community_priority is set to 'Spot'")
+}
@@ -0,0 +1,227 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/ssh"
+)
+
+// aksPlan returns a plan of the azure_aks module with its required inputs and a user assigned identity.
+func aksPlan() helpers.ModulePlan {
+	return helpers.ModulePlan{
+		Module: "azure_aks",
+		Inputs: map[string]interface{}{
+			"aks_cluster_name":                         "test-aks",
+			"aks_cluster_rg":                           "test-rg",
+			"aks_cluster_dns_prefix":                   "test-aks",
+			"aks_cluster_endpoint_public_access_cidrs": []string{},
+			"aks_cluster_tags":                         map[string]string{"project_name": "viya"},
+			"aks_oms_enabled":                          false,
+			"aks_log_analytics_workspace_id":           "",
+			"aks_uai_id":                               uaiID,
+			"aks_vnet_subnet_id":                       subnetID("aks"),
+			"node_resource_group_name":                 "MC_test-rg_test-aks_eastus",
+		},
+	}
+}
+
+// Test the cluster of a public cluster with the module defaults.
+func TestPlanAzureAksDefaults(t *testing.T) {
+	t.Parallel()
+
+	plan := aksPlan()
+	resource := plan.Address("azurerm_kubernetes_cluster.aks")
+	tests := map[string]helpers.TestCase{
+		"dnsPrefix": {
+			Expected:          `test-aks`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.dns_prefix}",
+		},
+		"privateClusterEnabled": {
+			Expected:          `false`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.private_cluster_enabled}",
+		},
+		"privateDnsZoneId": {
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.private_dns_zone_id}",
+			ExpectAbsent:      true,
+		},
+		"apiServerAccessProfile": {
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.api_server_access_profile[0]}",
+			ExpectAbsent:      true,
+			Message:           "No api_server_access_profile should be set without public access CIDRs",
+		},
+		"linuxProfile": {
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.linux_profile[0]}",
+			ExpectAbsent:      true,
+			Message:           "No linux_profile should be set without an SSH public key",
+		},
+		"identityType": {
+			Expected:          `UserAssigned`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.identity[0].type}",
+		},
+		"identityIds": {
+			Expected:          `["` + uaiID + `"]`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.identity[0].identity_ids}",
+		},
+		"servicePrincipal": {
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.service_principal[0]}",
+			ExpectAbsent:      true,
+		},
+		"omsAgent": {
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.oms_agent[0]}",
+			ExpectAbsent:      true,
+		},
+		"defaultNodePoolName": {
+			Expected:          `system`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.default_node_pool[0].name}",
+		},
+		"defaultNodePoolOrchestratorVersion": {
+			Expected:          `1.35`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.default_node_pool[0].orchestrator_version}",
+		},
+		"networkPluginMode": {
+			Expected:          `overlay`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.network_profile[0].network_plugin_mode}",
+		},
+		"podCidr": {
+			Expected:          `10.244.0.0/16`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.network_profile[0].pod_cidr}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test a private cluster with an SSH public key, authorized IP ranges and the OMS agent.
+func TestPlanAzureAksPrivate(t *testing.T) {
+	t.Parallel()
+
+	plan := aksPlan()
+	plan.Inputs["aks_private_cluster"] = true
+	plan.Inputs["aks_cluster_private_dns_zone_id"] = dnsZoneID
+	plan.Inputs["aks_cluster_endpoint_public_access_cidrs"] = []string{"203.0.113.0/24"}
+	plan.Inputs["aks_cluster_ssh_public_key"] = ssh.GenerateRSAKeyPair(t, 2048).PublicKey
+	plan.Inputs["aks_oms_enabled"] = true
+	plan.Inputs["aks_log_analytics_workspace_id"] = resourceGroupID + "/providers/Microsoft.OperationalInsights/workspaces/test-log"
+	resource := plan.Address("azurerm_kubernetes_cluster.aks")
+
+	tests := map[string]helpers.TestCase{
+		"dnsPrefix": {
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.dns_prefix}",
+			ExpectAbsent:      true,
+			Message:           "A private cluster with a private DNS zone should only set dns_prefix_private_cluster",
+		},
+		"dnsPrefixPrivateCluster": {
+			Expected:          `test-aks`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.dns_prefix_private_cluster}",
+		},
+		"privateDnsZoneId": {
+			Expected:          dnsZoneID,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.private_dns_zone_id}",
+		},
+		"authorizedIpRanges": {
+			Expected:          `["203.0.113.0/24"]`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.api_server_access_profile[0].authorized_ip_ranges}",
+		},
+		"adminUsername": {
+			Expected:          `ubuntu`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.linux_profile[0].admin_username}",
+		},
+		"omsAgentWorkspaceId": {
+			Expected:          plan.Inputs["aks_log_analytics_workspace_id"],
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.oms_agent[0].log_analytics_workspace_id}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test that a private cluster without a private DNS zone uses the zone managed by AKS.
+func TestPlanAzureAksPrivateSystemDnsZone(t *testing.T) {
+	t.Parallel()
+
+	plan := aksPlan()
+	plan.Inputs["aks_private_cluster"] = true
+	resource := plan.Address("azurerm_kubernetes_cluster.aks")
+
+	tests := map[string]helpers.TestCase{
+		"dnsPrefix": {
+			Expected:          `test-aks`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.dns_prefix}",
+		},
+		"privateDnsZoneId": {
+			Expected:          `System`,
+			ResourceMapName:   resource,
+			AttributeJsonPath: "{$.private_dns_zone_id}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test that the pod CIDR is only set for kubenet or the overlay mode of the azure network plugin.
+func TestPlanAzureAksPodCidr(t *testing.T) {
+	t.Parallel()
+
+	kubenet := aksPlan()
+	kubenet.Inputs["aks_network_plugin"] = "kubenet"
+	kubenet.Inputs["aks_network_plugin_mode"] = nil
+	helpers.RunModulePlanTests(t, kubenet, map[string]helpers.TestCase{
+		"kubenetPodCidr": {
+			Expected:          `10.244.0.0/16`,
+			ResourceMapName:   kubenet.Address("azurerm_kubernetes_cluster.aks"),
+			AttributeJsonPath: "{$.network_profile[0].pod_cidr}",
+		},
+	})
+
+	azure := aksPlan()
+	azure.Inputs["aks_network_plugin_mode"] = nil
+	helpers.RunModulePlanTests(t, azure, map[string]helpers.TestCase{
+		"azurePodCidr": {
+			ResourceMapName:   azure.Address("azurerm_kubernetes_cluster.aks"),
+			AttributeJsonPath: "{$.network_profile[0].pod_cidr}",
+			ExpectAbsent:      true,
+			Message:           "The azure network plugin without the overlay mode assigns pod IPs from the subnet",
+		},
+	})
+}
+
+// Test that the preconditions reject network policies and plugin modes that need the azure network plugin.
+func TestPlanAzureAksNetworkPreconditions(t *testing.T) {
+	t.Parallel()
+
+	plan := aksPlan()
+	plan.Inputs["aks_network_plugin"] = "kubenet"
+	_, err := helpers.InitModulePlan(t, plan)
+	helpers.AssertPlanError(t, err, "When network_plugin_mode is set to `overlay`, the aks_network_plugin field can only be set to `azure`.")
+
+	plan = aksPlan()
+	plan.Inputs["aks_network_plugin"] = "kubenet"
+	plan.Inputs["aks_network_plugin_mode"] = nil
+	plan.Inputs["aks_network_policy"] = "azure"
+	_, err = helpers.InitModulePlan(t, plan)
+	helpers.AssertPlanError(t, err, "When aks_network_policy is set to `azure`, the aks_network_plugin field can only be set to `azure`.")
+}
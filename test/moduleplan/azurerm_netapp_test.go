@@ -0,0 +1,184 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// netAppPlan returns a plan of the azurerm_netapp module with its required inputs.
+func netAppPlan() helpers.ModulePlan {
+	return helpers.ModulePlan{
+		Module: "azurerm_netapp",
+		Inputs: map[string]interface{}{
+			"prefix":              "test",
+			"resource_group_name": "test-rg",
+			"location":            "eastus",
+			"subnet_id":           subnetID("netapp"),
+			"vnet_id":             vnetID,
+			"service_level":       "Premium",
+			"size_in_tb":          4,
+			"volume_path":         "test-export",
+			"tags":                map[string]string{"project_name": "viya"},
+		},
+	}
+}
+
+// Test the volume of the module defaults, without cross-zone replication.
+func TestPlanAzurermNetAppDefaults(t *testing.T) {
+	t.Parallel()
+
+	plan := netAppPlan()
+	tests := map[string]helpers.TestCase{
+		"accountName": {
+			Expected:          `test-netappaccount`,
+			ResourceMapName:   plan.Address("azurerm_netapp_account.anf"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"volumePoolName": {
+			Expected:          `test-netapppool`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf"),
+			AttributeJsonPath: "{$.pool_name}",
+		},
+		"volumeStorageQuota": {
+			Expected:          `4096`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf"),
+			AttributeJsonPath: "{$.storage_quota_in_gb}",
+			Message:           "The volume should use the whole pool when community_netapp_volume_size is 0",
+		},
+		"volumeZone": {
+			Expected:          `1`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf"),
+			AttributeJsonPath: "{$.zone}",
+		},
+		"volumeRoleTag": {
+			Expected:          `primary`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf"),
+			AttributeJsonPath: "{$.tags.role}",
+		},
+		"exportPolicyAllowedClients": {
+			Expected:          `["0.0.0.0/0"]`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf"),
+			AttributeJsonPath: "{$.export_policy_rule[0].allowed_clients}",
+		},
+		"replicaPoolAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_netapp_pool.anf_replica[0]"),
+			AttributeJsonPath: "{$}",
+		},
+		"replicaVolumeAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf_replica[0]"),
+			AttributeJsonPath: "{$}",
+		},
+		"dnsZoneAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_private_dns_zone.anf_dns[0]"),
+			AttributeJsonPath: "{$}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test that community_netapp_volume_size overrides the size of the volume.
+func TestPlanAzurermNetAppVolumeSize(t *testing.T) {
+	t.Parallel()
+
+	plan := netAppPlan()
+	plan.Inputs["community_netapp_volume_size"] = 500
+	tests := map[string]helpers.TestCase{
+		"volumeStorageQuota": {
+			Expected:          `500`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf"),
+			AttributeJsonPath: "{$.storage_quota_in_gb}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test the replica pool and volume and the private DNS zone of cross-zone replication.
+func TestPlanAzurermNetAppCrossZoneReplication(t *testing.T) {
+	t.Parallel()
+
+	plan := netAppPlan()
+	plan.Inputs["network_features"] = "Standard"
+	plan.Inputs["netapp_enable_cross_zone_replication"] = true
+	plan.Inputs["netapp_replication_zone"] = "3"
+	plan.Inputs["netapp_replication_frequency"] = "hourly"
+
+	tests := map[string]helpers.TestCase{
+		"replicaPoolRoleTag": {
+			Expected:          `replica`,
+			ResourceMapName:   plan.Address("azurerm_netapp_pool.anf_replica[0]"),
+			AttributeJsonPath: "{$.tags.role}",
+		},
+		"replicaVolumePoolName": {
+			Expected:          `test-netapppool-replica`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf_replica[0]"),
+			AttributeJsonPath: "{$.pool_name}",
+		},
+		"replicaVolumePath": {
+			Expected:          `test-export`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf_replica[0]"),
+			AttributeJsonPath: "{$.volume_path}",
+			Message:           "The replica should export the same path as the primary volume",
+		},
+		"replicaVolumeZone": {
+			Expected:          `3`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf_replica[0]"),
+			AttributeJsonPath: "{$.zone}",
+		},
+		"replicationEndpointType": {
+			Expected:          `dst`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf_replica[0]"),
+			AttributeJsonPath: "{$.data_protection_replication[0].endpoint_type}",
+		},
+		"replicationFrequency": {
+			Expected:          `hourly`,
+			ResourceMapName:   plan.Address("azurerm_netapp_volume.anf_replica[0]"),
+			AttributeJsonPath: "{$.data_protection_replication[0].replication_frequency}",
+		},
+		"dnsZoneName": {
+			Expected:          `sas-viya.internal`,
+			ResourceMapName:   plan.Address("azurerm_private_dns_zone.anf_dns[0]"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"dnsZoneLinkVnet": {
+			Expected:          vnetID,
+			ResourceMapName:   plan.Address("azurerm_private_dns_zone_virtual_network_link.anf_dns_link[0]"),
+			AttributeJsonPath: "{$.virtual_network_id}",
+		},
+		"dnsRecordExists": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_private_dns_a_record.anf_primary[0]"),
+			AttributeJsonPath: "{$}",
+			AssertFunction:    assert.NotEqual,
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test that cross-zone replication is rejected without Standard network features or with the zone of the
+// primary volume.
+func TestPlanAzurermNetAppCrossZoneReplicationValidation(t *testing.T) {
+	t.Parallel()
+
+	plan := netAppPlan()
+	plan.Inputs["netapp_enable_cross_zone_replication"] = true
+	_, err := helpers.InitModulePlan(t, plan)
+	helpers.AssertPlanError(t, err, "network_features must be set to 'Standard'")
+
+	plan = netAppPlan()
+	plan.Inputs["network_features"] = "Standard"
+	plan.Inputs["netapp_enable_cross_zone_replication"] = true
+	plan.Inputs["netapp_replication_zone"] = "1"
+	_, err = helpers.InitModulePlan(t, plan)
+	helpers.AssertPlanError(t, err, "netapp_replication_zone must be set and differ from netapp_availability_zone")
+}
@@ -0,0 +1,149 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// postgresPlan returns a plan of the azurerm_postgresql_flex module with public connectivity. The network inputs
+// are null, as the root module passes them for a public server.
+func postgresPlan() helpers.ModulePlan {
+	return helpers.ModulePlan{
+		Module: "azurerm_postgresql_flex",
+		Inputs: map[string]interface{}{
+			"resource_group_name":    "test-rg",
+			"location":               "eastus",
+			"server_name":            "test-default",
+			"administrator_login":    "pgadmin",
+			"administrator_password": "my$up3rS3cretPassw0rd",
+			"virtual_network_id":     nil,
+			"delegated_subnet_id":    nil,
+			"firewall_rules": []map[string]string{
+				{"name": "office", "start_ip": "203.0.113.0", "end_ip": "203.0.113.255"},
+			},
+		},
+	}
+}
+
+// Test the firewall rules and public network access of a public server.
+func TestPlanAzurermPostgresqlFlexPublic(t *testing.T) {
+	t.Parallel()
+
+	plan := postgresPlan()
+	server := plan.Address("azurerm_postgresql_flexible_server.flexpsql")
+	tests := map[string]helpers.TestCase{
+		"serverName": {
+			Expected:          `test-default-flexpsql`,
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.name}",
+		},
+		"skuName": {
+			Expected:          `GP_Standard_D4s_v5`,
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.sku_name}",
+		},
+		"publicNetworkAccessEnabled": {
+			Expected:          `true`,
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.public_network_access_enabled}",
+		},
+		"highAvailability": {
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.high_availability[0]}",
+			ExpectAbsent:      true,
+		},
+		"firewallRuleName": {
+			Expected:          `firewall-office`,
+			ResourceMapName:   plan.Address("azurerm_postgresql_flexible_server_firewall_rule.flexpsql[0]"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"firewallRuleEndIp": {
+			Expected:          `203.0.113.255`,
+			ResourceMapName:   plan.Address("azurerm_postgresql_flexible_server_firewall_rule.flexpsql[0]"),
+			AttributeJsonPath: "{$.end_ip_address}",
+		},
+		"azurePublicFirewallRuleExists": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_postgresql_flexible_server_firewall_rule.azure_public[0]"),
+			AttributeJsonPath: "{$}",
+			AssertFunction:    assert.NotEqual,
+		},
+		"privateDnsZoneAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_private_dns_zone.flexpsql[0]"),
+			AttributeJsonPath: "{$}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test the private DNS zone of a private server with zone redundant high availability.
+func TestPlanAzurermPostgresqlFlexPrivateHighAvailability(t *testing.T) {
+	t.Parallel()
+
+	plan := postgresPlan()
+	plan.Inputs["connectivity_method"] = "private"
+	plan.Inputs["virtual_network_id"] = vnetID
+	plan.Inputs["delegated_subnet_id"] = subnetID("postgresql")
+	plan.Inputs["high_availability_mode"] = "ZoneRedundant"
+	plan.Inputs["standby_availability_zone"] = "3"
+	server := plan.Address("azurerm_postgresql_flexible_server.flexpsql")
+
+	tests := map[string]helpers.TestCase{
+		"publicNetworkAccessEnabled": {
+			Expected:          `false`,
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.public_network_access_enabled}",
+		},
+		"delegatedSubnetId": {
+			Expected:          subnetID("postgresql"),
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.delegated_subnet_id}",
+		},
+		"privateDnsZoneName": {
+			Expected:          `test-default.postgres.database.azure.com`,
+			ResourceMapName:   plan.Address("azurerm_private_dns_zone.flexpsql[0]"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"privateDnsZoneLinkVnet": {
+			Expected:          vnetID,
+			ResourceMapName:   plan.Address("azurerm_private_dns_zone_virtual_network_link.flexpsql[0]"),
+			AttributeJsonPath: "{$.virtual_network_id}",
+		},
+		"firewallRuleAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_postgresql_flexible_server_firewall_rule.flexpsql[0]"),
+			AttributeJsonPath: "{$}",
+			Message:           "The firewall rules should only be created for a public server",
+		},
+		"highAvailabilityMode": {
+			Expected:          `ZoneRedundant`,
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.high_availability[0].mode}",
+		},
+		"standbyAvailabilityZone": {
+			Expected:          `3`,
+			ResourceMapName:   server,
+			AttributeJsonPath: "{$.high_availability[0].standby_availability_zone}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test that zone redundant high availability is rejected with the standby server in the zone of the primary.
+func TestPlanAzurermPostgresqlFlexHighAvailabilityValidation(t *testing.T) {
+	t.Parallel()
+
+	plan := postgresPlan()
+	plan.Inputs["high_availability_mode"] = "ZoneRedundant"
+	plan.Inputs["standby_availability_zone"] = "1"
+	_, err := helpers.InitModulePlan(t, plan)
+	helpers.AssertPlanError(t, err, "standby_availability_zone must be set and differ from availability_zone")
+}
@@ -0,0 +1,189 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/ssh"
+	"github.com/stretchr/testify/assert"
+)
+
+// vmPlan returns a plan of the azurerm_vm module with its required inputs and an SSH public key.
+func vmPlan(t *testing.T, name string) helpers.ModulePlan {
+	return helpers.ModulePlan{
+		Module: "azurerm_vm",
+		Inputs: map[string]interface{}{
+			"name":              name,
+			"azure_rg_name":     "test-rg",
+			"azure_rg_location": "eastus",
+			"azure_nsg_id":      nsgID,
+			"vnet_subnet_id":    subnetID("misc"),
+			"ssh_public_key":    ssh.GenerateRSAKeyPair(t, 2048).PublicKey,
+			"tags":              map[string]string{"project_name": "viya"},
+		},
+	}
+}
+
+// Test a VM with the module defaults, which has no data disks and no public IP.
+func TestPlanAzurermVmDefaults(t *testing.T) {
+	t.Parallel()
+
+	plan := vmPlan(t, "test-jump")
+	tests := map[string]helpers.TestCase{
+		"vmName": {
+			Expected:          `test-jump-vm`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"vmAdminUsername": {
+			Expected:          `azureuser`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.admin_username}",
+		},
+		"size": {
+			Expected:          `Standard_E8s_v5`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.size}",
+		},
+		"imageOffer": {
+			Expected:          `0001-com-ubuntu-server-jammy`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.source_image_reference[0].offer}",
+		},
+		"imagePlan": {
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.plan[0]}",
+			ExpectAbsent:      true,
+		},
+		"ultraSsdEnabled": {
+			Expected:          `false`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.additional_capabilities[0].ultra_ssd_enabled}",
+		},
+		"acceleratedNetworkingEnabled": {
+			Expected:          `false`,
+			ResourceMapName:   plan.Address("azurerm_network_interface.vm_nic"),
+			AttributeJsonPath: "{$.accelerated_networking_enabled}",
+		},
+		"nicSecurityGroupAssociationExists": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_network_interface_security_group_association.vm_nic_sg"),
+			AttributeJsonPath: "{$}",
+			AssertFunction:    assert.NotEqual,
+		},
+		"publicIpAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_public_ip.vm_ip[0]"),
+			AttributeJsonPath: "{$}",
+		},
+		"dataDiskAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_managed_disk.vm_data_disk[0]"),
+			AttributeJsonPath: "{$}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test the UltraSSD data disks of an NFS server VM, whose name enables accelerated networking.
+func TestPlanAzurermVmNfsDataDisks(t *testing.T) {
+	t.Parallel()
+
+	plan := vmPlan(t, "test-nfs")
+	plan.Inputs["data_disk_count"] = 2
+	plan.Inputs["data_disk_size"] = 128
+	plan.Inputs["data_disk_storage_account_type"] = "UltraSSD_LRS"
+	plan.Inputs["data_disk_zone"] = "1"
+	plan.Inputs["vm_zone"] = "1"
+
+	tests := map[string]helpers.TestCase{
+		"acceleratedNetworkingEnabled": {
+			Expected:          `true`,
+			ResourceMapName:   plan.Address("azurerm_network_interface.vm_nic"),
+			AttributeJsonPath: "{$.accelerated_networking_enabled}",
+			Message:           "Accelerated networking should be enabled for a VM whose name contains -nfs",
+		},
+		"firstDataDiskName": {
+			Expected:          `test-nfs-disk01`,
+			ResourceMapName:   plan.Address("azurerm_managed_disk.vm_data_disk[0]"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"secondDataDiskName": {
+			Expected:          `test-nfs-disk02`,
+			ResourceMapName:   plan.Address("azurerm_managed_disk.vm_data_disk[1]"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"dataDiskSize": {
+			Expected:          `128`,
+			ResourceMapName:   plan.Address("azurerm_managed_disk.vm_data_disk[1]"),
+			AttributeJsonPath: "{$.disk_size_gb}",
+		},
+		"secondDataDiskLun": {
+			Expected:          `11`,
+			ResourceMapName:   plan.Address("azurerm_virtual_machine_data_disk_attachment.vm_data_disk_attach[1]"),
+			AttributeJsonPath: "{$.lun}",
+		},
+		"dataDiskCaching": {
+			Expected:          `None`,
+			ResourceMapName:   plan.Address("azurerm_virtual_machine_data_disk_attachment.vm_data_disk_attach[0]"),
+			AttributeJsonPath: "{$.caching}",
+			Message:           "UltraSSD_LRS data disks do not support caching",
+		},
+		"ultraSsdEnabled": {
+			Expected:          `true`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.additional_capabilities[0].ultra_ssd_enabled}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test a FIPS VM with a dynamic public IP.
+func TestPlanAzurermVmFipsPublicIp(t *testing.T) {
+	t.Parallel()
+
+	plan := vmPlan(t, "test-jump")
+	plan.Inputs["fips_enabled"] = true
+	plan.Inputs["create_public_ip"] = true
+	plan.Inputs["enable_public_static_ip"] = false
+
+	tests := map[string]helpers.TestCase{
+		"imageOffer": {
+			Expected:          `0001-com-ubuntu-pro-jammy-fips`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.source_image_reference[0].offer}",
+		},
+		"imageSku": {
+			Expected:          `pro-fips-22_04`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.source_image_reference[0].sku}",
+		},
+		"imagePlanProduct": {
+			Expected:          `0001-com-ubuntu-pro-jammy-fips`,
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.plan[0].product}",
+		},
+		"publicIpName": {
+			Expected:          `test-jump-public_ip`,
+			ResourceMapName:   plan.Address("azurerm_public_ip.vm_ip[0]"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"publicIpAllocationMethod": {
+			Expected:          `Dynamic`,
+			ResourceMapName:   plan.Address("azurerm_public_ip.vm_ip[0]"),
+			AttributeJsonPath: "{$.allocation_method}",
+		},
+		"publicIpZones": {
+			Expected:          `[]`,
+			ResourceMapName:   plan.Address("azurerm_public_ip.vm_ip[0]"),
+			AttributeJsonPath: "{$.zones}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
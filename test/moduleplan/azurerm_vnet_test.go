@@ -0,0 +1,97 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+import (
+	"test/helpers"
+	"testing"
+)
+
+// subnet returns a subnet input of the azurerm_vnet module without service endpoints.
+func subnet(prefix string, delegations map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"prefixes":                                      []string{prefix},
+		"service_endpoints":                             []string{},
+		"private_endpoint_network_policies":             "Enabled",
+		"private_link_service_network_policies_enabled": false,
+		"service_delegations":                           delegations,
+	}
+}
+
+// Test the virtual network and subnets that the module creates when no existing network is given.
+func TestPlanAzurermVnetCreated(t *testing.T) {
+	t.Parallel()
+
+	plan := helpers.ModulePlan{
+		Module: "azurerm_vnet",
+		Inputs: map[string]interface{}{
+			"prefix":               "test",
+			"resource_group_name":  "test-rg",
+			"location":             "eastus",
+			"address_space":        []string{"192.168.0.0/16"},
+			"aks_uai_principal_id": "00000000-0000-0000-0000-000000000001",
+			"tags":                 map[string]string{"project_name": "viya"},
+			"subnets": map[string]interface{}{
+				"aks": subnet("192.168.0.0/23", map[string]interface{}{}),
+				"netapp": subnet("192.168.3.0/24", map[string]interface{}{
+					"netapp": map[string]interface{}{
+						"name":    "Microsoft.Netapp/volumes",
+						"actions": []string{"Microsoft.Network/networkinterfaces/*", "Microsoft.Network/virtualNetworks/subnets/join/action"},
+					},
+				}),
+			},
+		},
+	}
+
+	tests := map[string]helpers.TestCase{
+		"vnetName": {
+			Expected:          `test-vnet`,
+			ResourceMapName:   plan.Address("azurerm_virtual_network.vnet[0]"),
+			AttributeJsonPath: "{$.name}",
+		},
+		"vnetAddressSpace": {
+			Expected:          `["192.168.0.0/16"]`,
+			ResourceMapName:   plan.Address("azurerm_virtual_network.vnet[0]"),
+			AttributeJsonPath: "{$.address_space}",
+		},
+		"aksSubnetName": {
+			Expected:          `test-aks-subnet`,
+			ResourceMapName:   plan.Address(`azurerm_subnet.subnet["aks"]`),
+			AttributeJsonPath: "{$.name}",
+		},
+		"aksSubnetVnetName": {
+			Expected:          `test-vnet`,
+			ResourceMapName:   plan.Address(`azurerm_subnet.subnet["aks"]`),
+			AttributeJsonPath: "{$.virtual_network_name}",
+		},
+		"aksSubnetAddressPrefixes": {
+			Expected:          `["192.168.0.0/23"]`,
+			ResourceMapName:   plan.Address(`azurerm_subnet.subnet["aks"]`),
+			AttributeJsonPath: "{$.address_prefixes}",
+		},
+		"aksSubnetDelegation": {
+			ResourceMapName:   plan.Address(`azurerm_subnet.subnet["aks"]`),
+			AttributeJsonPath: "{$.delegation[0]}",
+			ExpectAbsent:      true,
+		},
+		"netappSubnetDelegation": {
+			Expected:          `netapp`,
+			ResourceMapName:   plan.Address(`azurerm_subnet.subnet["netapp"]`),
+			AttributeJsonPath: "{$.delegation[0].name}",
+		},
+		"netappSubnetServiceDelegation": {
+			Expected:          `Microsoft.Netapp/volumes`,
+			ResourceMapName:   plan.Address(`azurerm_subnet.subnet["netapp"]`),
+			AttributeJsonPath: "{$.delegation[0].service_delegation[0].name}",
+		},
+		"existingVnetRoleAssignmentAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("azurerm_role_assignment.existing_vnet_assignment[0]"),
+			AttributeJsonPath: "{$}",
+			Message:           "Roles are only assigned on an existing virtual network",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
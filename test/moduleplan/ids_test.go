@@ -0,0 +1,20 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+// The module plans pass the IDs of resources that the root module would create to the modules. The IDs are not
+// looked up, so they only need to be well formed.
+const (
+	resourceGroupID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/test-rg"
+	vnetID          = resourceGroupID + "/providers/Microsoft.Network/virtualNetworks/test-vnet"
+	nsgID           = resourceGroupID + "/providers/Microsoft.Network/networkSecurityGroups/test-nsg"
+	clusterID       = resourceGroupID + "/providers/Microsoft.ContainerService/managedClusters/test-aks"
+	uaiID           = resourceGroupID + "/providers/Microsoft.ManagedIdentity/userAssignedIdentities/test-aks-identity"
+	dnsZoneID       = resourceGroupID + "/providers/Microsoft.Network/privateDnsZones/privatelink.eastus.azmk8s.io"
+)
+
+// subnetID returns the ID of a subnet of the test virtual network.
+func subnetID(name string) string {
+	return vnetID + "/subnets/" + name
+}
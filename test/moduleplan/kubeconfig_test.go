@@ -0,0 +1,108 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package moduleplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// kubeconfigPlan returns a plan of the kubeconfig module with its required inputs.
+func kubeconfigPlan() helpers.ModulePlan {
+	return helpers.ModulePlan{
+		Module: "kubeconfig",
+		Inputs: map[string]interface{}{
+			"prefix":       "test",
+			"path":         "/tmp/test-aks-kubeconfig.conf",
+			"cluster_name": "test-aks",
+			"endpoint":     "https://test-aks.hcp.eastus.azmk8s.io:443",
+			"ca_crt":       "Y2EtY3J0",
+			"client_crt":   "Y2xpZW50LWNydA==",
+			"client_key":   "Y2xpZW50LWtleQ==",
+			"token":        "test-token",
+		},
+	}
+}
+
+// Test the service account and cluster role binding of a static kubeconfig, the module default.
+func TestPlanKubeconfigStatic(t *testing.T) {
+	t.Parallel()
+
+	plan := kubeconfigPlan()
+	tests := map[string]helpers.TestCase{
+		"serviceAccountName": {
+			Expected:          `test-cluster-admin-sa`,
+			ResourceMapName:   plan.Address("kubernetes_service_account.kubernetes_sa[0]"),
+			AttributeJsonPath: "{$.metadata[0].name}",
+		},
+		"serviceAccountNamespace": {
+			Expected:          `kube-system`,
+			ResourceMapName:   plan.Address("kubernetes_service_account.kubernetes_sa[0]"),
+			AttributeJsonPath: "{$.metadata[0].namespace}",
+		},
+		"secretType": {
+			Expected:          `kubernetes.io/service-account-token`,
+			ResourceMapName:   plan.Address("kubernetes_secret.sa_secret[0]"),
+			AttributeJsonPath: "{$.type}",
+		},
+		"clusterRoleBindingRole": {
+			Expected:          `cluster-admin`,
+			ResourceMapName:   plan.Address("kubernetes_cluster_role_binding.kubernetes_crb[0]"),
+			AttributeJsonPath: "{$.role_ref[0].name}",
+		},
+		"clusterRoleBindingSubject": {
+			Expected:          `test-cluster-admin-sa`,
+			ResourceMapName:   plan.Address("kubernetes_cluster_role_binding.kubernetes_crb[0]"),
+			AttributeJsonPath: "{$.subject[0].name}",
+		},
+		"kubeconfigFilename": {
+			Expected:          `/tmp/test-aks-kubeconfig.conf`,
+			ResourceMapName:   plan.Address("local_file.kubeconfig"),
+			AttributeJsonPath: "{$.filename}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
+
+// Test that a provider based kubeconfig uses the credentials of the cluster and creates no service account.
+func TestPlanKubeconfigProvider(t *testing.T) {
+	t.Parallel()
+
+	plan := kubeconfigPlan()
+	plan.Inputs["create_static_kubeconfig"] = false
+	tests := map[string]helpers.TestCase{
+		"serviceAccountAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("kubernetes_service_account.kubernetes_sa[0]"),
+			AttributeJsonPath: "{$}",
+		},
+		"clusterRoleBindingAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   plan.Address("kubernetes_cluster_role_binding.kubernetes_crb[0]"),
+			AttributeJsonPath: "{$}",
+		},
+		"kubeconfigServer": {
+			Expected:          `server: 'https://test-aks.hcp.eastus.azmk8s.io:443'`,
+			ResourceMapName:   plan.Address("local_file.kubeconfig"),
+			AttributeJsonPath: "{$.content}",
+			AssertFunction:    assert.Contains,
+		},
+		"kubeconfigCurrentContext": {
+			Expected:          `current-context: test-aks`,
+			ResourceMapName:   plan.Address("local_file.kubeconfig"),
+			AttributeJsonPath: "{$.content}",
+			AssertFunction:    assert.Contains,
+		},
+		"kubeconfigFilePermission": {
+			Expected:          `0644`,
+			ResourceMapName:   plan.Address("local_file.kubeconfig"),
+			AttributeJsonPath: "{$.file_permission}",
+		},
+	}
+
+	helpers.RunModulePlanTests(t, plan, tests)
+}
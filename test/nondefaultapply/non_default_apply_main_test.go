@@ -6,6 +6,8 @@ package defaultapply
 import (
 	"test/helpers"
 	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
 )
 
 // todo - add tests for non-default apply.  Overrides are currently for illustrative purposes only.
@@ -19,11 +21,8 @@ func TestApplyNonDefaultMain(t *testing.T) {
 	overrides["rbac_aad_enabled"] = true
 	overrides["storage_type"] = "ha"
 
-	// deferred cleanup routine for the resources created by the terrafrom init and apply after the test have been run
-	terraformOptions, _ := helpers.InitPlanAndApply(t, overrides)
-
-	defer helpers.DestroyDouble(t, terraformOptions)
-
-	// Drop in test cases here
-
+	// the teardown stage destroys the resources created by the setup stage after the validate stage has run
+	helpers.RunApplyStages(t, overrides, func(t *testing.T, terraformOptions *terraform.Options, plan *terraform.PlanStruct) {
+		// Drop in test cases here
+	})
 }
@@ -0,0 +1,153 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nondefaultplan
+
+import (
+	"test/helpers"
+	"testing"
+)
+
+// byoNetworkVariables returns the default variables with the existing resource groups, virtual network, subnets,
+// network security group and user assigned identity of sample-input-byo.tfvars.
+func byoNetworkVariables(t *testing.T, prefix string) map[string]interface{} {
+	variables := helpers.GetDefaultPlanVars(t)
+	variables["prefix"] = prefix
+	variables["resource_group_name"] = prefix + "-rg"
+	variables["vnet_resource_group_name"] = prefix + "-network-rg"
+	variables["vnet_name"] = prefix + "-vnet"
+	variables["subnet_names"] = map[string]interface{}{"aks": prefix + "-aks-subnet", "misc": prefix + "-misc-subnet"}
+	variables["nsg_name"] = prefix + "-nsg"
+	variables["aks_uai_name"] = prefix + "-uai"
+	variables["create_jump_public_ip"] = true
+	return variables
+}
+
+// Test that the existing network resources are used instead of being created, with their data sources stubbed
+// by helpers.BYONetworkStubs.
+func TestPlanBYONetwork(t *testing.T) {
+	t.Parallel()
+
+	variables := byoNetworkVariables(t, "byo")
+	stubs := helpers.BYONetworkStubs(variables)
+	// The IDs of the stubs, see helpers.BYONetworkStubs
+	networkResourceGroupID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/byo-network-rg"
+	vnetID := networkResourceGroupID + "/providers/Microsoft.Network/virtualNetworks/byo-vnet"
+
+	tests := map[string]helpers.TestCase{
+		"resourceGroupNotCreated": {
+			Expected:          `nil`,
+			ResourceMapName:   "azurerm_resource_group.aks_rg[0]",
+			AttributeJsonPath: "{$}",
+		},
+		"aksResourceGroup": {
+			Expected:          `byo-rg`,
+			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
+			AttributeJsonPath: "{$.resource_group_name}",
+		},
+		"vnetNotCreated": {
+			Expected:          `nil`,
+			ResourceMapName:   "module.vnet.azurerm_virtual_network.vnet[0]",
+			AttributeJsonPath: "{$}",
+		},
+		"aksSubnetNotCreated": {
+			Expected:          `nil`,
+			ResourceMapName:   "module.vnet.azurerm_subnet.subnet[\"aks\"]",
+			AttributeJsonPath: "{$}",
+		},
+		"byoAksSubnetId": {
+			Expected:          vnetID + "/subnets/byo-aks-subnet",
+			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
+			AttributeJsonPath: "{$.default_node_pool[0].vnet_subnet_id}",
+		},
+		"byoNodePoolSubnetId": {
+			Expected:          vnetID + "/subnets/byo-aks-subnet",
+			ResourceMapName:   "module.node_pools[\"stateless\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]",
+			AttributeJsonPath: "{$.vnet_subnet_id}",
+		},
+		"byoJumpSubnetId": {
+			Expected:          vnetID + "/subnets/byo-misc-subnet",
+			ResourceMapName:   "module.jump[0].azurerm_network_interface.vm_nic",
+			AttributeJsonPath: "{$.ip_configuration[0].subnet_id}",
+		},
+		"nsgNotCreated": {
+			Expected:          `nil`,
+			ResourceMapName:   "azurerm_network_security_group.nsg[0]",
+			AttributeJsonPath: "{$}",
+		},
+		"byoJumpNsgId": {
+			Expected:          networkResourceGroupID + "/providers/Microsoft.Network/networkSecurityGroups/byo-nsg",
+			ResourceMapName:   "module.jump[0].azurerm_network_interface_security_group_association.vm_nic_sg",
+			AttributeJsonPath: "{$.network_security_group_id}",
+		},
+		"byoSshRuleNsg": {
+			Expected:          `byo-nsg`,
+			ResourceMapName:   "azurerm_network_security_rule.vm-ssh[0]",
+			AttributeJsonPath: "{$.network_security_group_name}",
+		},
+		"byoSshRuleResourceGroup": {
+			Expected:          `byo-network-rg`,
+			ResourceMapName:   "azurerm_network_security_rule.vm-ssh[0]",
+			AttributeJsonPath: "{$.resource_group_name}",
+		},
+		"uaiNotCreated": {
+			Expected:          `nil`,
+			ResourceMapName:   "azurerm_user_assigned_identity.uai[0]",
+			AttributeJsonPath: "{$}",
+		},
+		"byoAksIdentityIds": {
+			Expected:          `["` + networkResourceGroupID + `/providers/Microsoft.ManagedIdentity/userAssignedIdentities/byo-uai"]`,
+			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
+			AttributeJsonPath: "{$.identity[0].identity_ids}",
+		},
+		"existingNetworkRoleAssignmentAbsent": {
+			Expected:          `nil`,
+			ResourceMapName:   "module.vnet.azurerm_role_assignment.existing_network_assignment[0]",
+			AttributeJsonPath: "{$}",
+			Message:           "Roles are only assigned to the identity that the root module creates",
+		},
+	}
+
+	plan := helpers.GetPlanWithDataStubs(t, variables, stubs)
+	helpers.RunTests(t, tests, plan)
+}
+
+// Test that a BYO virtual network and subnets can be used with a resource group and identity that the root
+// module creates.
+func TestPlanBYOVnetOnly(t *testing.T) {
+	t.Parallel()
+
+	variables := byoNetworkVariables(t, "byo-vnet-only")
+	delete(variables, "resource_group_name")
+	delete(variables, "vnet_resource_group_name")
+	delete(variables, "nsg_name")
+	delete(variables, "aks_uai_name")
+	stubs := helpers.BYONetworkStubs(variables)
+
+	tests := map[string]helpers.TestCase{
+		"resourceGroupCreated": {
+			Expected:          `byo-vnet-only-rg`,
+			ResourceMapName:   "azurerm_resource_group.aks_rg[0]",
+			AttributeJsonPath: "{$.name}",
+		},
+		"byoVnetOnlyAksSubnetId": {
+			Expected: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/byo-vnet-only-rg" +
+				"/providers/Microsoft.Network/virtualNetworks/byo-vnet-only-vnet/subnets/byo-vnet-only-aks-subnet",
+			ResourceMapName:   "module.aks.azurerm_kubernetes_cluster.aks",
+			AttributeJsonPath: "{$.default_node_pool[0].vnet_subnet_id}",
+		},
+		"existingVnetRoleAssignment": {
+			Expected:          `Network Contributor`,
+			ResourceMapName:   "module.vnet.azurerm_role_assignment.existing_vnet_assignment[0]",
+			AttributeJsonPath: "{$.role_definition_name}",
+		},
+		"existingNetworkRoleAssignment": {
+			Expected:          `Network Contributor`,
+			ResourceMapName:   "module.vnet.azurerm_role_assignment.existing_network_assignment[0]",
+			AttributeJsonPath: "{$.role_definition_name}",
+		},
+	}
+
+	plan := helpers.GetPlanWithDataStubs(t, variables, stubs)
+	helpers.RunTests(t, tests, plan)
+}
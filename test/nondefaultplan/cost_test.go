@@ -0,0 +1,46 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nondefaultplan
+
+import (
+	"strings"
+	"test/cost"
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Verify that the maximum monthly estimate of each example tfvars file stays under its budget.
+func TestPlanExampleCostBudgets(t *testing.T) {
+	t.Parallel()
+
+	prices, err := cost.LoadPriceSheet("../cost/price_sheet.json")
+	require.NoError(t, err)
+
+	// The maximum monthly budget of each example file, in the price sheet currency, is set in the test profile
+	config := helpers.GetTestConfig(t)
+	for exampleFileName, prefix := range examplePrefixes {
+		t.Run(exampleFileName, func(t *testing.T) {
+			t.Parallel()
+
+			variables := helpers.GetExamplePlanVars(t, exampleFileName)
+			variables["prefix"] = prefix
+			plan := helpers.GetPlanFromCache(t, variables)
+
+			estimate, err := cost.EstimatePlan(plan, prices)
+			require.NoError(t, err, "Price sheet is missing prices used by %s", exampleFileName)
+			out := &strings.Builder{}
+			require.NoError(t, estimate.Write(out))
+			t.Log("\n" + out.String())
+			budget, ok := config.Budget(exampleFileName)
+			if !ok {
+				t.Logf("The %s test profile has no budget for %s", config.Profile, exampleFileName)
+				return
+			}
+			assert.LessOrEqual(t, estimate.MaxMonthly, budget, "Maximum monthly estimate of %s exceeds its budget", exampleFileName)
+		})
+	}
+}
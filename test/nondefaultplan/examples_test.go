@@ -0,0 +1,22 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nondefaultplan
+
+// examplePrefixes maps the example tfvars files that are planned by the tests of this package to the prefix of
+// their plan, so the tests share the cached plans of the cost budget test. sample-input-byo.tfvars is skipped
+// since it requires existing network resources to plan.
+var examplePrefixes = map[string]string{
+	"sample-input.tfvars":                    "cost-sample",
+	"sample-input-cilium.tfvars":             "cost-cilium",
+	"sample-input-connect.tfvars":            "cost-connect",
+	"sample-input-defaults.tfvars":           "cost-defaults",
+	"sample-input-ha.tfvars":                 "cost-ha",
+	"sample-input-minimal.tfvars":            "cost-minimal",
+	"sample-input-multizone.tfvars":          "cost-multizone",
+	"sample-input-multizone-enhanced.tfvars": "cost-mz-enhanced",
+	"sample-input-optionalcas.tfvars":        "cost-optionalcas",
+	"sample-input-postgres.tfvars":           "cost-postgres",
+	"sample-input-ppg.tfvars":                "cost-ppg",
+	"sample-input-singlestore.tfvars":        "cost-singlestore",
+}
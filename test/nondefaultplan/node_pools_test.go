@@ -0,0 +1,160 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nondefaultplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"test/helpers"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Number of random node_pools samples planned per run. Each sample is a full terraform plan.
+const nodePoolsSampleCount = 3
+
+// Verify that randomly generated node_pools inputs are planned as one
+// azurerm_kubernetes_cluster_node_pool per entry with the requested settings.
+// A failing sample can be replayed by setting NODE_POOLS_SEED to the logged seed.
+func TestPlanGeneratedNodePools(t *testing.T) {
+	t.Parallel()
+
+	for _, seed := range helpers.NodePoolsSeeds(t, nodePoolsSampleCount) {
+		sample := helpers.GenerateNodePools(seed)
+		t.Run(fmt.Sprintf("seed=%d", seed), func(t *testing.T) {
+			t.Parallel()
+
+			variables := helpers.GetDefaultPlanVars(t)
+			sample.SetVariables(variables)
+			plan := helpers.GetPlanFromCache(t, variables)
+
+			nodePoolResources := 0
+			for name := range plan.ResourcePlannedValuesMap {
+				if strings.HasPrefix(name, "module.node_pools[") && strings.Contains(name, ".azurerm_kubernetes_cluster_node_pool.") {
+					nodePoolResources++
+				}
+			}
+			assert.Equal(t, len(sample.NodePools), nodePoolResources, "Expected one node pool resource per node_pools entry (seed %d)", seed)
+
+			proximityAssert := assert.Equal
+			if sample.ProximityPlacement {
+				proximityAssert = assert.NotEqual
+			}
+			helpers.RunTest(t, helpers.TestCase{
+				Expected:          "nil",
+				ResourceMapName:   "azurerm_proximity_placement_group.proximity[0]",
+				AttributeJsonPath: "{$}",
+				AssertFunction:    proximityAssert,
+				Message:           fmt.Sprintf("Unexpected proximity placement group (seed %d)", seed),
+			}, plan)
+
+			for name, pool := range sample.NodePools {
+				t.Run(name, func(t *testing.T) {
+					testGeneratedNodePool(t, seed, sample, name, pool, plan)
+				})
+			}
+		})
+	}
+}
+
+func testGeneratedNodePool(t *testing.T, seed int64, sample helpers.NodePoolsSample, name string, pool helpers.NodePool, plan *terraform.PlanStruct) {
+	resourceMapName := fmt.Sprintf("module.node_pools[\"%s\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]", name)
+	if pool.MinNodes == pool.MaxNodes {
+		resourceMapName = fmt.Sprintf("module.node_pools[\"%s\"].azurerm_kubernetes_cluster_node_pool.static_node_pool[0]", name)
+	}
+	_, exists := plan.ResourcePlannedValuesMap[resourceMapName]
+	require.True(t, exists, "Node pool resource %s not found in plan (seed %d)", resourceMapName, seed)
+
+	zones := pool.AvailabilityZones
+	if zones == nil {
+		zones = []string{"1"}
+		if sample.ProximityPlacement || !helpers.GetTestLocations(t)[0].HasZones() {
+			zones = []string{}
+		}
+	}
+
+	tests := map[string]helpers.TestCase{
+		"poolNameTest": {
+			Expected:          name,
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.name}",
+		},
+		"machineTypeTest": {
+			Expected:          pool.MachineType,
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.vm_size}",
+		},
+		"osDiskSizeTest": {
+			Expected:          strconv.Itoa(pool.OsDiskSize),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.os_disk_size_gb}",
+		},
+		"maxPodsTest": {
+			Expected:          strconv.Itoa(pool.MaxPods),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.max_pods}",
+		},
+		"nodeTaintsTest": {
+			Expected:          mustMarshal(t, pool.NodeTaints),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.node_taints}",
+		},
+		"nodeLabelsTest": {
+			Expected:          mustMarshal(t, pool.NodeLabels),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.node_labels}",
+		},
+		"zonesTest": {
+			Expected:          mustMarshal(t, zones),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.zones}",
+		},
+	}
+	if pool.MinNodes == pool.MaxNodes {
+		tests["nodeCountTest"] = helpers.TestCase{
+			Expected:          strconv.Itoa(pool.MinNodes),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.node_count}",
+		}
+	} else {
+		tests["minCountTest"] = helpers.TestCase{
+			Expected:          strconv.Itoa(pool.MinNodes),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.min_count}",
+		}
+		tests["maxCountTest"] = helpers.TestCase{
+			Expected:          strconv.Itoa(pool.MaxNodes),
+			ResourceMapName:   resourceMapName,
+			AttributeJsonPath: "{$.max_count}",
+		}
+	}
+	for testName, tc := range tests {
+		tc.Message = fmt.Sprintf("Unexpected %s value (seed %d)", tc.AttributeJsonPath, seed)
+		tests[testName] = tc
+	}
+	helpers.RunTests(t, tests, plan)
+
+	if pool.MinNodes != pool.MaxNodes {
+		minCount, err := helpers.RetrieveFromResourcePlannedValuesMap(plan, resourceMapName, "{$.min_count}")
+		require.NoError(t, err)
+		maxCount, err := helpers.RetrieveFromResourcePlannedValuesMap(plan, resourceMapName, "{$.max_count}")
+		require.NoError(t, err)
+		minNodes, err := strconv.Atoi(minCount)
+		require.NoError(t, err)
+		maxNodes, err := strconv.Atoi(maxCount)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, minNodes, maxNodes, "min_count must not exceed max_count (seed %d)", seed)
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(out)
+}
@@ -15,6 +15,7 @@ func TestPlanPostgresServers(t *testing.T) {
 	defaultPostgresServerName := "default"
 	postgresResourceMapName := "module.flex_postgresql[\"" + defaultPostgresServerName + "\"].azurerm_postgresql_flexible_server.flexpsql"
 	postgresFlexResourceMapName := "module.flex_postgresql[\"" + defaultPostgresServerName + "\"].azurerm_postgresql_flexible_server_configuration.flexpsql[\"max_prepared_transactions\"]"
+	postgresFlexSecureTransportResourceMapName := "module.flex_postgresql[\"" + defaultPostgresServerName + "\"].azurerm_postgresql_flexible_server_configuration.flexpsql[\"require_secure_transport\"]"
 
 	variables := helpers.GetDefaultPlanVars(t)
 	variables["prefix"] = "postgres-servers"
@@ -54,8 +55,10 @@ func TestPlanPostgresServers(t *testing.T) {
 			ResourceMapName:   postgresResourceMapName,
 			AttributeJsonPath: "{$.administrator_login}",
 		},
+		// The password is compared by hash so it is not shown in a failure message
 		"postgresFlexServerAdminPassword": {
-			Expected:          `my$up3rS3cretPassw0rd`,
+			Expected:          "sha256:4be699f80cb74b18c4a27f34d1e0ffa1ff144073a928cf0fa26686996f671c2d",
+			Retriever:         helpers.HashedRetriever(nil),
 			ResourceMapName:   postgresResourceMapName,
 			AttributeJsonPath: "{$.administrator_password}",
 		},
@@ -65,15 +68,15 @@ func TestPlanPostgresServers(t *testing.T) {
 			AttributeJsonPath: "{$.version}",
 		},
 		"postgresFlexServerSSLEnforcement": {
-			Expected:          `OFF`,
-			ResourceMapName:   postgresResourceMapName,
-			AttributeJsonPath: "{$.postgresql_configurations[*].require_secure_transport}",
-			AssertFunction:    assert.NotEqual,
+			Expected:          `nil`,
+			ResourceMapName:   postgresFlexSecureTransportResourceMapName,
+			AttributeJsonPath: "{$}",
+			Message:           "require_secure_transport should not be turned off when SSL is enforced",
 		},
-		"postgresFlexServerVnetId": {
-			Expected:          ``,
+		"postgresFlexServerDelegatedSubnetId": {
 			ResourceMapName:   postgresResourceMapName,
-			AttributeJsonPath: "{$.virtual_network_id}",
+			AttributeJsonPath: "{$.delegated_subnet_id}",
+			ExpectAbsent:      true,
 		},
 		"postgresFlexServerConfigurationMaxPreparedTransactionsName": {
 			Expected:          `max_prepared_transactions`,
@@ -0,0 +1,30 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nondefaultplan
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Verify that the outputs carrying secrets are sensitive in the plan of each example tfvars file, so they are
+// never shown in clear text in the terraform output or the CI logs.
+func TestPlanSensitiveOutputs(t *testing.T) {
+	t.Parallel()
+
+	for exampleFileName, prefix := range examplePrefixes {
+		t.Run(exampleFileName, func(t *testing.T) {
+			t.Parallel()
+
+			variables := helpers.GetExamplePlanVars(t, exampleFileName)
+			variables["prefix"] = prefix
+			plan := helpers.GetPlanFromCache(t, variables)
+
+			assert.Empty(t, helpers.AuditSensitiveOutputs(plan, helpers.DefaultSensitiveOutputPolicy),
+				"Outputs carrying secrets are exposed in clear text in the plan of %s", exampleFileName)
+		})
+	}
+}
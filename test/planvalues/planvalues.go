@@ -0,0 +1,47 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package planvalues reads the attribute values of the resources of a plan, as decoded from the JSON plan. A
+// missing attribute or an attribute of another type reads as the zero value.
+package planvalues
+
+// String returns the string attribute of the values, or "" if it is not a string.
+func String(values map[string]interface{}, name string) string {
+	value, _ := values[name].(string)
+	return value
+}
+
+// Number returns the number attribute of the values, or 0 if it is not a number.
+func Number(values map[string]interface{}, name string) float64 {
+	value, _ := values[name].(float64)
+	return value
+}
+
+// Bool returns the bool attribute of the values, or false if it is not a bool.
+func Bool(values map[string]interface{}, name string) bool {
+	value, _ := values[name].(bool)
+	return value
+}
+
+// Strings returns the strings of the list attribute of the values, skipping the items that are not strings.
+func Strings(values map[string]interface{}, name string) []string {
+	items, _ := values[name].([]interface{})
+	var list []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// FirstBlock returns the first block of the nested block attribute of the values, e.g. default_node_pool, or an
+// empty map if the block is not set.
+func FirstBlock(values map[string]interface{}, name string) map[string]interface{} {
+	blocks, _ := values[name].([]interface{})
+	if len(blocks) == 0 {
+		return map[string]interface{}{}
+	}
+	block, _ := blocks[0].(map[string]interface{})
+	return block
+}
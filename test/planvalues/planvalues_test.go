@@ -0,0 +1,35 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package planvalues
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAttributes verifies that the attributes are read with their type, and that missing attributes or
+// attributes of another type read as the zero value.
+func TestAttributes(t *testing.T) {
+	values := map[string]interface{}{
+		"name":               "aks",
+		"node_count":         float64(2),
+		"host_encryption":    true,
+		"zones":              []interface{}{"1", 2, "3"},
+		"default_node_pool":  []interface{}{map[string]interface{}{"vm_size": "Standard_D4s_v5"}},
+		"linux_profile":      []interface{}{},
+		"private_cluster_id": nil,
+	}
+
+	assert.Equal(t, "aks", String(values, "name"))
+	assert.Equal(t, "", String(values, "node_count"))
+	assert.Equal(t, float64(2), Number(values, "node_count"))
+	assert.True(t, Bool(values, "host_encryption"))
+	assert.False(t, Bool(values, "private_cluster_id"))
+	assert.Equal(t, []string{"1", "3"}, Strings(values, "zones"))
+	assert.Nil(t, Strings(values, "name"))
+	assert.Equal(t, "Standard_D4s_v5", String(FirstBlock(values, "default_node_pool"), "vm_size"))
+	assert.Empty(t, FirstBlock(values, "linux_profile"))
+	assert.Empty(t, FirstBlock(values, "missing"))
+}
@@ -0,0 +1,330 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package providerschema validates the resource addresses and jsonpath queries of the plan test tables against
+// a stored snapshot of the provider schemas, without running terraform.
+package providerschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ValidatedProviders are the providers whose resource types are validated. Resource types of other providers,
+// such as tls and null, are not in the snapshot and are not validated.
+var ValidatedProviders = []string{"azurerm", "kubernetes", "cloudinit"}
+
+// Snapshot is the stored output of 'terraform providers schema -json' for the ValidatedProviders, without the
+// descriptions, along with the provider versions it was read from.
+type Snapshot struct {
+	// Versions maps the provider source addresses, e.g. registry.terraform.io/hashicorp/azurerm, to their version
+	Versions map[string]string       `json:"versions"`
+	Schemas  *tfjson.ProviderSchemas `json:"schemas"`
+}
+
+// LoadSnapshot reads a Snapshot from a JSON file.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("parsing provider schema snapshot %s: %w", path, err)
+	}
+	if snapshot.Schemas == nil {
+		return nil, fmt.Errorf("provider schema snapshot %s has no schemas", path)
+	}
+	return snapshot, nil
+}
+
+// Trim returns the schemas of the providers, without the descriptions and the provider configuration.
+func Trim(schemas *tfjson.ProviderSchemas, providers []string) *tfjson.ProviderSchemas {
+	trimmed := &tfjson.ProviderSchemas{
+		FormatVersion: schemas.FormatVersion,
+		Schemas:       make(map[string]*tfjson.ProviderSchema),
+	}
+	for source, schema := range schemas.Schemas {
+		if !containsString(providers, providerName(source)) {
+			continue
+		}
+		provider := &tfjson.ProviderSchema{
+			ResourceSchemas:   schema.ResourceSchemas,
+			DataSourceSchemas: schema.DataSourceSchemas,
+		}
+		for _, resource := range provider.ResourceSchemas {
+			trimBlock(resource.Block)
+		}
+		for _, dataSource := range provider.DataSourceSchemas {
+			trimBlock(dataSource.Block)
+		}
+		trimmed.Schemas[source] = provider
+	}
+	return trimmed
+}
+
+func trimBlock(block *tfjson.SchemaBlock) {
+	if block == nil {
+		return
+	}
+	block.Description = ""
+	block.DescriptionKind = ""
+	trimAttributes(block.Attributes)
+	for _, nested := range block.NestedBlocks {
+		trimBlock(nested.Block)
+	}
+}
+
+func trimAttributes(attributes map[string]*tfjson.SchemaAttribute) {
+	for _, attribute := range attributes {
+		attribute.Description = ""
+		attribute.DescriptionKind = ""
+		if attribute.AttributeNestedType != nil {
+			trimAttributes(attribute.AttributeNestedType.Attributes)
+		}
+	}
+}
+
+// Version returns the version of the provider, e.g. azurerm, that the snapshot was read from.
+func (s *Snapshot) Version(provider string) string {
+	for source, version := range s.Versions {
+		if providerName(source) == provider {
+			return version
+		}
+	}
+	return ""
+}
+
+// Validate returns an error if the resource type of the address does not exist, or if the jsonpath query names
+// an attribute or block that the resource type does not have. Resources of providers that are not in the
+// snapshot and queries using filters or recursive descent are not validated.
+func (s *Snapshot) Validate(address string, jsonPath string) error {
+	resourceType, data, err := ParseAddress(address)
+	if err != nil {
+		return err
+	}
+	provider, _, _ := strings.Cut(resourceType, "_")
+	var providerSchema *tfjson.ProviderSchema
+	for source, schema := range s.Schemas.Schemas {
+		if providerName(source) == provider {
+			providerSchema = schema
+		}
+	}
+	if providerSchema == nil {
+		return nil
+	}
+
+	schemas, kind := providerSchema.ResourceSchemas, "resource"
+	if data {
+		schemas, kind = providerSchema.DataSourceSchemas, "data source"
+	}
+	schema, ok := schemas[resourceType]
+	if !ok || schema.Block == nil {
+		return fmt.Errorf("%s type %s of %s does not exist in the %s provider", kind, resourceType, address, provider)
+	}
+
+	segments, ok := parseJsonPath(jsonPath)
+	if !ok {
+		return nil
+	}
+	if err := validateBlock(schema.Block, segments, "$"); err != nil {
+		return fmt.Errorf("%s of %s: %w", jsonPath, resourceType, err)
+	}
+	return nil
+}
+
+// ParseAddress returns the resource type of a resource address such as
+// module.node_pools["cas"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0], and whether it is a
+// data source.
+func ParseAddress(address string) (string, bool, error) {
+	parts := splitAddress(address)
+	for i := 0; i < len(parts); i++ {
+		switch name := stripIndex(parts[i]); {
+		case name == "module":
+			i++
+		case name == "data" && i+2 < len(parts):
+			return parts[i+1], true, nil
+		case i+1 < len(parts):
+			return name, false, nil
+		}
+	}
+	return "", false, fmt.Errorf("%s is not a resource address", address)
+}
+
+// splitAddress splits an address on the dots that are not in an index.
+func splitAddress(address string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, char := range address {
+		switch char {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, address[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, address[start:])
+}
+
+func stripIndex(part string) string {
+	name, _, _ := strings.Cut(part, "[")
+	return name
+}
+
+// segment is a field, an index or a wildcard of a jsonpath query.
+type segment struct {
+	text  string
+	field string
+	index bool
+}
+
+// jsonPathSegment matches a field, an index, a wildcard or a quoted field at the start of a jsonpath query
+var jsonPathSegment = regexp.MustCompile(`^(?:\.([A-Za-z0-9_\-]+)|\[(\d+|\*)\]|\['([^']*)'\])`)
+
+// parseJsonPath returns the segments of a jsonpath query made of fields, indexes and wildcards only.
+func parseJsonPath(jsonPath string) ([]segment, bool) {
+	rest := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(jsonPath, "{"), "}"), "$")
+	if !strings.HasPrefix(jsonPath, "{") || !strings.HasSuffix(jsonPath, "}") {
+		return nil, false
+	}
+	var segments []segment
+	for rest != "" {
+		match := jsonPathSegment.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, false
+		}
+		segments = append(segments, segment{text: match[0], field: match[1] + match[3], index: match[2] != ""})
+		rest = rest[len(match[0]):]
+	}
+	return segments, true
+}
+
+func validateBlock(block *tfjson.SchemaBlock, segments []segment, path string) error {
+	if len(segments) == 0 {
+		return nil
+	}
+	current, rest := segments[0], segments[1:]
+	if current.index {
+		return fmt.Errorf("%s is an object, not a list", path)
+	}
+	if attribute, ok := block.Attributes[current.field]; ok {
+		if attribute.AttributeNestedType != nil {
+			return validateNested(attribute.AttributeNestedType, rest, path+current.text)
+		}
+		return validateType(attribute.AttributeType, rest, path+current.text)
+	}
+	nested, ok := block.NestedBlocks[current.field]
+	if !ok || nested.Block == nil {
+		return fmt.Errorf("%s has no attribute or block %s, available: %s", path, current.field, blockKeys(block))
+	}
+	path += current.text
+	switch nested.NestingMode {
+	case tfjson.SchemaNestingModeSingle, tfjson.SchemaNestingModeGroup:
+	case tfjson.SchemaNestingModeMap:
+		if len(rest) > 0 {
+			if rest[0].index {
+				return fmt.Errorf("%s is a map of blocks, not a list", path)
+			}
+			path, rest = path+rest[0].text, rest[1:]
+		}
+	default:
+		if len(rest) > 0 {
+			if !rest[0].index {
+				return fmt.Errorf("%s is a %s of blocks, index it before %s", path, nested.NestingMode, rest[0].field)
+			}
+			path, rest = path+rest[0].text, rest[1:]
+		}
+	}
+	return validateBlock(nested.Block, rest, path)
+}
+
+func validateNested(nested *tfjson.SchemaNestedAttributeType, segments []segment, path string) error {
+	block := &tfjson.SchemaBlock{Attributes: nested.Attributes}
+	if nested.NestingMode == tfjson.SchemaNestingModeSingle || len(segments) == 0 {
+		return validateBlock(block, segments, path)
+	}
+	if segments[0].index != (nested.NestingMode != tfjson.SchemaNestingModeMap) {
+		return fmt.Errorf("%s is a %s, %s cannot be used", path, nested.NestingMode, segments[0].text)
+	}
+	return validateBlock(block, segments[1:], path+segments[0].text)
+}
+
+func validateType(attributeType cty.Type, segments []segment, path string) error {
+	if len(segments) == 0 || attributeType == cty.DynamicPseudoType {
+		return nil
+	}
+	current, rest := segments[0], segments[1:]
+	switch {
+	case attributeType.IsListType(), attributeType.IsSetType():
+		if !current.index {
+			return fmt.Errorf("%s is a %s, index it before %s", path, attributeType.FriendlyName(), current.field)
+		}
+		return validateType(attributeType.ElementType(), rest, path+current.text)
+	case attributeType.IsTupleType():
+		if !current.index {
+			return fmt.Errorf("%s is a tuple, index it before %s", path, current.field)
+		}
+		return nil
+	case attributeType.IsMapType():
+		if current.index {
+			return fmt.Errorf("%s is a %s, not a list", path, attributeType.FriendlyName())
+		}
+		return validateType(attributeType.ElementType(), rest, path+current.text)
+	case attributeType.IsObjectType():
+		if current.index {
+			return fmt.Errorf("%s is an object, not a list", path)
+		}
+		if !attributeType.HasAttribute(current.field) {
+			return fmt.Errorf("%s has no attribute %s, available: %s", path, current.field, objectKeys(attributeType))
+		}
+		return validateType(attributeType.AttributeType(current.field), rest, path+current.text)
+	}
+	return fmt.Errorf("%s is a %s, %s cannot be used", path, attributeType.FriendlyName(), current.text)
+}
+
+func blockKeys(block *tfjson.SchemaBlock) string {
+	keys := make([]string, 0, len(block.Attributes)+len(block.NestedBlocks))
+	for key := range block.Attributes {
+		keys = append(keys, key)
+	}
+	for key := range block.NestedBlocks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+func objectKeys(objectType cty.Type) string {
+	keys := make([]string, 0, len(objectType.AttributeTypes()))
+	for key := range objectType.AttributeTypes() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// providerName returns the name of a provider source address, e.g. azurerm for registry.terraform.io/hashicorp/azurerm.
+func providerName(source string) string {
+	return source[strings.LastIndex(source, "/")+1:]
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
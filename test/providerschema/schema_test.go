@@ -0,0 +1,225 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package providerschema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSnapshot is a snapshot with a part of the azurerm_linux_virtual_machine and azurerm_subnet schemas.
+const testSnapshot = `{
+  "versions": {"registry.terraform.io/hashicorp/azurerm": "4.48.0"},
+  "schemas": {
+    "format_version": "1.0",
+    "provider_schemas": {
+      "registry.terraform.io/hashicorp/azurerm": {
+        "resource_schemas": {
+          "azurerm_linux_virtual_machine": {
+            "version": 0,
+            "block": {
+              "attributes": {
+                "size": {"type": "string", "required": true},
+                "zone": {"type": "string", "optional": true},
+                "tags": {"type": ["map", "string"], "optional": true},
+                "identity_ids": {"type": ["set", "string"], "optional": true},
+                "settings": {"type": ["object", {"level": "string"}], "optional": true}
+              },
+              "block_types": {
+                "os_disk": {
+                  "nesting_mode": "list",
+                  "block": {"attributes": {"caching": {"type": "string", "required": true}}},
+                  "min_items": 1,
+                  "max_items": 1
+                }
+              }
+            }
+          }
+        },
+        "data_source_schemas": {
+          "azurerm_subnet": {
+            "version": 0,
+            "block": {"attributes": {"address_prefixes": {"type": ["list", "string"], "computed": true}}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func loadTestSnapshot(t *testing.T) *Snapshot {
+	path := filepath.Join(t.TempDir(), "provider_schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(testSnapshot), 0644))
+	snapshot, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	return snapshot
+}
+
+// TestValidate verifies that missing resource types and attributes are reported, and that queries of existing
+// attributes, blocks and nested values are not.
+func TestValidate(t *testing.T) {
+	snapshot := loadTestSnapshot(t)
+	vm := "module.nfs[0].azurerm_linux_virtual_machine.vm"
+
+	tests := map[string]struct {
+		address  string
+		jsonPath string
+		err      string
+	}{
+		"root":            {vm, "{$}", ""},
+		"attribute":       {vm, "{$.size}", ""},
+		"block":           {vm, "{$.os_disk[0].caching}", ""},
+		"blockWildcard":   {vm, "{$.os_disk[*].caching}", ""},
+		"mapKey":          {vm, "{$.tags.environment}", ""},
+		"setIndex":        {vm, "{$.identity_ids[0]}", ""},
+		"objectAttribute": {vm, "{$.settings.level}", ""},
+		"filter":          {vm, "{$.os_disk[?(@.caching==\"None\")]}", ""},
+		"otherProvider":   {"module.jump[0].tls_private_key.ssh", "{$.anything}", ""},
+		"dataSource":      {"data.azurerm_subnet.aks[0]", "{$.address_prefixes[0]}", ""},
+		"typo": {vm, "{$.vm_zone}", "{$.vm_zone} of azurerm_linux_virtual_machine: $ has no attribute or block vm_zone, " +
+			"available: identity_ids, os_disk, settings, size, tags, zone"},
+		"blockNotIndexed": {vm, "{$.os_disk.caching}", "{$.os_disk.caching} of azurerm_linux_virtual_machine: $.os_disk is a list of blocks, index it before caching"},
+		"nestedTypo":      {vm, "{$.os_disk[0].cache}", "{$.os_disk[0].cache} of azurerm_linux_virtual_machine: $.os_disk[0] has no attribute or block cache, available: caching"},
+		"primitive":       {vm, "{$.size.name}", "{$.size.name} of azurerm_linux_virtual_machine: $.size is a string, .name cannot be used"},
+		"objectTypo":      {vm, "{$.settings.lvl}", "{$.settings.lvl} of azurerm_linux_virtual_machine: $.settings has no attribute lvl, available: level"},
+		"resourceType":    {"azurerm_linux_vm.vm", "{$}", "resource type azurerm_linux_vm of azurerm_linux_vm.vm does not exist in the azurerm provider"},
+		"dataSourceType":  {"data.azurerm_subnets.aks", "{$}", "data source type azurerm_subnets of data.azurerm_subnets.aks does not exist in the azurerm provider"},
+		"notAnAddress":    {"location", "{$}", "location is not a resource address"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := snapshot.Validate(tc.address, tc.jsonPath)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+	assert.Equal(t, "4.48.0", snapshot.Version("azurerm"))
+}
+
+// TestFindTableCases verifies that the test cases querying the planned values of resources are found in the
+// test tables and tuple test tables, with constants resolved.
+func TestFindTableCases(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plan_test.go"), []byte(`package plan
+
+func TestPlanExample(t *testing.T) {
+	vmName := "module.nfs[0].azurerm_linux_virtual_machine.vm"
+	tests := map[string]helpers.TestCase{
+		"size": {
+			Expected:          "Standard_D4s_v5",
+			ResourceMapName:   vmName,
+			AttributeJsonPath: "{$.size}",
+		},
+		"exists": {
+			Expected:        "nil",
+			ResourceMapName: vmName,
+			AssertFunction:  assert.NotEqual,
+		},
+		"location": {
+			Expected:        "eastus",
+			Retriever:       helpers.RetrieveFromRawPlan,
+			ResourceMapName: "location",
+		},
+	}
+	helpers.RunDefaultPlanTests(t, tests)
+
+	tuples := map[string]helpers.TupleTestCase{
+		"aks": {
+			Expected: map[string]helpers.AttrTuple{
+				"prefixes": {"[]", "{$.address_prefixes}"},
+			},
+		},
+	}
+	helpers.RunDefaultPlanTupleTests(t, "module.vnet.azurerm_subnet.subnet[\"%s\"]", tuples)
+
+	plan := helpers.ModulePlan{Module: "azurerm_vm"}
+	helpers.RunModulePlanTests(t, plan, map[string]helpers.TestCase{
+		"vmSize": {
+			Expected:          "Standard_E8s_v5",
+			ResourceMapName:   plan.Address("azurerm_linux_virtual_machine.vm"),
+			AttributeJsonPath: "{$.size}",
+		},
+	})
+}
+`), 0644))
+
+	cases, err := FindTableCases(dir)
+	require.NoError(t, err)
+	for i := range cases {
+		cases[i].Position.Filename = filepath.Base(cases[i].Position.Filename)
+	}
+	assert.Equal(t, []string{
+		"plan_test.go:6:11: TestPlanExample size",
+		"plan_test.go:11:13: TestPlanExample exists",
+		"plan_test.go:27:17: TestPlanExample aks/prefixes",
+		"plan_test.go:35:13: TestPlanExample vmSize",
+	}, tableCaseNames(cases))
+	assert.Equal(t, "{$}", cases[1].AttributeJsonPath)
+	assert.Equal(t, `module.vnet.azurerm_subnet.subnet["aks"]`, cases[2].ResourceMapName)
+	assert.Equal(t, "module.plan.azurerm_linux_virtual_machine.vm", cases[3].ResourceMapName)
+
+	assert.Equal(t, []string{
+		"plan_test.go:27:17: TestPlanExample aks/prefixes: resource type azurerm_subnet of " +
+			`module.vnet.azurerm_subnet.subnet["aks"] does not exist in the azurerm provider`,
+	}, ValidateTableCases(loadTestSnapshot(t), cases))
+}
+
+func tableCaseNames(cases []TableCase) []string {
+	var names []string
+	for _, tc := range cases {
+		names = append(names, tc.String())
+	}
+	return names
+}
+
+// versionPattern matches the providers of the required_providers block of versions.tf pinned to a version.
+var versionPattern = regexp.MustCompile(`(\w+)\s*=\s*\{\s*source\s*=\s*"[^"]+"\s*version\s*=\s*"(\d+\.\d+\.\d+)"`)
+
+// Verify that the resource addresses and jsonpath queries of the plan test tables exist in the provider schemas,
+// without running a plan. Regenerate the snapshot with 'go run ./cmd/providerschema' from the test directory
+// when the provider versions change.
+func TestPlanTablesMatchProviderSchema(t *testing.T) {
+	snapshot, err := LoadSnapshot("provider_schema.json")
+	require.NoError(t, err, "Generate provider_schema.json with 'go run ./cmd/providerschema' from the test directory")
+
+	versions, err := os.ReadFile("../../versions.tf")
+	require.NoError(t, err)
+	for _, match := range versionPattern.FindAllStringSubmatch(string(versions), -1) {
+		if containsString(ValidatedProviders, match[1]) {
+			assert.Equal(t, match[2], snapshot.Version(match[1]),
+				"provider_schema.json is not for the %s version of versions.tf, regenerate it with 'go run ./cmd/providerschema'", match[1])
+		}
+	}
+
+	cases, err := FindTableCases("../defaultplan", "../nondefaultplan", "../moduleplan")
+	require.NoError(t, err)
+	for _, finding := range ValidateTableCases(snapshot, cases) {
+		assert.Fail(t, "Test case queries an attribute that does not exist", finding)
+	}
+}
+
+// TestTrim verifies that the descriptions and the providers that are not validated are removed.
+func TestTrim(t *testing.T) {
+	snapshot := loadTestSnapshot(t)
+	resource := snapshot.Schemas.Schemas["registry.terraform.io/hashicorp/azurerm"].ResourceSchemas["azurerm_linux_virtual_machine"]
+	resource.Block.Description = "Manages a Linux Virtual Machine."
+	resource.Block.Attributes["size"].Description = "The SKU of the Virtual Machine."
+	snapshot.Schemas.Schemas["registry.terraform.io/hashicorp/tls"] = snapshot.Schemas.Schemas["registry.terraform.io/hashicorp/azurerm"]
+
+	trimmed := Trim(snapshot.Schemas, ValidatedProviders)
+	data, err := json.Marshal(trimmed)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Virtual Machine")
+	assert.Len(t, trimmed.Schemas, 1)
+}
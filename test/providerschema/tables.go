@@ -0,0 +1,281 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package providerschema
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+)
+
+// TableCase is a test case of a plan test table that queries the planned values of a resource.
+type TableCase struct {
+	Position          token.Position
+	Test              string
+	Key               string
+	ResourceMapName   string
+	AttributeJsonPath string
+}
+
+func (c TableCase) String() string {
+	return fmt.Sprintf("%s: %s %s", c.Position, c.Test, c.Key)
+}
+
+// resourceRetrievers are the retrievers whose test cases query the planned values of a resource. Test cases
+// with other retrievers, such as RetrieveFromRawPlan, query variables or outputs and are not validated.
+var resourceRetrievers = []string{"RetrieveFromResourcePlannedValuesMap", "HashedRetriever"}
+
+// FindTableCases parses the test files of the directories and returns the helpers.TestCase entries of their
+// test tables, and the helpers.AttrTuple entries of the tuple test tables that are run, whose resource
+// address and jsonpath query are string literals or constants.
+func FindTableCases(dirs ...string) ([]TableCase, error) {
+	var cases []TableCase
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			fileSet := token.NewFileSet()
+			parsed, err := parser.ParseFile(fileSet, file, nil, 0)
+			if err != nil {
+				return nil, err
+			}
+			for _, decl := range parsed.Decls {
+				if function, ok := decl.(*ast.FuncDecl); ok && function.Body != nil {
+					cases = append(cases, findInFunction(fileSet, function)...)
+				}
+			}
+		}
+	}
+	return cases, nil
+}
+
+func findInFunction(fileSet *token.FileSet, function *ast.FuncDecl) []TableCase {
+	var cases []TableCase
+	// A helpers.TestCase literal in a table of helpers.TestCase literals is found twice
+	seen := make(map[*ast.CompositeLit]bool)
+	ast.Inspect(function.Body, func(node ast.Node) bool {
+		switch typed := node.(type) {
+		case *ast.CompositeLit:
+			for _, entry := range tableEntries(typed, "TestCase") {
+				if tc, ok := testCase(entry.value); ok && !seen[entry.value] {
+					seen[entry.value] = true
+					tc.Position, tc.Key = fileSet.Position(entry.value.Pos()), entry.key
+					cases = append(cases, tc)
+				}
+			}
+		case *ast.CallExpr:
+			if isHelper(typed.Fun, "RunTupleTests", "RunDefaultPlanTupleTests") && len(typed.Args) >= 3 {
+				cases = append(cases, tupleCases(fileSet, typed.Args[1], typed.Args[2])...)
+			}
+		}
+		return true
+	})
+	for i := range cases {
+		cases[i].Test = function.Name.Name
+	}
+	return cases
+}
+
+// tupleCases returns the attributes of the tuple test table, with the address of the resource of each entry
+// formatted from format.
+func tupleCases(fileSet *token.FileSet, formatArg ast.Expr, tableArg ast.Expr) []TableCase {
+	format, ok := stringValue(formatArg)
+	table := compositeValue(tableArg)
+	if !ok || table == nil {
+		return nil
+	}
+	var cases []TableCase
+	for _, entry := range tableEntries(table, "TupleTestCase") {
+		expected := compositeValue(field(entry.value, "Expected", 0))
+		if expected == nil {
+			continue
+		}
+		for _, attribute := range tableEntries(expected, "AttrTuple") {
+			jsonPath, ok := stringValue(field(attribute.value, "JsonPath", 1))
+			if !ok {
+				continue
+			}
+			cases = append(cases, TableCase{
+				Position:          fileSet.Position(attribute.value.Pos()),
+				Key:               entry.key + "/" + attribute.key,
+				ResourceMapName:   fmt.Sprintf(format, entry.key),
+				AttributeJsonPath: jsonPath,
+			})
+		}
+	}
+	return cases
+}
+
+// testCase returns the resource address and jsonpath query of a helpers.TestCase literal that queries the
+// planned values of a resource.
+func testCase(literal *ast.CompositeLit) (TableCase, bool) {
+	if retriever := field(literal, "Retriever", -1); retriever != nil {
+		if call, ok := retriever.(*ast.CallExpr); ok {
+			retriever = call.Fun
+		}
+		if !isHelper(retriever, resourceRetrievers...) {
+			return TableCase{}, false
+		}
+	}
+	resourceMapName, ok := stringValue(field(literal, "ResourceMapName", -1))
+	if !ok {
+		return TableCase{}, false
+	}
+	jsonPath, ok := stringValue(field(literal, "AttributeJsonPath", -1))
+	if !ok {
+		jsonPath = "{$}"
+	}
+	return TableCase{ResourceMapName: resourceMapName, AttributeJsonPath: jsonPath}, true
+}
+
+type tableEntry struct {
+	key   string
+	value *ast.CompositeLit
+}
+
+// tableEntries returns the literals of the helpers type typeName in a literal of that type, or in a map or slice
+// literal of it, with their map keys.
+func tableEntries(literal *ast.CompositeLit, typeName string) []tableEntry {
+	var elementType ast.Expr
+	switch typed := literal.Type.(type) {
+	case *ast.MapType:
+		elementType = typed.Value
+	case *ast.ArrayType:
+		elementType = typed.Elt
+	default:
+		if isHelper(literal.Type, typeName) {
+			return []tableEntry{{value: literal}}
+		}
+		return nil
+	}
+	if !isHelper(elementType, typeName) {
+		return nil
+	}
+	var entries []tableEntry
+	for _, element := range literal.Elts {
+		var key string
+		if keyValue, ok := element.(*ast.KeyValueExpr); ok {
+			key, _ = stringValue(keyValue.Key)
+			element = keyValue.Value
+		}
+		if value, ok := element.(*ast.CompositeLit); ok {
+			entries = append(entries, tableEntry{key: key, value: value})
+		}
+	}
+	return entries
+}
+
+// field returns the value of a field of a struct literal, given by name or by its index in a literal without
+// field names. Pass an index of -1 for fields that are always named.
+func field(literal *ast.CompositeLit, name string, index int) ast.Expr {
+	for i, element := range literal.Elts {
+		keyValue, ok := element.(*ast.KeyValueExpr)
+		if !ok {
+			if i == index {
+				return element
+			}
+			continue
+		}
+		if ident, ok := keyValue.Key.(*ast.Ident); ok && ident.Name == name {
+			return keyValue.Value
+		}
+	}
+	return nil
+}
+
+// isHelper returns whether the expression is one of the names, qualified by the helpers package.
+func isHelper(expr ast.Expr, names ...string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "helpers" && containsString(names, selector.Sel.Name)
+}
+
+// stringValue returns the value of a string literal, or of a constant or variable of the file declared with one.
+// The address of a resource of a module plan, such as plan.Address("azurerm_linux_virtual_machine.vm"), is the
+// address of the resource in a module named after the plan variable, which is enough to know its type.
+func stringValue(expr ast.Expr) (string, bool) {
+	switch typed := expr.(type) {
+	case *ast.BasicLit:
+		if typed.Kind == token.STRING {
+			value, err := strconv.Unquote(typed.Value)
+			return value, err == nil
+		}
+	case *ast.Ident:
+		if value := declaredValue(typed); value != nil {
+			return stringValue(value)
+		}
+	case *ast.BinaryExpr:
+		if typed.Op == token.ADD {
+			left, leftOk := stringValue(typed.X)
+			right, rightOk := stringValue(typed.Y)
+			return left + right, leftOk && rightOk
+		}
+	case *ast.CallExpr:
+		selector, ok := typed.Fun.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "Address" || len(typed.Args) != 1 {
+			break
+		}
+		plan, ok := selector.X.(*ast.Ident)
+		resource, resourceOk := stringValue(typed.Args[0])
+		if ok && resourceOk {
+			return "module." + plan.Name + "." + resource, true
+		}
+	}
+	return "", false
+}
+
+// compositeValue returns a composite literal, or the one a variable of the file is declared with.
+func compositeValue(expr ast.Expr) *ast.CompositeLit {
+	switch typed := expr.(type) {
+	case *ast.CompositeLit:
+		return typed
+	case *ast.Ident:
+		if value, ok := declaredValue(typed).(*ast.CompositeLit); ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// declaredValue returns the expression a constant or variable is declared with in the file, if it is declared
+// with a single value.
+func declaredValue(ident *ast.Ident) ast.Expr {
+	if ident.Obj == nil {
+		return nil
+	}
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.ValueSpec:
+		for i, name := range decl.Names {
+			if name.Name == ident.Name && i < len(decl.Values) {
+				return decl.Values[i]
+			}
+		}
+	case *ast.AssignStmt:
+		for i, name := range decl.Lhs {
+			if lhs, ok := name.(*ast.Ident); ok && lhs.Name == ident.Name && i < len(decl.Rhs) && len(decl.Lhs) == len(decl.Rhs) {
+				return decl.Rhs[i]
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTableCases returns the table cases whose resource type or attribute does not exist in the snapshot.
+func ValidateTableCases(snapshot *Snapshot, cases []TableCase) []string {
+	var findings []string
+	for _, tc := range cases {
+		if err := snapshot.Validate(tc.ResourceMapName, tc.AttributeJsonPath); err != nil {
+			findings = append(findings, fmt.Sprintf("%s: %s", tc, err))
+		}
+	}
+	return findings
+}
@@ -0,0 +1,228 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package quota compares the worst-case vCPUs of a planned deployment with the
+// regional vCPU quotas of a subscription.
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"test/sku"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// TotalRegionalVCPUs is the usage name of the total regional vCPU quota.
+const TotalRegionalVCPUs = "cores"
+
+// Usage is a single entry of the 'az vm list-usage --location <location> -o json' output.
+type Usage struct {
+	CurrentValue count     `json:"currentValue"`
+	Limit        count     `json:"limit"`
+	LocalName    string    `json:"localName"`
+	Name         UsageName `json:"name"`
+}
+
+// UsageName is the name of a Usage. Value is the quota family, e.g. standardDSv5Family.
+type UsageName struct {
+	LocalizedValue string `json:"localizedValue"`
+	Value          string `json:"value"`
+}
+
+// count accepts both JSON numbers and numeric strings, since the output of
+// 'az vm list-usage' has used both depending on the CLI version.
+type count int
+
+func (c *count) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		*c = count(v)
+	case string:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*c = count(n)
+	default:
+		return fmt.Errorf("unexpected usage value %s", data)
+	}
+	return nil
+}
+
+// LoadUsages reads the output of 'az vm list-usage' from a JSON file.
+func LoadUsages(path string) ([]Usage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var usages []Usage
+	if err := json.Unmarshal(data, &usages); err != nil {
+		return nil, fmt.Errorf("parsing vCPU usage file %s: %w", path, err)
+	}
+	return usages, nil
+}
+
+// Requirement is the worst-case number of vCPUs a plan needs from a quota family.
+type Requirement struct {
+	Family string
+	VCPUs  int
+	// Resources lists the planned resources that contribute to the requirement
+	Resources []string
+}
+
+// Shortfall is a quota that does not have enough vCPUs available for the plan.
+type Shortfall struct {
+	Family    string
+	Name      string
+	Required  int
+	Available int
+	Limit     int
+}
+
+// Report is the result of a vCPU quota pre-flight check.
+type Report struct {
+	Requirements []Requirement
+	TotalVCPUs   int
+	Shortfalls   []Shortfall
+}
+
+// RequiredVCPUs aggregates the worst-case vCPUs of the default node pool, every node pool at its
+// max_count and every virtual machine in the plan by quota family.
+func RequiredVCPUs(plan *terraform.PlanStruct, catalog *sku.Catalog) ([]Requirement, int, error) {
+	byFamily := make(map[string]*Requirement)
+	total := 0
+	var errs []error
+
+	add := func(address string, size string, nodes int) {
+		vm, err := catalog.VM(size)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", address, err))
+			return
+		}
+		requirement, ok := byFamily[vm.Family]
+		if !ok {
+			requirement = &Requirement{Family: vm.Family}
+			byFamily[vm.Family] = requirement
+		}
+		requirement.VCPUs += vm.VCPUs * nodes
+		requirement.Resources = append(requirement.Resources, fmt.Sprintf("%s (%d x %s)", address, nodes, size))
+		total += vm.VCPUs * nodes
+	}
+
+	for address, resource := range plan.ResourcePlannedValuesMap {
+		values := resource.AttributeValues
+		switch resource.Type {
+		case "azurerm_kubernetes_cluster":
+			blocks, _ := values["default_node_pool"].([]interface{})
+			if len(blocks) > 0 {
+				pool, _ := blocks[0].(map[string]interface{})
+				size, _ := pool["vm_size"].(string)
+				add(address+".default_node_pool", size, maxNodes(pool))
+			}
+		case "azurerm_kubernetes_cluster_node_pool":
+			size, _ := values["vm_size"].(string)
+			add(address, size, maxNodes(values))
+		case "azurerm_linux_virtual_machine":
+			size, _ := values["size"].(string)
+			add(address, size, 1)
+		}
+	}
+
+	requirements := make([]Requirement, 0, len(byFamily))
+	for _, requirement := range byFamily {
+		sort.Strings(requirement.Resources)
+		requirements = append(requirements, *requirement)
+	}
+	sort.Slice(requirements, func(i, j int) bool {
+		return requirements[i].Family < requirements[j].Family
+	})
+	return requirements, total, errors.Join(errs...)
+}
+
+// Preflight compares the worst-case vCPUs of the plan with the available quota. Families
+// without an entry in usages are reported as a shortfall with no quota available.
+func Preflight(plan *terraform.PlanStruct, catalog *sku.Catalog, usages []Usage) (*Report, error) {
+	requirements, total, err := RequiredVCPUs(plan, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]Usage)
+	for _, usage := range usages {
+		byName[usage.Name.Value] = usage
+	}
+	check := func(family string, required int) *Shortfall {
+		usage, ok := byName[family]
+		if !ok {
+			return &Shortfall{Family: family, Name: family, Required: required}
+		}
+		available := int(usage.Limit - usage.CurrentValue)
+		if required <= available {
+			return nil
+		}
+		return &Shortfall{Family: family, Name: usage.LocalName, Required: required, Available: available, Limit: int(usage.Limit)}
+	}
+
+	report := &Report{Requirements: requirements, TotalVCPUs: total}
+	for _, requirement := range requirements {
+		if shortfall := check(requirement.Family, requirement.VCPUs); shortfall != nil {
+			report.Shortfalls = append(report.Shortfalls, *shortfall)
+		}
+	}
+	if shortfall := check(TotalRegionalVCPUs, total); shortfall != nil {
+		report.Shortfalls = append(report.Shortfalls, *shortfall)
+	}
+	return report, nil
+}
+
+// Write prints the vCPU requirements by family followed by any shortfalls and the quota
+// increase needed to resolve each one.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FAMILY\tWORST-CASE vCPUs\tRESOURCES")
+	for _, requirement := range r.Requirements {
+		for i, resource := range requirement.Resources {
+			if i == 0 {
+				fmt.Fprintf(tw, "%s\t%d\t%s\n", requirement.Family, requirement.VCPUs, resource)
+			} else {
+				fmt.Fprintf(tw, "\t\t%s\n", resource)
+			}
+		}
+	}
+	fmt.Fprintf(tw, "%s\t%d\t\n", TotalRegionalVCPUs, r.TotalVCPUs)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Shortfalls) == 0 {
+		_, err := fmt.Fprintln(w, "All vCPU quotas are sufficient.")
+		return err
+	}
+	for _, shortfall := range r.Shortfalls {
+		fmt.Fprintf(w, "SHORTFALL: %s needs %d vCPUs but only %d of %d are available. "+
+			"Request a quota increase of at least %d vCPUs (new limit %d) or use a smaller or different VM size.\n",
+			shortfall.Name, shortfall.Required, shortfall.Available, shortfall.Limit,
+			shortfall.Required-shortfall.Available, shortfall.Limit+shortfall.Required-shortfall.Available)
+	}
+	return nil
+}
+
+// maxNodes returns the worst-case node count of an autoscaled or static node pool.
+func maxNodes(pool map[string]interface{}) int {
+	if maxCount, ok := pool["max_count"].(float64); ok {
+		return int(maxCount)
+	}
+	nodeCount, _ := pool["node_count"].(float64)
+	return int(nodeCount)
+}
@@ -0,0 +1,100 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"encoding/json"
+	"strings"
+	"test/sku"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlan() *terraform.PlanStruct {
+	return &terraform.PlanStruct{
+		ResourcePlannedValuesMap: map[string]*tfjson.StateResource{
+			"module.aks.azurerm_kubernetes_cluster.aks": {
+				Type: "azurerm_kubernetes_cluster",
+				AttributeValues: map[string]interface{}{
+					"default_node_pool": []interface{}{map[string]interface{}{
+						"vm_size":   "Standard_E8s_v5",
+						"min_count": float64(1),
+						"max_count": float64(5),
+					}},
+				},
+			},
+			"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": {
+				Type:            "azurerm_kubernetes_cluster_node_pool",
+				AttributeValues: map[string]interface{}{"vm_size": "Standard_E16ds_v5", "min_count": float64(0), "max_count": float64(5)},
+			},
+			"module.node_pools[\"stateful\"].azurerm_kubernetes_cluster_node_pool.static_node_pool[0]": {
+				Type:            "azurerm_kubernetes_cluster_node_pool",
+				AttributeValues: map[string]interface{}{"vm_size": "Standard_D4s_v5", "node_count": float64(2)},
+			},
+			"module.nfs[0].azurerm_linux_virtual_machine.vm": {
+				Type:            "azurerm_linux_virtual_machine",
+				AttributeValues: map[string]interface{}{"size": "Standard_D4s_v5"},
+			},
+			"module.jump[0].azurerm_linux_virtual_machine.vm": {
+				Type:            "azurerm_linux_virtual_machine",
+				AttributeValues: map[string]interface{}{"size": "Standard_B2ls_v2"},
+			},
+		},
+	}
+}
+
+// TestRequiredVCPUs verifies the worst-case vCPUs are aggregated by family.
+func TestRequiredVCPUs(t *testing.T) {
+	catalog, err := sku.LoadCatalog("../sku/vm_skus_snapshot.json")
+	require.NoError(t, err)
+
+	requirements, total, err := RequiredVCPUs(testPlan(), catalog)
+	require.NoError(t, err)
+
+	byFamily := make(map[string]int)
+	for _, requirement := range requirements {
+		byFamily[requirement.Family] = requirement.VCPUs
+	}
+	assert.Equal(t, map[string]int{
+		"standardESv5Family":  40,
+		"standardEDSv5Family": 80,
+		"standardDSv5Family":  12,
+		"standardBsv2Family":  2,
+	}, byFamily)
+	assert.Equal(t, 134, total)
+}
+
+// TestPreflight verifies shortfalls are reported for families and the regional total, and that
+// usage values are accepted as numbers or strings.
+func TestPreflight(t *testing.T) {
+	catalog, err := sku.LoadCatalog("../sku/vm_skus_snapshot.json")
+	require.NoError(t, err)
+
+	var usages []Usage
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"currentValue": 0, "limit": 100, "localName": "Standard ESv5 Family vCPUs", "name": {"localizedValue": "Standard ESv5 Family vCPUs", "value": "standardESv5Family"}},
+		{"currentValue": "40", "limit": "100", "localName": "Standard EDSv5 Family vCPUs", "name": {"localizedValue": "Standard EDSv5 Family vCPUs", "value": "standardEDSv5Family"}},
+		{"currentValue": 0, "limit": 100, "localName": "Standard DSv5 Family vCPUs", "name": {"localizedValue": "Standard DSv5 Family vCPUs", "value": "standardDSv5Family"}},
+		{"currentValue": 10, "limit": 150, "localName": "Total Regional vCPUs", "name": {"localizedValue": "Total Regional vCPUs", "value": "cores"}}
+	]`), &usages))
+
+	report, err := Preflight(testPlan(), catalog, usages)
+	require.NoError(t, err)
+
+	shortfalls := make(map[string]Shortfall)
+	for _, shortfall := range report.Shortfalls {
+		shortfalls[shortfall.Family] = shortfall
+	}
+	require.Len(t, shortfalls, 2)
+	assert.Equal(t, Shortfall{Family: "standardEDSv5Family", Name: "Standard EDSv5 Family vCPUs", Required: 80, Available: 60, Limit: 100}, shortfalls["standardEDSv5Family"])
+	assert.Equal(t, Shortfall{Family: "standardBsv2Family", Name: "standardBsv2Family", Required: 2}, shortfalls["standardBsv2Family"])
+
+	out := &strings.Builder{}
+	require.NoError(t, report.Write(out))
+	assert.Contains(t, out.String(), "Request a quota increase of at least 20 vCPUs (new limit 120)")
+}
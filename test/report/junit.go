@@ -0,0 +1,112 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite holds the tests of a package.
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+	SystemOut string          `xml:"system-out,omitempty"`
+}
+
+// JUnitTestCase is a test or subtest.
+type JUnitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitMessage `xml:"failure"`
+	Skipped   *JUnitMessage `xml:"skipped"`
+}
+
+// JUnitMessage is the failure or skip reason of a test case, with the test output as its content.
+type JUnitMessage struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// JUnit returns the report as JUnit test suites, one per package. A package that failed without a failed
+// test, e.g. because it did not build, is counted as an error with its output.
+func (r *Report) JUnit() JUnitTestSuites {
+	var suites JUnitTestSuites
+	for _, pkg := range r.sortedPackages() {
+		if pkg.noTests() {
+			continue
+		}
+		passed, failed, skipped := pkg.Counts()
+		suite := JUnitTestSuite{
+			Name:     pkg.Name,
+			Tests:    passed + failed + skipped,
+			Failures: failed,
+			Skipped:  skipped,
+			Time:     formatSeconds(pkg.Elapsed),
+		}
+		if pkg.Action != "pass" && pkg.Action != "skip" && failed == 0 {
+			suite.Errors = 1
+			suite.SystemOut = strings.Join(pkg.Output, "")
+		}
+		for _, test := range pkg.Tests {
+			testCase := JUnitTestCase{ClassName: pkg.Name, Name: test.Name, Time: formatSeconds(test.Elapsed)}
+			switch test.Action {
+			case "fail":
+				testCase.Failure = &JUnitMessage{Message: "Failed", Contents: strings.Join(test.Output, "")}
+			case "skip":
+				testCase.Skipped = &JUnitMessage{Message: skipReason(test.Output), Contents: strings.Join(test.Output, "")}
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+	return suites
+}
+
+// WriteJUnit writes the JUnit XML report.
+func (r *Report) WriteJUnit(out io.Writer) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(r.JUnit()); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
+
+// skipReason returns the message of t.Skip, the output line before the "--- SKIP" line.
+func skipReason(output []string) string {
+	for i := len(output) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(output[i])
+		if line != "" && !strings.HasPrefix(line, "--- SKIP") && !strings.HasPrefix(line, "=== ") {
+			// Drop the "file_test.go:12: " location prefix
+			if location, message, ok := strings.Cut(line, ": "); ok && strings.Contains(location, ".go:") {
+				return message
+			}
+			return line
+		}
+	}
+	return "Skipped"
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
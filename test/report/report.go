@@ -0,0 +1,271 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package report collects the events of 'go test -json' into per test results, logs and a JUnit report.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Event is a single line of 'go test -json' output, see 'go doc test2json'.
+type Event struct {
+	Action     string
+	Package    string
+	Test       string
+	Elapsed    float64
+	Output     string
+	ImportPath string
+}
+
+// TestResult is the outcome and output of a test or subtest.
+type TestResult struct {
+	Name    string
+	Action  string
+	Elapsed float64
+	Output  []string
+}
+
+// Top returns the name of the top-level test of the test or subtest.
+func (r *TestResult) Top() string {
+	return strings.SplitN(r.Name, "/", 2)[0]
+}
+
+// PackageResult is the outcome of a package with the results of its tests in the order they started.
+type PackageResult struct {
+	Name    string
+	Action  string
+	Elapsed float64
+	// Output holds the package output that does not belong to a test, such as build errors.
+	Output []string
+	Tests  []*TestResult
+	tests  map[string]*TestResult
+	// topOutput holds the output of each top-level test and its subtests in the order it was written.
+	topOutput map[string][]string
+}
+
+// Counts returns the number of passed, failed and skipped tests, including subtests.
+func (p *PackageResult) Counts() (passed int, failed int, skipped int) {
+	for _, test := range p.Tests {
+		switch test.Action {
+		case "pass":
+			passed++
+		case "fail":
+			failed++
+		case "skip":
+			skipped++
+		}
+	}
+	return passed, failed, skipped
+}
+
+// TestOutput returns the output of the top-level test and its subtests in the order it was written.
+func (p *PackageResult) TestOutput(top string) []string {
+	return p.topOutput[top]
+}
+
+// noTests reports whether the package has no test files.
+func (p *PackageResult) noTests() bool {
+	return p.Action == "skip" && len(p.Tests) == 0
+}
+
+// Report collects the results of a 'go test -json' run.
+type Report struct {
+	// Verbose shows the output of every test as it is written. Otherwise only the package results and the
+	// output of failed tests are shown.
+	Verbose  bool
+	Packages []*PackageResult
+	packages map[string]*PackageResult
+}
+
+// New returns an empty report.
+func New(verbose bool) *Report {
+	return &Report{
+		Verbose:  verbose,
+		packages: make(map[string]*PackageResult),
+	}
+}
+
+// AddLine parses a line of 'go test -json' output, adds it to the report and returns the lines to show. Lines
+// that are not JSON, such as output of the go command itself, are returned as they are.
+func (r *Report) AddLine(line string) []string {
+	var event Event
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil {
+		return []string{line + "\n"}
+	}
+	return r.Add(event)
+}
+
+// Add adds the event to the report and returns the lines to show.
+func (r *Report) Add(event Event) []string {
+	if event.Action == "build-output" || event.Action == "build-fail" {
+		// Build events name the package in ImportPath, and only build-output has output
+		pkg := r.packageResult(strings.SplitN(event.ImportPath, " ", 2)[0])
+		if event.Output == "" {
+			return nil
+		}
+		pkg.Output = append(pkg.Output, event.Output)
+		return []string{event.Output}
+	}
+
+	pkg := r.packageResult(event.Package)
+	if event.Test == "" {
+		return r.addPackageEvent(pkg, event)
+	}
+
+	test, ok := pkg.tests[event.Test]
+	if !ok {
+		test = &TestResult{Name: event.Test}
+		pkg.tests[event.Test] = test
+		pkg.Tests = append(pkg.Tests, test)
+	}
+	switch event.Action {
+	case "output":
+		test.Output = append(test.Output, event.Output)
+		pkg.topOutput[test.Top()] = append(pkg.topOutput[test.Top()], event.Output)
+		if r.Verbose {
+			return []string{event.Output}
+		}
+	case "pass", "fail", "skip":
+		test.Action = event.Action
+		test.Elapsed = event.Elapsed
+		// Without Verbose the output of a failed test is shown once the top-level test has failed
+		if event.Action == "fail" && test.Name == test.Top() && !r.Verbose {
+			return pkg.TestOutput(test.Name)
+		}
+	}
+	return nil
+}
+
+func (r *Report) addPackageEvent(pkg *PackageResult, event Event) []string {
+	switch event.Action {
+	case "output":
+		pkg.Output = append(pkg.Output, event.Output)
+		return []string{event.Output}
+	case "pass", "fail", "skip":
+		pkg.Action = event.Action
+		pkg.Elapsed = event.Elapsed
+		if event.Action != "fail" {
+			return nil
+		}
+		// A timeout or panic ends the package without ending the running tests
+		var lines []string
+		for _, test := range pkg.Tests {
+			if test.Action == "" {
+				test.Action = "fail"
+				if test.Name == test.Top() && !r.Verbose {
+					lines = append(lines, pkg.TestOutput(test.Name)...)
+				}
+			}
+		}
+		return lines
+	}
+	return nil
+}
+
+func (r *Report) packageResult(name string) *PackageResult {
+	pkg, ok := r.packages[name]
+	if !ok {
+		pkg = &PackageResult{Name: name, tests: make(map[string]*TestResult), topOutput: make(map[string][]string)}
+		r.packages[name] = pkg
+		r.Packages = append(r.Packages, pkg)
+	}
+	return pkg
+}
+
+// Failed returns the names of the failed top-level tests and of the FailedPackages.
+func (r *Report) Failed() []string {
+	var failed []string
+	for _, pkg := range r.Packages {
+		for _, test := range pkg.Tests {
+			if test.Action == "fail" && test.Name == test.Top() {
+				failed = append(failed, pkg.Name+"."+test.Name)
+			}
+		}
+	}
+	return append(failed, r.FailedPackages()...)
+}
+
+// FailedPackages returns the names of the packages that failed without a failed test, e.g. because they did
+// not build.
+func (r *Report) FailedPackages() []string {
+	var failed []string
+	for _, pkg := range r.Packages {
+		if _, failedTests, _ := pkg.Counts(); pkg.Action != "pass" && pkg.Action != "skip" && failedTests == 0 {
+			failed = append(failed, pkg.Name)
+		}
+	}
+	return failed
+}
+
+// WriteSummary writes the test counts of each package and the failed tests.
+func (r *Report) WriteSummary(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tRESULT\tPASSED\tFAILED\tSKIPPED\tTIME")
+	for _, pkg := range r.Packages {
+		if pkg.noTests() {
+			continue
+		}
+		passed, failed, skipped := pkg.Counts()
+		action := pkg.Action
+		if action == "" {
+			action = "fail"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1fs\n", pkg.Name, action, passed, failed, skipped, pkg.Elapsed)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	failed := r.Failed()
+	if len(failed) == 0 {
+		_, err := fmt.Fprintln(out, "\nAll tests passed")
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\nFailed:\n  %s\n", strings.Join(failed, "\n  "))
+	return err
+}
+
+// WriteTestLogs writes the output of each top-level test, including its subtests, to
+// <dir>/<package>/<test>.log, and the output of each package that does not belong to a test to
+// <dir>/<package>/package.log.
+func (r *Report) WriteTestLogs(dir string) error {
+	for _, pkg := range r.Packages {
+		pkgDir := filepath.Join(dir, filepath.FromSlash(pkg.Name))
+		if err := os.MkdirAll(pkgDir, 0755); err != nil {
+			return err
+		}
+		if err := writeLines(filepath.Join(pkgDir, "package.log"), pkg.Output); err != nil {
+			return err
+		}
+		for _, test := range pkg.Tests {
+			if test.Name != test.Top() {
+				continue
+			}
+			if err := writeLines(filepath.Join(pkgDir, test.Name+".log"), pkg.TestOutput(test.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeLines(path string, lines []string) error {
+	return os.WriteFile(path, []byte(strings.Join(lines, "")), 0644)
+}
+
+// sortedPackages returns the packages sorted by name.
+func (r *Report) sortedPackages() []*PackageResult {
+	packages := append([]*PackageResult{}, r.Packages...)
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages
+}
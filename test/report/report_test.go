@@ -0,0 +1,135 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOutput is 'go test -json' output of a package with a passed, a failed and a skipped test, a package
+// that timed out in a running test and a package that did not build.
+var testOutput = []string{
+	`{"Action":"start","Package":"test/defaultplan"}`,
+	`{"Action":"run","Package":"test/defaultplan","Test":"TestPlanPass"}`,
+	`{"Action":"output","Package":"test/defaultplan","Test":"TestPlanPass","Output":"=== RUN   TestPlanPass\n"}`,
+	`{"Action":"output","Package":"test/defaultplan","Test":"TestPlanPass","Output":"--- PASS: TestPlanPass (0.10s)\n"}`,
+	`{"Action":"pass","Package":"test/defaultplan","Test":"TestPlanPass","Elapsed":0.1}`,
+	`{"Action":"run","Package":"test/defaultplan","Test":"TestPlanFail"}`,
+	`{"Action":"output","Package":"test/defaultplan","Test":"TestPlanFail","Output":"=== RUN   TestPlanFail\n"}`,
+	`{"Action":"run","Package":"test/defaultplan","Test":"TestPlanFail/sub"}`,
+	`{"Action":"output","Package":"test/defaultplan","Test":"TestPlanFail/sub","Output":"    plan_test.go:12: expected 1, got 2\n"}`,
+	`{"Action":"fail","Package":"test/defaultplan","Test":"TestPlanFail/sub","Elapsed":0.2}`,
+	`{"Action":"output","Package":"test/defaultplan","Test":"TestPlanFail","Output":"--- FAIL: TestPlanFail (0.20s)\n"}`,
+	`{"Action":"fail","Package":"test/defaultplan","Test":"TestPlanFail","Elapsed":0.2}`,
+	`{"Action":"run","Package":"test/defaultplan","Test":"TestPlanSkip"}`,
+	`{"Action":"output","Package":"test/defaultplan","Test":"TestPlanSkip","Output":"    plan_test.go:20: The plan suite is not enabled\n"}`,
+	`{"Action":"output","Package":"test/defaultplan","Test":"TestPlanSkip","Output":"--- SKIP: TestPlanSkip (0.00s)\n"}`,
+	`{"Action":"skip","Package":"test/defaultplan","Test":"TestPlanSkip"}`,
+	`{"Action":"output","Package":"test/defaultplan","Output":"FAIL\n"}`,
+	`{"Action":"fail","Package":"test/defaultplan","Elapsed":0.5}`,
+	`{"Action":"run","Package":"test/defaultapply","Test":"TestApplyDefaultMain"}`,
+	`{"Action":"output","Package":"test/defaultapply","Test":"TestApplyDefaultMain","Output":"Applying\n"}`,
+	`{"Action":"output","Package":"test/defaultapply","Output":"panic: test timed out after 1s\n"}`,
+	`{"Action":"fail","Package":"test/defaultapply","Elapsed":1}`,
+	`{"ImportPath":"test/broken [test/broken.test]","Action":"build-output","Output":"broken/a_test.go:3:1: syntax error\n"}`,
+	`{"ImportPath":"test/broken [test/broken.test]","Action":"build-fail"}`,
+	`FAIL	test/broken [build failed]`,
+	`{"Action":"fail","Package":"test/broken","Elapsed":0,"FailedBuild":"test/broken [test/broken.test]"}`,
+	`{"Action":"output","Package":"test/cmd/runner","Output":"?   \ttest/cmd/runner\t[no test files]\n"}`,
+	`{"Action":"skip","Package":"test/cmd/runner","Elapsed":0}`,
+}
+
+func addAll(r *Report) string {
+	shown := &strings.Builder{}
+	for _, line := range testOutput {
+		for _, output := range r.AddLine(line) {
+			shown.WriteString(output)
+		}
+	}
+	return shown.String()
+}
+
+// TestReport verifies the results, the output shown while the tests run and the summary.
+func TestReport(t *testing.T) {
+	r := New(false)
+	assert.Equal(t, "=== RUN   TestPlanFail\n"+
+		"    plan_test.go:12: expected 1, got 2\n"+
+		"--- FAIL: TestPlanFail (0.20s)\n"+
+		"FAIL\n"+
+		"panic: test timed out after 1s\n"+
+		"Applying\n"+
+		"broken/a_test.go:3:1: syntax error\n"+
+		"FAIL\ttest/broken [build failed]\n"+
+		"?   \ttest/cmd/runner\t[no test files]\n", addAll(r))
+
+	assert.Equal(t, []string{
+		"test/defaultplan.TestPlanFail",
+		"test/defaultapply.TestApplyDefaultMain",
+		"test/broken",
+	}, r.Failed())
+	assert.Equal(t, []string{"test/broken"}, r.FailedPackages())
+
+	summary := &strings.Builder{}
+	require.NoError(t, r.WriteSummary(summary))
+	assert.Equal(t, "PACKAGE            RESULT  PASSED  FAILED  SKIPPED  TIME\n"+
+		"test/defaultplan   fail    1       2       1        0.5s\n"+
+		"test/defaultapply  fail    0       1       0        1.0s\n"+
+		"test/broken        fail    0       0       0        0.0s\n"+
+		"\nFailed:\n"+
+		"  test/defaultplan.TestPlanFail\n"+
+		"  test/defaultapply.TestApplyDefaultMain\n"+
+		"  test/broken\n", summary.String())
+
+	verbose := New(true)
+	assert.Contains(t, addAll(verbose), "=== RUN   TestPlanPass\n")
+}
+
+// TestWriteTestLogs verifies that the output of each top-level test and its subtests is written to its own log.
+func TestWriteTestLogs(t *testing.T) {
+	r := New(false)
+	addAll(r)
+	dir := t.TempDir()
+	require.NoError(t, r.WriteTestLogs(dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "test", "defaultplan", "TestPlanFail.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "=== RUN   TestPlanFail\n    plan_test.go:12: expected 1, got 2\n--- FAIL: TestPlanFail (0.20s)\n", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "test", "broken", "package.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "broken/a_test.go:3:1: syntax error\n", string(data))
+}
+
+// TestJUnit verifies the test suites, failures and skip reasons of the JUnit report.
+func TestJUnit(t *testing.T) {
+	r := New(false)
+	addAll(r)
+	suites := r.JUnit().TestSuites
+	require.Len(t, suites, 3)
+
+	assert.Equal(t, "test/broken", suites[0].Name)
+	assert.Equal(t, 1, suites[0].Errors)
+	assert.Equal(t, "broken/a_test.go:3:1: syntax error\n", suites[0].SystemOut)
+
+	plan := suites[2]
+	assert.Equal(t, "test/defaultplan", plan.Name)
+	assert.Equal(t, 4, plan.Tests)
+	assert.Equal(t, 2, plan.Failures)
+	assert.Equal(t, 1, plan.Skipped)
+	assert.Equal(t, "0.500", plan.Time)
+	assert.Nil(t, plan.TestCases[0].Failure)
+	assert.Equal(t, "TestPlanFail/sub", plan.TestCases[2].Name)
+	assert.Equal(t, "    plan_test.go:12: expected 1, got 2\n", plan.TestCases[2].Failure.Contents)
+	assert.Equal(t, "The plan suite is not enabled", plan.TestCases[3].Skipped.Message)
+
+	out := &strings.Builder{}
+	require.NoError(t, r.WriteJUnit(out))
+	assert.Contains(t, out.String(), `<testsuite name="test/defaultapply" tests="1" failures="1" errors="0" skipped="0" time="1.000">`)
+}
@@ -0,0 +1,141 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SecretHash returns the hash that a secret value is recorded and compared by, so the value itself is never
+// written or shown.
+func SecretHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+var appendLock sync.Mutex
+
+// AppendSecretHash appends the length and hash of the secret value to the secret hashes file.
+func AppendSecretHash(path string, value string) error {
+	appendLock.Lock()
+	defer appendLock.Unlock()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "%d %s\n", len(value), SecretHash(value))
+	return err
+}
+
+// SecretHashes holds the hashes of the known secret values by their length.
+type SecretHashes map[int]map[string]bool
+
+// Add adds the hash of the secret value.
+func (h SecretHashes) Add(value string) {
+	h.add(len(value), SecretHash(value))
+}
+
+func (h SecretHashes) add(length int, hash string) {
+	if h[length] == nil {
+		h[length] = make(map[string]bool)
+	}
+	h[length][hash] = true
+}
+
+// LoadSecretHashes reads a secret hashes file written by AppendSecretHash. A missing file has no hashes.
+func LoadSecretHashes(path string) (SecretHashes, error) {
+	hashes := SecretHashes{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return hashes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		lengthField, hash, ok := strings.Cut(line, " ")
+		length, err := strconv.Atoi(lengthField)
+		if !ok || err != nil || !strings.HasPrefix(hash, "sha256:") {
+			return nil, fmt.Errorf("%s:%d: invalid secret hash line", path, i+1)
+		}
+		hashes.add(length, hash)
+	}
+	return hashes, nil
+}
+
+// Leak is a line of a file that contains a known secret value.
+type Leak struct {
+	Path string
+	Line int
+}
+
+func (l Leak) String() string {
+	return fmt.Sprintf("%s:%d", l.Path, l.Line)
+}
+
+// Scan returns the lines of the files, and of the files in the directories, that contain a known secret value.
+// Every substring of each line with the length of a secret is hashed and compared. Missing paths are skipped.
+func (h SecretHashes) Scan(paths ...string) ([]Leak, error) {
+	var leaks []Leak
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			fileLeaks, err := h.scanFile(path)
+			leaks = append(leaks, fileLeaks...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return leaks, nil
+}
+
+func (h SecretHashes) scanFile(path string) ([]Leak, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var leaks []Leak
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		if h.contains(scanner.Text()) {
+			leaks = append(leaks, Leak{Path: path, Line: number})
+		}
+	}
+	return leaks, scanner.Err()
+}
+
+func (h SecretHashes) contains(line string) bool {
+	for length, hashes := range h {
+		for start := 0; start+length <= len(line); start++ {
+			if hashes[SecretHash(line[start:start+length])] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScanSecrets verifies that the lines of the logs with a recorded secret are found, without the secret
+// being written to the hashes file.
+func TestScanSecrets(t *testing.T) {
+	dir := t.TempDir()
+	hashesFile := filepath.Join(dir, "secret_hashes")
+	require.NoError(t, AppendSecretHash(hashesFile, "client-secret-value"))
+	require.NoError(t, AppendSecretHash(hashesFile, "nested-password"))
+	data, err := os.ReadFile(hashesFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "client-secret-value")
+
+	hashes, err := LoadSecretHashes(hashesFile)
+	require.NoError(t, err)
+	hashes.Add("env-secret")
+
+	logs := filepath.Join(dir, "logs")
+	require.NoError(t, os.MkdirAll(filepath.Join(logs, "test"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test_output.log"),
+		[]byte("Running terraform plan\n-var client_secret=client-secret-value\nok\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(logs, "test", "TestApply.log"),
+		[]byte("password (sensitive value)\nARM_CLIENT_SECRET=env-secret\n"), 0644))
+
+	leaks, err := hashes.Scan(filepath.Join(dir, "test_output.log"), logs, filepath.Join(dir, "missing.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, []Leak{
+		{Path: filepath.Join(dir, "test_output.log"), Line: 2},
+		{Path: filepath.Join(logs, "test", "TestApply.log"), Line: 2},
+	}, leaks)
+}
@@ -0,0 +1,293 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sku
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"test/planvalues"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// Capability names used from the 'az vm list-skus' output
+const (
+	EncryptionAtHostSupported = "EncryptionAtHostSupported"
+	UltraSSDAvailable         = "UltraSSDAvailable"
+)
+
+// ResourceSku is a single entry of the 'az vm list-skus --all -o json' output.
+type ResourceSku struct {
+	ResourceType string         `json:"resourceType"`
+	Name         string         `json:"name"`
+	Tier         string         `json:"tier"`
+	Size         string         `json:"size"`
+	Family       string         `json:"family"`
+	Locations    []string       `json:"locations"`
+	LocationInfo []LocationInfo `json:"locationInfo"`
+	Capabilities []Capability   `json:"capabilities"`
+	Restrictions []Restriction  `json:"restrictions"`
+}
+
+// LocationInfo lists the zones a ResourceSku is offered in and any capabilities that are zone specific.
+type LocationInfo struct {
+	Location    string       `json:"location"`
+	Zones       []string     `json:"zones"`
+	ZoneDetails []ZoneDetail `json:"zoneDetails"`
+}
+
+// ZoneDetail holds the capabilities that are only available in the named zones.
+type ZoneDetail struct {
+	Name         []string     `json:"name"`
+	Capabilities []Capability `json:"capabilities"`
+}
+
+// Capability is a named capability of a ResourceSku, e.g. EncryptionAtHostSupported=True.
+type Capability struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Restriction marks a ResourceSku as unavailable to the subscription in a location or in zones of a location.
+type Restriction struct {
+	Type            string          `json:"type"`
+	Values          []string        `json:"values"`
+	RestrictionInfo RestrictionInfo `json:"restrictionInfo"`
+	ReasonCode      string          `json:"reasonCode"`
+}
+
+// RestrictionInfo lists the locations and zones a Restriction applies to.
+type RestrictionInfo struct {
+	Locations []string `json:"locations"`
+	Zones     []string `json:"zones"`
+}
+
+// Snapshot is an offline copy of the 'az vm list-skus' output for the locations the tests use.
+type Snapshot []ResourceSku
+
+// LoadSnapshot reads a Snapshot from a JSON file.
+func LoadSnapshot(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing SKU snapshot %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// Has reports whether the snapshot contains the given resource type and name in any location.
+func (s Snapshot) Has(resourceType string, name string) bool {
+	for i := range s {
+		if strings.EqualFold(s[i].ResourceType, resourceType) && strings.EqualFold(s[i].Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the ResourceSku with the given resource type and name, e.g. "virtualMachines" and
+// "Standard_D4s_v5", in the location. 'az vm list-skus' lists a separate entry for each location.
+func (s Snapshot) Find(resourceType string, name string, location string) (*ResourceSku, bool) {
+	for i := range s {
+		if strings.EqualFold(s[i].ResourceType, resourceType) && strings.EqualFold(s[i].Name, name) &&
+			containsFold(s[i].Locations, location) {
+			return &s[i], true
+		}
+	}
+	return nil, false
+}
+
+// Zones returns the zones the ResourceSku is available in at the location, excluding restricted zones.
+// The second return value is false if the ResourceSku is not available in the location at all.
+func (r *ResourceSku) Zones(location string) ([]string, bool) {
+	var info *LocationInfo
+	for i := range r.LocationInfo {
+		if strings.EqualFold(r.LocationInfo[i].Location, location) {
+			info = &r.LocationInfo[i]
+		}
+	}
+	if info == nil {
+		return nil, false
+	}
+
+	restricted := make(map[string]bool)
+	for _, restriction := range r.Restrictions {
+		if !containsFold(restriction.RestrictionInfo.Locations, location) {
+			continue
+		}
+		switch restriction.Type {
+		case "Location":
+			return nil, false
+		case "Zone":
+			for _, zone := range restriction.RestrictionInfo.Zones {
+				restricted[zone] = true
+			}
+		}
+	}
+
+	var zones []string
+	for _, zone := range info.Zones {
+		if !restricted[zone] {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	return zones, true
+}
+
+// HasCapability reports whether the ResourceSku has the capability set to True.
+func (r *ResourceSku) HasCapability(name string) bool {
+	return hasCapability(r.Capabilities, name)
+}
+
+// HasZoneCapability reports whether the ResourceSku has the capability set to True in the zone of the location.
+func (r *ResourceSku) HasZoneCapability(location string, zone string, name string) bool {
+	for _, info := range r.LocationInfo {
+		if !strings.EqualFold(info.Location, location) {
+			continue
+		}
+		for _, detail := range info.ZoneDetails {
+			if containsFold(detail.Name, zone) && hasCapability(detail.Capabilities, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Finding is an unsupported size, zone or feature combination of a planned resource.
+type Finding struct {
+	Address string
+	Message string
+}
+
+func (f Finding) String() string {
+	return f.Address + ": " + f.Message
+}
+
+// CheckPlan flags planned VMs, AKS node pools and managed disks whose size, zones or features are not
+// supported in the location according to the snapshot.
+func CheckPlan(plan *terraform.PlanStruct, snapshot Snapshot) []Finding {
+	location := ""
+	if variable, ok := plan.RawPlan.Variables["location"]; ok {
+		location, _ = variable.Value.(string)
+	}
+
+	var findings []Finding
+	for address, resource := range plan.ResourcePlannedValuesMap {
+		values := resource.AttributeValues
+		switch resource.Type {
+		case "azurerm_kubernetes_cluster":
+			pool := planvalues.FirstBlock(values, "default_node_pool")
+			findings = append(findings, checkVM(snapshot, address+".default_node_pool", location,
+				planvalues.String(pool, "vm_size"), planvalues.Strings(pool, "zones"), planvalues.Bool(pool, "host_encryption_enabled"), false)...)
+		case "azurerm_kubernetes_cluster_node_pool":
+			findings = append(findings, checkVM(snapshot, address, location,
+				planvalues.String(values, "vm_size"), planvalues.Strings(values, "zones"), planvalues.Bool(values, "host_encryption_enabled"), false)...)
+		case "azurerm_linux_virtual_machine":
+			var zones []string
+			if zone := planvalues.String(values, "zone"); zone != "" {
+				zones = []string{zone}
+			}
+			ultraSSD := planvalues.Bool(planvalues.FirstBlock(values, "additional_capabilities"), "ultra_ssd_enabled")
+			findings = append(findings, checkVM(snapshot, address, location,
+				planvalues.String(values, "size"), zones, planvalues.Bool(values, "encryption_at_host_enabled"), ultraSSD)...)
+		case "azurerm_managed_disk":
+			var zones []string
+			if zone := planvalues.String(values, "zone"); zone != "" {
+				zones = []string{zone}
+			}
+			findings = append(findings, checkDisk(snapshot, address, location, planvalues.String(values, "storage_account_type"), zones)...)
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].String() < findings[j].String()
+	})
+	return findings
+}
+
+func checkVM(snapshot Snapshot, address string, location string, size string, zones []string, hostEncryption bool, ultraSSD bool) []Finding {
+	if !snapshot.Has("virtualMachines", size) {
+		return []Finding{{address, fmt.Sprintf("VM size %s is not in the SKU snapshot", size)}}
+	}
+	vm, ok := snapshot.Find("virtualMachines", size, location)
+	var available []string
+	if ok {
+		available, ok = vm.Zones(location)
+	}
+	if !ok {
+		return []Finding{{address, fmt.Sprintf("VM size %s is not available in %s", size, location)}}
+	}
+
+	var findings []Finding
+	for _, zone := range zones {
+		if !containsFold(available, zone) {
+			findings = append(findings, Finding{address, fmt.Sprintf("VM size %s is not available in zone %s of %s (available zones: %v)", size, zone, location, available)})
+		}
+	}
+	if hostEncryption && !vm.HasCapability(EncryptionAtHostSupported) {
+		findings = append(findings, Finding{address, fmt.Sprintf("VM size %s does not support encryption at host", size)})
+	}
+	if ultraSSD {
+		if len(zones) == 0 {
+			findings = append(findings, Finding{address, fmt.Sprintf("Ultra SSD requires a zone for VM size %s in %s", size, location)})
+		}
+		for _, zone := range zones {
+			if !vm.HasZoneCapability(location, zone, UltraSSDAvailable) {
+				findings = append(findings, Finding{address, fmt.Sprintf("VM size %s does not support Ultra SSD in zone %s of %s", size, zone, location)})
+			}
+		}
+	}
+	return findings
+}
+
+func checkDisk(snapshot Snapshot, address string, location string, diskType string, zones []string) []Finding {
+	if !snapshot.Has("disks", diskType) {
+		return []Finding{{address, fmt.Sprintf("Disk type %s is not in the SKU snapshot", diskType)}}
+	}
+	disk, ok := snapshot.Find("disks", diskType, location)
+	var available []string
+	if ok {
+		available, ok = disk.Zones(location)
+	}
+	if !ok {
+		return []Finding{{address, fmt.Sprintf("Disk type %s is not available in %s", diskType, location)}}
+	}
+
+	var findings []Finding
+	for _, zone := range zones {
+		if !containsFold(available, zone) {
+			findings = append(findings, Finding{address, fmt.Sprintf("Disk type %s is not available in zone %s of %s (available zones: %v)", diskType, zone, location, available)})
+		}
+	}
+	if strings.EqualFold(diskType, "UltraSSD_LRS") && len(zones) == 0 && len(available) > 0 {
+		findings = append(findings, Finding{address, fmt.Sprintf("Disk type %s requires a zone in %s", diskType, location)})
+	}
+	return findings
+}
+
+func hasCapability(capabilities []Capability, name string) bool {
+	for _, capability := range capabilities {
+		if capability.Name == name {
+			return strings.EqualFold(capability.Value, "True")
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
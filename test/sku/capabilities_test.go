@@ -0,0 +1,124 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sku
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planInLocation(location string, resources map[string]*tfjson.StateResource) *terraform.PlanStruct {
+	return &terraform.PlanStruct{
+		RawPlan: tfjson.Plan{
+			Variables: map[string]*tfjson.PlanVariable{"location": {Value: location}},
+		},
+		ResourcePlannedValuesMap: resources,
+	}
+}
+
+// TestCheckPlan verifies unsupported size, zone and feature combinations are flagged per resource.
+func TestCheckPlan(t *testing.T) {
+	snapshot, err := LoadSnapshot("vm_skus_snapshot.json")
+	require.NoError(t, err)
+
+	resources := map[string]*tfjson.StateResource{
+		"module.aks.azurerm_kubernetes_cluster.aks": {
+			Type: "azurerm_kubernetes_cluster",
+			AttributeValues: map[string]interface{}{
+				"default_node_pool": []interface{}{map[string]interface{}{
+					"vm_size":                 "Standard_E8s_v5",
+					"zones":                   []interface{}{"1", "4"},
+					"host_encryption_enabled": true,
+				}},
+			},
+		},
+		"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]": {
+			Type:            "azurerm_kubernetes_cluster_node_pool",
+			AttributeValues: map[string]interface{}{"vm_size": "Standard_Missing_v9"},
+		},
+		"module.jump[0].azurerm_linux_virtual_machine.vm": {
+			Type:            "azurerm_linux_virtual_machine",
+			AttributeValues: map[string]interface{}{"size": "Standard_D4_v2", "encryption_at_host_enabled": true},
+		},
+		"module.nfs[0].azurerm_linux_virtual_machine.vm": {
+			Type: "azurerm_linux_virtual_machine",
+			AttributeValues: map[string]interface{}{
+				"size":                    "Standard_D4s_v5",
+				"additional_capabilities": []interface{}{map[string]interface{}{"ultra_ssd_enabled": true}},
+			},
+		},
+		"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]": {
+			Type:            "azurerm_managed_disk",
+			AttributeValues: map[string]interface{}{"storage_account_type": "UltraSSD_LRS"},
+		},
+	}
+
+	var messages []string
+	for _, finding := range CheckPlan(planInLocation("eastus", resources), snapshot) {
+		messages = append(messages, finding.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"module.aks.azurerm_kubernetes_cluster.aks.default_node_pool: VM size Standard_E8s_v5 is not available in zone 4 of eastus (available zones: [1 2 3])",
+		"module.node_pools[\"cas\"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]: VM size Standard_Missing_v9 is not in the SKU snapshot",
+		"module.jump[0].azurerm_linux_virtual_machine.vm: VM size Standard_D4_v2 does not support encryption at host",
+		"module.nfs[0].azurerm_linux_virtual_machine.vm: Ultra SSD requires a zone for VM size Standard_D4s_v5 in eastus",
+		"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]: Disk type UltraSSD_LRS requires a zone in eastus",
+	}, messages)
+}
+
+// TestCheckPlanNoZones verifies that zones and zone only disk types are flagged in a location without availability zones.
+func TestCheckPlanNoZones(t *testing.T) {
+	snapshot, err := LoadSnapshot("vm_skus_snapshot.json")
+	require.NoError(t, err)
+
+	resources := map[string]*tfjson.StateResource{
+		"module.nfs[0].azurerm_linux_virtual_machine.vm": {
+			Type:            "azurerm_linux_virtual_machine",
+			AttributeValues: map[string]interface{}{"size": "Standard_D4s_v5", "zone": "1"},
+		},
+		"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]": {
+			Type:            "azurerm_managed_disk",
+			AttributeValues: map[string]interface{}{"storage_account_type": "Premium_ZRS"},
+		},
+		"module.jump[0].azurerm_linux_virtual_machine.vm": {
+			Type:            "azurerm_linux_virtual_machine",
+			AttributeValues: map[string]interface{}{"size": "Standard_B2ls_v2"},
+		},
+	}
+
+	var messages []string
+	for _, finding := range CheckPlan(planInLocation("westus", resources), snapshot) {
+		messages = append(messages, finding.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"module.nfs[0].azurerm_linux_virtual_machine.vm: VM size Standard_D4s_v5 is not available in zone 1 of westus (available zones: [])",
+		"module.nfs[0].azurerm_managed_disk.vm_data_disk[0]: Disk type Premium_ZRS is not available in westus",
+	}, messages)
+}
+
+// TestZonesRestrictions verifies that location and zone restrictions reduce the available zones.
+func TestZonesRestrictions(t *testing.T) {
+	resourceSku := &ResourceSku{
+		LocationInfo: []LocationInfo{{Location: "eastus", Zones: []string{"3", "1", "2"}}},
+		Restrictions: []Restriction{{
+			Type:            "Zone",
+			RestrictionInfo: RestrictionInfo{Locations: []string{"eastus"}, Zones: []string{"2"}},
+			ReasonCode:      "NotAvailableForSubscription",
+		}},
+	}
+	zones, ok := resourceSku.Zones("eastus")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1", "3"}, zones)
+
+	resourceSku.Restrictions = append(resourceSku.Restrictions, Restriction{
+		Type:            "Location",
+		RestrictionInfo: RestrictionInfo{Locations: []string{"eastus"}},
+	})
+	_, ok = resourceSku.Zones("eastus")
+	assert.False(t, ok)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sku provides a local catalog of the Azure VM SKUs used by this project.
+package sku
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Capability names of the VM sizes used by the Catalog
+const (
+	VCPUsCapability    = "vCPUs"
+	MemoryGBCapability = "MemoryGB"
+)
+
+// VMSku describes a single Azure VM size.
+type VMSku struct {
+	VCPUs    int
+	MemoryGB float64
+	// Family is the quota family name as reported by 'az vm list-usage', e.g. standardDSv5Family
+	Family string
+}
+
+// Catalog maps VM sizes, e.g. Standard_D4s_v5, to their VMSku.
+type Catalog struct {
+	VirtualMachines map[string]VMSku
+}
+
+// LoadCatalog reads a Catalog from a Snapshot JSON file, so the vCPUs and the zones of a VM size come from the
+// same 'az vm list-skus' output.
+func LoadCatalog(path string) (*Catalog, error) {
+	snapshot, err := LoadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Catalog()
+}
+
+// Catalog returns the VM sizes of the snapshot with their vCPUs, memory and quota family. It fails if the
+// entries of a VM size in different locations disagree.
+func (s Snapshot) Catalog() (*Catalog, error) {
+	catalog := &Catalog{VirtualMachines: make(map[string]VMSku)}
+	for i := range s {
+		if !strings.EqualFold(s[i].ResourceType, "virtualMachines") {
+			continue
+		}
+		vm, err := s[i].vmSku()
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := catalog.VirtualMachines[s[i].Name]; ok && existing != vm {
+			return nil, fmt.Errorf("the SKU snapshot has different capabilities for VM size %q in %s", s[i].Name,
+				strings.Join(s[i].Locations, ", "))
+		}
+		catalog.VirtualMachines[s[i].Name] = vm
+	}
+	return catalog, nil
+}
+
+// vmSku returns the VMSku of a virtualMachines ResourceSku.
+func (r *ResourceSku) vmSku() (VMSku, error) {
+	vm := VMSku{Family: r.Family}
+	for _, capability := range r.Capabilities {
+		var err error
+		switch capability.Name {
+		case VCPUsCapability:
+			vm.VCPUs, err = strconv.Atoi(capability.Value)
+		case MemoryGBCapability:
+			vm.MemoryGB, err = strconv.ParseFloat(capability.Value, 64)
+		}
+		if err != nil {
+			return VMSku{}, fmt.Errorf("parsing capability %s of VM size %q: %w", capability.Name, r.Name, err)
+		}
+	}
+	if vm.VCPUs == 0 {
+		return VMSku{}, fmt.Errorf("VM size %q has no %s capability in the SKU snapshot", r.Name, VCPUsCapability)
+	}
+	return vm, nil
+}
+
+// VM returns the VMSku for the given size.
+func (c *Catalog) VM(size string) (VMSku, error) {
+	vm, ok := c.VirtualMachines[size]
+	if !ok {
+		return VMSku{}, fmt.Errorf("VM size %q is not in the SKU catalog", size)
+	}
+	return vm, nil
+}
@@ -0,0 +1,32 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package sku
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadCatalog verifies that the catalog is read from the VM sizes of the snapshot, and that the entries of
+// a VM size in different locations must agree.
+func TestLoadCatalog(t *testing.T) {
+	catalog, err := LoadCatalog("vm_skus_snapshot.json")
+	require.NoError(t, err)
+	vm, err := catalog.VM("Standard_E16ds_v5")
+	require.NoError(t, err)
+	assert.Equal(t, VMSku{VCPUs: 16, MemoryGB: 128, Family: "standardEDSv5Family"}, vm)
+	_, err = catalog.VM("Standard_D64s_v5")
+	assert.Error(t, err)
+
+	snapshot := Snapshot{
+		{ResourceType: "virtualMachines", Name: "Standard_D4s_v5", Family: "standardDSv5Family", Locations: []string{"eastus"},
+			Capabilities: []Capability{{Name: VCPUsCapability, Value: "4"}}},
+		{ResourceType: "virtualMachines", Name: "Standard_D4s_v5", Family: "standardDSv5Family", Locations: []string{"westus"},
+			Capabilities: []Capability{{Name: VCPUsCapability, Value: "8"}}},
+	}
+	_, err = snapshot.Catalog()
+	assert.EqualError(t, err, `the SKU snapshot has different capabilities for VM size "Standard_D4s_v5" in westus`)
+}
@@ -0,0 +1,442 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tablelint is a go/analysis pass that checks the helpers.TestCase, helpers.TupleTestCase and
+// helpers.ApplyTestCase literals of the tests. It reports:
+//
+//   - table keys that are used in the package for test cases checking different things
+//   - ResourceMapName addresses that match no resource, data or module block of the terraform sources
+//   - Messages that contradict the Expected value or the assertion
+//   - Expected and Actual values of an ApplyTestCase passed in swapped order
+//
+// Run it from the test directory with:
+//
+//	go build -o /tmp/tablelint ./cmd/tablelint && go vet -vettool=/tmp/tablelint ./...
+package tablelint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"test/tfconfig"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const helpersPath = "test/helpers"
+
+var Analyzer = &analysis.Analyzer{
+	Name: "tablelint",
+	Doc:  "check the helpers.TestCase, helpers.TupleTestCase and helpers.ApplyTestCase tables of the tests",
+	Run:  run,
+}
+
+var tfRoot string
+
+func init() {
+	Analyzer.Flags.StringVar(&tfRoot, "tfroot", "",
+		"terraform root module that ResourceMapName addresses are resolved in, defaults to the nearest parent directory with a versions.tf")
+}
+
+// resourceRetrievers are the retrievers whose ResourceMapName is a resource address. The other retrievers,
+// including RetrieveFromRawPlanResource, take a variable or output name.
+var resourceRetrievers = []string{"", "RetrieveFromResourcePlannedValuesMap", "RetrieveFromResourceChanges", "RetrieveResourceAction"}
+
+var (
+	// valueClaim matches a Message stating the value a test case expects, e.g. "should be Standard_B2ls_v2"
+	valueClaim = regexp.MustCompile(`(?i)\bshould (?:be|equal)(?: set to)? ([^\s,;]+)`)
+	// presentClaim and absentClaim match a Message stating that a resource should or should not be planned
+	presentClaim = regexp.MustCompile(`(?i)\bshould (?:exist|be created)\b`)
+	absentClaim  = regexp.MustCompile(`(?i)\bshould not (?:exist|be created|be present)\b|\bwhen it should not be\b`)
+)
+
+// keyUse is the first test case of a package that a table key is used for.
+type keyUse struct {
+	pos   token.Pos
+	check string
+}
+
+// checker holds the state of a pass over a package.
+type checker struct {
+	pass   *analysis.Pass
+	config *tfconfig.Config
+	// values maps the variables declared with a single value to it, to resolve addresses and formats
+	values map[types.Object]ast.Expr
+	keys   map[string]keyUse
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if len(pass.Files) == 0 {
+		return nil, nil
+	}
+	c := &checker{
+		pass:   pass,
+		config: loadConfig(pass.Fset.Position(pass.Files[0].Pos()).Filename),
+		values: make(map[types.Object]ast.Expr),
+		keys:   make(map[string]keyUse),
+	}
+	for _, file := range pass.Files {
+		c.collectValues(file)
+	}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch typed := node.(type) {
+			case *ast.CompositeLit:
+				c.checkLiteral(typed)
+			case *ast.CallExpr:
+				c.checkTupleCall(typed)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+var configs = struct {
+	sync.Mutex
+	byRoot map[string]*tfconfig.Config
+}{byRoot: make(map[string]*tfconfig.Config)}
+
+// loadConfig returns the terraform configuration of the -tfroot flag, or of the nearest parent directory of the
+// file with a versions.tf. It returns nil if there is none or it cannot be loaded, and addresses are not checked.
+func loadConfig(file string) *tfconfig.Config {
+	root := tfRoot
+	for dir := filepath.Dir(file); root == ""; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "versions.tf")); err == nil {
+			root = dir
+		} else if filepath.Dir(dir) == dir {
+			return nil
+		}
+	}
+	configs.Lock()
+	defer configs.Unlock()
+	if config, ok := configs.byRoot[root]; ok {
+		return config
+	}
+	config, err := tfconfig.LoadConfig(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tablelint: not checking addresses, loading the terraform sources failed: %s\n", err)
+	}
+	configs.byRoot[root] = config
+	return config
+}
+
+func (c *checker) collectValues(file *ast.File) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch typed := node.(type) {
+		case *ast.AssignStmt:
+			if typed.Tok == token.DEFINE && len(typed.Lhs) == len(typed.Rhs) {
+				for i, lhs := range typed.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && c.pass.TypesInfo.Defs[ident] != nil {
+						c.values[c.pass.TypesInfo.Defs[ident]] = typed.Rhs[i]
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(typed.Names) == len(typed.Values) {
+				for i, name := range typed.Names {
+					if object := c.pass.TypesInfo.Defs[name]; object != nil {
+						c.values[object] = typed.Values[i]
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+func (c *checker) checkLiteral(literal *ast.CompositeLit) {
+	literalType := c.pass.TypesInfo.TypeOf(literal)
+	if mapType, ok := literalType.Underlying().(*types.Map); ok {
+		switch helpersType(mapType.Elem()) {
+		case "TestCase", "AttrTuple":
+			c.checkKeys(literal)
+		}
+		return
+	}
+	switch helpersType(literalType) {
+	case "TestCase":
+		c.checkTestCase(literal)
+	case "ApplyTestCase":
+		c.checkApplyTestCase(literal)
+	}
+}
+
+// checkKeys reports the keys of a table that are used in the package for test cases checking something else,
+// since the results of the two are reported under the same name.
+func (c *checker) checkKeys(table *ast.CompositeLit) {
+	for _, element := range table.Elts {
+		keyValue, ok := element.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := c.stringValue(keyValue.Key)
+		value, isLiteral := keyValue.Value.(*ast.CompositeLit)
+		if !ok || !isLiteral {
+			continue
+		}
+		var check string
+		if helpersType(c.pass.TypesInfo.TypeOf(value)) == "AttrTuple" {
+			check = c.text(c.field(value, "JsonPath", 1))
+		} else {
+			check = strings.TrimSpace(fmt.Sprintf("%s %s %s", c.retriever(value),
+				c.text(c.field(value, "ResourceMapName", -1)), c.text(c.field(value, "AttributeJsonPath", -1))))
+		}
+		previous, found := c.keys[key]
+		if !found {
+			c.keys[key] = keyUse{pos: keyValue.Key.Pos(), check: check}
+		} else if previous.check != check {
+			position := c.pass.Fset.Position(previous.pos)
+			c.pass.Reportf(keyValue.Key.Pos(), "test case %q is also used at %s:%d to check %s, rename one of them",
+				key, filepath.Base(position.Filename), position.Line, previous.check)
+		}
+	}
+}
+
+func (c *checker) checkTestCase(literal *ast.CompositeLit) {
+	if address := c.field(literal, "ResourceMapName", -1); address != nil && containsString(resourceRetrievers, c.retriever(literal)) {
+		c.checkAddress(address, "")
+	}
+	absent := c.isTrue(c.field(literal, "ExpectAbsent", -1))
+	c.checkMessage(literal, absent)
+}
+
+func (c *checker) checkApplyTestCase(literal *ast.CompositeLit) {
+	c.checkMessage(literal, false)
+
+	expected, actual := c.field(literal, "Expected", -1), c.field(literal, "Actual", -1)
+	if expected != nil && actual != nil && c.isConstant(actual) && !c.isConstant(expected) && !c.isNil(expected) {
+		c.pass.Reportf(actual.Pos(), "Expected and Actual are swapped: Actual is the constant %s and Expected is computed", c.text(actual))
+	}
+	expectedRetriever, actualRetriever := c.field(literal, "ExpectedRetriever", -1), c.field(literal, "ActualRetriever", -1)
+	if expectedRetriever != nil && actualRetriever != nil && c.isHelperCall(actualRetriever, "RetrieveFromPlan") &&
+		!c.isHelperCall(expectedRetriever, "RetrieveFromPlan") {
+		c.pass.Reportf(actualRetriever.Pos(), "ExpectedRetriever and ActualRetriever are swapped: ActualRetriever reads the plan")
+	}
+}
+
+// checkMessage reports a Message that states a value other than Expected, or the opposite of the assertion.
+func (c *checker) checkMessage(literal *ast.CompositeLit, absent bool) {
+	messageExpr := c.field(literal, "Message", -1)
+	message, ok := c.stringValue(messageExpr)
+	if !ok {
+		return
+	}
+	assertion := "Equal"
+	if assertFunction, ok := c.field(literal, "AssertFunction", -1).(*ast.SelectorExpr); ok {
+		assertion = assertFunction.Sel.Name
+	}
+	expectedExpr := c.field(literal, "Expected", -1)
+	expected, isString := c.stringValue(expectedExpr)
+	nilExpected := c.isNil(expectedExpr) || (isString && (expected == "nil" || expected == "<nil>" || expected == "null"))
+	absent = absent || (assertion == "Equal" && nilExpected)
+	present := assertion == "NotEqual" && nilExpected
+
+	if match := valueClaim.FindStringSubmatch(message); match != nil && isValueLike(match[1]) {
+		value := strings.Trim(strings.TrimRight(match[1], "."), `'"`)
+		switch {
+		case assertion == "NotEqual" && nilExpected:
+			c.pass.Reportf(messageExpr.Pos(), "Message says the value should be %s, but the test case only checks that it is not %s",
+				value, c.text(expectedExpr))
+		case assertion == "Equal" && isString && !strings.Contains(expected, value):
+			c.pass.Reportf(messageExpr.Pos(), "Message says the value should be %s, but Expected is %q", value, expected)
+		}
+	}
+	if presentClaim.MatchString(message) && !strings.Contains(strings.ToLower(message), "should not") && absent {
+		c.pass.Reportf(messageExpr.Pos(), "Message says the resource should exist, but the test case checks that it does not")
+	}
+	if absentClaim.MatchString(message) && present {
+		c.pass.Reportf(messageExpr.Pos(), "Message says the resource should not exist, but the test case checks that it does")
+	}
+}
+
+// checkTupleCall checks the resource addresses that helpers.RunTupleTests and helpers.RunDefaultPlanTupleTests
+// format from the keys of their table.
+func (c *checker) checkTupleCall(call *ast.CallExpr) {
+	if !c.isHelperFunc(call.Fun, "RunTupleTests", "RunDefaultPlanTupleTests") || len(call.Args) < 3 {
+		return
+	}
+	format, ok := c.stringValue(call.Args[1])
+	table := c.compositeValue(call.Args[2])
+	if !ok || table == nil {
+		return
+	}
+	for _, element := range table.Elts {
+		if keyValue, ok := element.(*ast.KeyValueExpr); ok {
+			c.checkAddress(keyValue.Key, format)
+		}
+	}
+}
+
+// checkAddress reports an address, or the address formatted with a table key, that does not match the
+// terraform sources of the root module or of a module plan.
+func (c *checker) checkAddress(expr ast.Expr, format string) {
+	if c.config == nil {
+		return
+	}
+	address, ok := c.stringValue(expr)
+	if !ok {
+		return
+	}
+	if format != "" {
+		address = fmt.Sprintf(format, address)
+	}
+	// The addresses of the module plans of helpers.ModulePlan are in a module of the modules directory
+	if err := c.config.Resolve(address); err != nil && c.config.ResolveModulePlan(address) != nil {
+		c.pass.Reportf(expr.Pos(), "ResourceMapName %s does not match the terraform sources: %s", address, err)
+	}
+}
+
+// field returns the value of a field of a struct literal, given by name or by its index in a literal without
+// field names. Pass an index of -1 for fields that are always named.
+func (c *checker) field(literal *ast.CompositeLit, name string, index int) ast.Expr {
+	for i, element := range literal.Elts {
+		keyValue, ok := element.(*ast.KeyValueExpr)
+		if !ok {
+			if i == index {
+				return element
+			}
+			continue
+		}
+		if ident, ok := keyValue.Key.(*ast.Ident); ok && ident.Name == name {
+			return keyValue.Value
+		}
+	}
+	return nil
+}
+
+// retriever returns the name of the helpers retriever of a TestCase literal, or of the one a HashedRetriever
+// wraps. It is empty for the default retriever.
+func (c *checker) retriever(literal *ast.CompositeLit) string {
+	expr := c.field(literal, "Retriever", -1)
+	if call, ok := expr.(*ast.CallExpr); ok && c.isHelperFunc(call.Fun, "HashedRetriever") && len(call.Args) == 1 {
+		expr = call.Args[0]
+	}
+	switch typed := expr.(type) {
+	case nil:
+		return ""
+	case *ast.Ident:
+		if typed.Name == "nil" {
+			return ""
+		}
+		return typed.Name
+	case *ast.SelectorExpr:
+		return typed.Sel.Name
+	}
+	return c.text(expr)
+}
+
+// stringValue returns the value of a string constant, or of a variable declared with one, or their concatenation.
+func (c *checker) stringValue(expr ast.Expr) (string, bool) {
+	if expr == nil {
+		return "", false
+	}
+	if value := c.pass.TypesInfo.Types[expr].Value; value != nil && value.Kind() == constant.String {
+		return constant.StringVal(value), true
+	}
+	switch typed := expr.(type) {
+	case *ast.Ident:
+		if value, ok := c.values[c.pass.TypesInfo.Uses[typed]]; ok {
+			return c.stringValue(value)
+		}
+	case *ast.BinaryExpr:
+		if typed.Op == token.ADD {
+			left, leftOk := c.stringValue(typed.X)
+			right, rightOk := c.stringValue(typed.Y)
+			return left + right, leftOk && rightOk
+		}
+	}
+	return "", false
+}
+
+// compositeValue returns a composite literal, or the one a variable is declared with.
+func (c *checker) compositeValue(expr ast.Expr) *ast.CompositeLit {
+	switch typed := expr.(type) {
+	case *ast.CompositeLit:
+		return typed
+	case *ast.Ident:
+		if value, ok := c.values[c.pass.TypesInfo.Uses[typed]].(*ast.CompositeLit); ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// text returns the value of a string expression, or its source.
+func (c *checker) text(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	if value, ok := c.stringValue(expr); ok {
+		return value
+	}
+	return types.ExprString(expr)
+}
+
+func (c *checker) isConstant(expr ast.Expr) bool {
+	return c.pass.TypesInfo.Types[expr].Value != nil
+}
+
+func (c *checker) isNil(expr ast.Expr) bool {
+	return expr != nil && c.pass.TypesInfo.Types[expr].IsNil()
+}
+
+func (c *checker) isTrue(expr ast.Expr) bool {
+	if expr == nil {
+		return false
+	}
+	value := c.pass.TypesInfo.Types[expr].Value
+	return value != nil && value.Kind() == constant.Bool && constant.BoolVal(value)
+}
+
+// isHelperCall returns whether the expression is a call of one of the functions of the helpers package.
+func (c *checker) isHelperCall(expr ast.Expr, names ...string) bool {
+	call, ok := expr.(*ast.CallExpr)
+	return ok && c.isHelperFunc(call.Fun, names...)
+}
+
+// isHelperFunc returns whether the expression is one of the functions of the helpers package.
+func (c *checker) isHelperFunc(expr ast.Expr, names ...string) bool {
+	var ident *ast.Ident
+	switch typed := expr.(type) {
+	case *ast.Ident:
+		ident = typed
+	case *ast.SelectorExpr:
+		ident = typed.Sel
+	default:
+		return false
+	}
+	function, ok := c.pass.TypesInfo.Uses[ident].(*types.Func)
+	return ok && function.Pkg() != nil && function.Pkg().Path() == helpersPath && containsString(names, function.Name())
+}
+
+// helpersType returns the name of a type of the helpers package, or an empty string for other types.
+func helpersType(t types.Type) string {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != helpersPath {
+		return ""
+	}
+	return named.Obj().Name()
+}
+
+// isValueLike returns whether a word of a Message is a concrete value, such as Standard_B2ls_v2 or /viya-share,
+// rather than a description, such as disabled.
+func isValueLike(word string) bool {
+	return strings.ContainsAny(strings.TrimRight(word, "."), "0123456789_/:")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
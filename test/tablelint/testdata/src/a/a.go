@@ -0,0 +1,137 @@
+package a
+
+import (
+	"test/helpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func planTests(t *testing.T) {
+	vmName := "module.jump[0].azurerm_linux_virtual_machine.vm"
+	tests := map[string]helpers.TestCase{
+		"vmSize": {
+			Expected:          "Standard_B2ls_v2",
+			ResourceMapName:   vmName,
+			AttributeJsonPath: "{$.size}",
+			Message:           "The VM size should be Standard_B2ls_v2",
+		},
+		"vmSshKey": {
+			Expected:          "<nil>",
+			ResourceMapName:   vmName,
+			AttributeJsonPath: "{$.admin_ssh_key[0].public_key}",
+			AssertFunction:    assert.NotEqual,
+			Message:           "The VM machine type should be Standard_B2ls_v2", // want `Message says the value should be Standard_B2ls_v2, but the test case only checks that it is not <nil>`
+		},
+		"vmAdmin": {
+			Expected:          "azureuser",
+			ResourceMapName:   vmName,
+			AttributeJsonPath: "{$.admin_username}",
+			Message:           "The VM admin should be set to /home/jumpuser", // want `Message says the value should be /home/jumpuser, but Expected is "azureuser"`
+		},
+		"vmExists": {
+			Expected:        "nil",
+			ResourceMapName: vmName,
+			Message:         "The VM should exist", // want `Message says the resource should exist, but the test case checks that it does not`
+		},
+		"ipAbsent": {
+			Expected:        "<nil>",
+			ResourceMapName: "azurerm_resource_group.rg",
+			AssertFunction:  assert.NotEqual,
+			Message:         "The resource group should not be created", // want `Message says the resource should not exist, but the test case checks that it does`
+		},
+		"disabled": {
+			Expected:          "false",
+			ResourceMapName:   "azurerm_resource_group.rg",
+			AttributeJsonPath: "{$.managed}",
+			Message:           "The resource group should be disabled by default",
+		},
+		"missingResource": {
+			Expected:        "nil",
+			ResourceMapName: "azurerm_resource_group.aks_rg[0]", // want `ResourceMapName azurerm_resource_group.aks_rg\[0\] does not match the terraform sources: the root module has no resource azurerm_resource_group.aks_rg, available: azurerm_resource_group.rg`
+		},
+		"missingModule": {
+			Expected:        "nil",
+			ResourceMapName: "module.nfs[0].azurerm_linux_virtual_machine.vm", // want `the root module has no module nfs, available: jump, registry`
+		},
+		"missingModuleResource": {
+			Expected:        "nil",
+			ResourceMapName: "module.jump[0].azurerm_public_ip.vm_ip[0]", // want `module jump has no resource azurerm_public_ip.vm_ip, available: azurerm_linux_virtual_machine.vm, azurerm_subnet.subnet`
+		},
+		"dataSource": {
+			Expected:        "nil",
+			ResourceMapName: "data.azurerm_subnet.aks",
+		},
+		"modulePlan": {
+			Expected:        "nil",
+			ResourceMapName: "module.vm.azurerm_linux_virtual_machine.vm",
+		},
+		"registryModule": {
+			Expected:        "nil",
+			ResourceMapName: "module.registry.azurerm_anything.this",
+		},
+		"variable": {
+			Expected:        "eastus",
+			Retriever:       helpers.RetrieveFromRawPlan,
+			ResourceMapName: "location",
+		},
+		"hashed": {
+			Expected:        "sha256:0",
+			Retriever:       helpers.HashedRetriever(nil),
+			ResourceMapName: "azurerm_container_registry.acr[0]", // want `has no resource azurerm_container_registry.acr`
+		},
+	}
+	helpers.RunDefaultPlanTests(t, tests)
+
+	others := map[string]helpers.TestCase{
+		"vmSize": {
+			Expected:          "Standard_B2ls_v2",
+			ResourceMapName:   vmName,
+			AttributeJsonPath: "{$.size}",
+		},
+		"vmAdmin": { // want `test case "vmAdmin" is also used at a.go:26 to check module.jump\[0\].azurerm_linux_virtual_machine.vm \{\$.admin_username\}, rename one of them`
+			Expected:          "azureuser",
+			ResourceMapName:   vmName,
+			AttributeJsonPath: "{$.admin_password}",
+		},
+	}
+	helpers.RunDefaultPlanTests(t, others)
+
+	tuples := map[string]helpers.TupleTestCase{
+		"aks": {
+			Expected: map[string]helpers.AttrTuple{
+				"prefixes": {`["192.168.0.0/23"]`, "{$.address_prefixes}"},
+			},
+		},
+		"netapp": {
+			Expected: map[string]helpers.AttrTuple{
+				"prefixes": {`["192.168.3.0/24"]`, "{$.address_prefix}"}, // want `test case "prefixes" is also used at a.go:103 to check \{\$.address_prefixes\}, rename one of them`
+			},
+		},
+	}
+	helpers.RunDefaultPlanTupleTests(t, "module.jump[0].azurerm_subnet.subnet[\"%s\"]", tuples)
+	helpers.RunDefaultPlanTupleTests(t, "module.jump[0].azurerm_subnet.subnets[\"%s\"]", map[string]helpers.TupleTestCase{"netapp": {}}) // want `module jump has no resource azurerm_subnet.subnets`
+}
+
+func applyTests(t *testing.T, vms []string, location string) {
+	helpers.RunApplyTests(t, map[string]helpers.ApplyTestCase{
+		"vmsLengthTest": {
+			Expected: len(vms),
+			Actual:   2, // want `Expected and Actual are swapped: Actual is the constant 2 and Expected is computed`
+		},
+		"vmsCountTest": {
+			Expected: 2,
+			Actual:   len(vms),
+		},
+		"vmLocationTest": {
+			ExpectedRetriever: helpers.RetrieveFromStruct(location),
+			ActualRetriever:   helpers.RetrieveFromPlan(nil, "azurerm_resource_group.rg", "{$.location}"), // want `ExpectedRetriever and ActualRetriever are swapped: ActualRetriever reads the plan`
+		},
+		"vmExistsTest": {
+			Expected:       nil,
+			Actual:         vms,
+			AssertFunction: assert.NotEqual,
+			Message:        "VM does not exist",
+		},
+	})
+}
@@ -0,0 +1,17 @@
+resource "azurerm_resource_group" "rg" {
+  name     = "rg"
+  location = "eastus"
+}
+
+data "azurerm_subnet" "aks" {
+  name = "aks"
+}
+
+module "jump" {
+  source = "./modules/vm"
+  count  = 1
+}
+
+module "registry" {
+  source = "example/registry/azurerm"
+}
@@ -0,0 +1,9 @@
+resource "azurerm_linux_virtual_machine" "vm" {
+  name = "vm"
+  size = "Standard_B2ls_v2"
+}
+
+resource "azurerm_subnet" "subnet" {
+  for_each = toset(["aks", "misc"])
+  name     = each.key
+}
@@ -0,0 +1,9 @@
+package assert
+
+type TestingT interface{}
+
+type ComparisonAssertionFunc func(TestingT, interface{}, interface{}, ...interface{}) bool
+
+func Equal(t TestingT, expected, actual interface{}, msgAndArgs ...interface{}) bool { return true }
+
+func NotEqual(t TestingT, expected, actual interface{}, msgAndArgs ...interface{}) bool { return true }
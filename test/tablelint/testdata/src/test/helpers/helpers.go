@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Plan struct{}
+
+type Retriever func(plan *Plan, resourceMapName string, jsonPath string) (string, error)
+
+type TestCase struct {
+	Expected          interface{}
+	Retriever         Retriever
+	ResourceMapName   string
+	AttributeJsonPath string
+	AssertFunction    assert.ComparisonAssertionFunc
+	Message           string
+	ExpectAbsent      bool
+}
+
+type TupleTestCase struct {
+	Expected map[string]AttrTuple
+}
+
+type AttrTuple struct {
+	ExpectedValue string
+	JsonPath      string
+}
+
+type ApplyTestCase struct {
+	Expected          interface{}
+	ExpectedRetriever func() string
+	Actual            interface{}
+	ActualRetriever   func() string
+	AssertFunction    assert.ComparisonAssertionFunc
+	Message           string
+}
+
+func RetrieveFromRawPlan(plan *Plan, outputName string, jsonPath string) (string, error) {
+	return "", nil
+}
+
+func RetrieveFromResourcePlannedValuesMap(plan *Plan, resourceMapName string, jsonPath string) (string, error) {
+	return "", nil
+}
+
+func HashedRetriever(retriever Retriever) Retriever { return retriever }
+
+func RetrieveFromPlan(plan *Plan, resourceMapName string, jsonPath string) func() string { return nil }
+
+func RetrieveFromStruct(input interface{}, fieldNames ...string) func() string { return nil }
+
+func RunDefaultPlanTests(t *testing.T, tests map[string]TestCase) {}
+
+func RunDefaultPlanTupleTests(t *testing.T, resourceMapNameFmt string, tests map[string]TupleTestCase) {
+}
+
+func RunApplyTests(t *testing.T, tests map[string]ApplyTestCase) {}
@@ -0,0 +1,359 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tfconfig reads the blocks of the terraform modules of the repository from their sources, so the tests
+// can be checked against them without running terraform.
+package tfconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Module holds the resource, data, module, variable and output blocks of a terraform module directory.
+type Module struct {
+	Dir string
+	// Resources and DataSources hold the type.name of each block
+	Resources   map[string]hcl.Range
+	DataSources map[string]hcl.Range
+	ModuleCalls map[string]*ModuleCall
+	Variables   map[string]*Variable
+	Outputs     map[string]hcl.Range
+	// References holds the references of the expressions of the module, e.g. var.prefix or module.aks.name
+	References []hcl.Traversal
+}
+
+// ModuleCall is a module block. Dir is the directory of a local source, and is empty for other sources.
+type ModuleCall struct {
+	Name   string
+	Source string
+	Dir    string
+	// Inputs holds the arguments of the block that set variables of the module
+	Inputs map[string]*hcl.Attribute
+	Range  hcl.Range
+}
+
+// Variable is a variable block. Default is a null value if the variable has no default.
+type Variable struct {
+	Name        string
+	Description string
+	// Type is the source of the type constraint, empty if there is none, and Constraint the type it declares
+	Type        string
+	Constraint  cty.Type
+	Default     cty.Value
+	HasDefault  bool
+	Nullable    *bool
+	Validations int
+	Range       hcl.Range
+}
+
+var fileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+		{Type: "data", LabelNames: []string{"type", "name"}},
+		{Type: "module", LabelNames: []string{"name"}},
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "output", LabelNames: []string{"name"}},
+	},
+}
+
+var variableSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "description"}, {Name: "type"}, {Name: "default"}, {Name: "nullable"}},
+	Blocks:     []hcl.BlockHeaderSchema{{Type: "validation"}},
+}
+
+var moduleCallSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "source", Required: true}, {Name: "version"}, {Name: "count"}, {Name: "for_each"},
+		{Name: "depends_on"}, {Name: "providers"},
+	},
+}
+
+// LoadModule parses the .tf files of the directory.
+func LoadModule(dir string) (*Module, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s has no .tf files", dir)
+	}
+	module := &Module{
+		Dir:         dir,
+		Resources:   make(map[string]hcl.Range),
+		DataSources: make(map[string]hcl.Range),
+		ModuleCalls: make(map[string]*ModuleCall),
+		Variables:   make(map[string]*Variable),
+		Outputs:     make(map[string]hcl.Range),
+	}
+	parser := hclparse.NewParser()
+	for _, path := range files {
+		file, diags := parser.ParseHCLFile(path)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		content, _, diags := file.Body.PartialContent(fileSchema)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if body, ok := file.Body.(*hclsyntax.Body); ok {
+			module.References = append(module.References, references(body)...)
+		}
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case "resource":
+				module.Resources[block.Labels[0]+"."+block.Labels[1]] = block.DefRange
+			case "data":
+				module.DataSources[block.Labels[0]+"."+block.Labels[1]] = block.DefRange
+			case "module":
+				call, err := moduleCall(dir, block)
+				if err != nil {
+					return nil, err
+				}
+				module.ModuleCalls[call.Name] = call
+			case "variable":
+				variable, err := variable(file.Bytes, block)
+				if err != nil {
+					return nil, err
+				}
+				module.Variables[variable.Name] = variable
+			case "output":
+				module.Outputs[block.Labels[0]] = block.DefRange
+			}
+		}
+	}
+	return module, nil
+}
+
+func moduleCall(dir string, block *hcl.Block) (*ModuleCall, error) {
+	content, remain, diags := block.Body.PartialContent(moduleCallSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	source, diags := content.Attributes["source"].Expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	inputs, diags := remain.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	call := &ModuleCall{Name: block.Labels[0], Source: source.AsString(), Inputs: inputs, Range: block.DefRange}
+	if strings.HasPrefix(call.Source, "./") || strings.HasPrefix(call.Source, "../") {
+		call.Dir = filepath.Join(dir, call.Source)
+	}
+	return call, nil
+}
+
+func variable(source []byte, block *hcl.Block) (*Variable, error) {
+	content, _, diags := block.Body.PartialContent(variableSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	variable := &Variable{
+		Name:       block.Labels[0],
+		Constraint: cty.DynamicPseudoType,
+		Default:    cty.NullVal(cty.DynamicPseudoType),
+		Range:      block.DefRange,
+	}
+	if attribute, ok := content.Attributes["description"]; ok {
+		description, diags := attribute.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if description.Type() == cty.String && description.IsKnown() && !description.IsNull() {
+			variable.Description = description.AsString()
+		}
+	}
+	if attribute, ok := content.Attributes["type"]; ok {
+		variable.Type = string(attribute.Expr.Range().SliceBytes(source))
+		constraint, _, diags := typeexpr.TypeConstraintWithDefaults(attribute.Expr)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		variable.Constraint = constraint
+	}
+	if attribute, ok := content.Attributes["default"]; ok {
+		value, diags := attribute.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		variable.Default, variable.HasDefault = value, true
+	}
+	if attribute, ok := content.Attributes["nullable"]; ok {
+		value, diags := attribute.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if value.Type() == cty.Bool && value.IsKnown() && !value.IsNull() {
+			nullable := value.True()
+			variable.Nullable = &nullable
+		}
+	}
+	for _, nested := range content.Blocks {
+		if nested.Type == "validation" {
+			variable.Validations++
+		}
+	}
+	return variable, nil
+}
+
+// references returns the references of the expressions of a file. A reference to a module as a whole, such as
+// the source of module.nfs[*].private_ip_address, ends at the module name.
+func references(body *hclsyntax.Body) []hcl.Traversal {
+	var traversals []hcl.Traversal
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		if expr, ok := node.(*hclsyntax.ScopeTraversalExpr); ok {
+			traversals = append(traversals, expr.Traversal)
+		}
+		return nil
+	})
+	return traversals
+}
+
+// Config is a root module with the local modules it calls, loaded once each.
+type Config struct {
+	Root    *Module
+	modules map[string]*Module
+}
+
+// LoadConfig loads the root module in dir.
+func LoadConfig(dir string) (*Config, error) {
+	root, err := LoadModule(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Root: root, modules: map[string]*Module{filepath.Clean(dir): root}}, nil
+}
+
+// Module returns the module that a module call loads, or nil if its source is not a local directory.
+func (c *Config) Module(call *ModuleCall) (*Module, error) {
+	if call.Dir == "" {
+		return nil, nil
+	}
+	dir := filepath.Clean(call.Dir)
+	if module, ok := c.modules[dir]; ok {
+		return module, nil
+	}
+	module, err := LoadModule(dir)
+	if err != nil {
+		return nil, err
+	}
+	c.modules[dir] = module
+	return module, nil
+}
+
+// Resolve returns an error if a resource address, such as module.nfs[0].azurerm_managed_disk.vm_data_disk[0] or
+// module.aks, does not match the resource, data and module blocks of the configuration. The parts of the
+// address in modules whose source is not a local directory are not checked.
+func (c *Config) Resolve(address string) error {
+	return c.resolve(address, SplitAddress(address), c.Root, "the root module")
+}
+
+// ResolveModulePlan is Resolve for the addresses of a plan of a single module of the modules directory, which
+// is called by its directory name, e.g. module.aks_node_pool.azurerm_kubernetes_cluster_node_pool.static_node_pool[0].
+func (c *Config) ResolveModulePlan(address string) error {
+	parts := SplitAddress(address)
+	if len(parts) < 2 || stripIndex(parts[0]) != "module" {
+		return fmt.Errorf("%s is not an address of a module of the modules directory", address)
+	}
+	name := stripIndex(parts[1])
+	module, err := c.Module(&ModuleCall{Name: name, Dir: filepath.Join(c.Root.Dir, "modules", name)})
+	if err != nil {
+		return fmt.Errorf("the modules directory has no module %s", name)
+	}
+	return c.resolve(address, parts[2:], module, "module "+name)
+}
+
+func (c *Config) resolve(address string, parts []string, module *Module, path string) error {
+	for i := 0; i < len(parts); i++ {
+		name := stripIndex(parts[i])
+		switch {
+		case name == "module" && i+1 < len(parts):
+			callName := stripIndex(parts[i+1])
+			call, ok := module.ModuleCalls[callName]
+			if !ok {
+				return fmt.Errorf("%s has no module %s, available: %s", path, callName, callNames(module.ModuleCalls))
+			}
+			next, err := c.Module(call)
+			if err != nil || next == nil {
+				return err
+			}
+			module, path = next, "module "+callName
+			i++
+		case name == "data" && i+2 < len(parts):
+			dataSource := parts[i+1] + "." + stripIndex(parts[i+2])
+			if _, ok := module.DataSources[dataSource]; !ok {
+				return fmt.Errorf("%s has no data source %s, available: %s", path, dataSource, keys(module.DataSources))
+			}
+			return rest(address, parts[i+3:])
+		case i+1 < len(parts):
+			resource := name + "." + stripIndex(parts[i+1])
+			if _, ok := module.Resources[resource]; !ok {
+				return fmt.Errorf("%s has no resource %s, available: %s", path, resource, keys(module.Resources))
+			}
+			return rest(address, parts[i+2:])
+		default:
+			return fmt.Errorf("%s is not a resource address", address)
+		}
+	}
+	return nil
+}
+
+func rest(address string, parts []string) error {
+	if len(parts) > 0 {
+		return fmt.Errorf("%s is not a resource address, %s follows the resource", address, strings.Join(parts, "."))
+	}
+	return nil
+}
+
+// SplitAddress splits an address on the dots that are not in an index.
+func SplitAddress(address string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, char := range address {
+		switch char {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, address[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, address[start:])
+}
+
+func stripIndex(part string) string {
+	name, _, _ := strings.Cut(part, "[")
+	return name
+}
+
+func keys(blocks map[string]hcl.Range) string {
+	names := make([]string, 0, len(blocks))
+	for name := range blocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func callNames(calls map[string]*ModuleCall) string {
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
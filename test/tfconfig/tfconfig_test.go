@@ -0,0 +1,62 @@
+// Copyright © 2025, SAS Institute Inc., Cary, NC, USA. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tfconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolve verifies that addresses are resolved in the modules of the repository.
+func TestResolve(t *testing.T) {
+	config, err := LoadConfig("../..")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		address string
+		err     string
+	}{
+		"rootResource":   {"azurerm_resource_group.aks_rg[0]", ""},
+		"moduleResource": {`module.vnet.azurerm_subnet.subnet["aks"]`, ""},
+		"indexedModule":  {`module.node_pools["cas"].azurerm_kubernetes_cluster_node_pool.autoscale_node_pool[0]`, ""},
+		"module":         {"module.aks", ""},
+		"dataSource":     {"data.azurerm_resource_group.network_rg[0]", ""},
+		"missingModule": {"module.vm.azurerm_linux_virtual_machine.vm",
+			"the root module has no module vm, available: aks, flex_postgresql, jump, kubeconfig, netapp, nfs, node_pools, vnet"},
+		"missingResource": {"module.jump[0].azurerm_linux_virtual_machine.jump", "module jump has no resource azurerm_linux_virtual_machine.jump"},
+		"notAnAddress":    {"location", "location is not a resource address"},
+		"attribute":       {"azurerm_resource_group.aks_rg[0].name", "azurerm_resource_group.aks_rg[0].name is not a resource address, name follows the resource"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := config.Resolve(tc.address)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+// TestResolveModulePlan verifies that the addresses of module plans are resolved in the modules directory.
+func TestResolveModulePlan(t *testing.T) {
+	config, err := LoadConfig("../..")
+	require.NoError(t, err)
+
+	assert.NoError(t, config.ResolveModulePlan("module.aks_node_pool.azurerm_kubernetes_cluster_node_pool.static_node_pool[0]"))
+	assert.NoError(t, config.ResolveModulePlan("module.azurerm_vm.azurerm_managed_disk.vm_data_disk[1]"))
+	assert.EqualError(t, config.ResolveModulePlan("module.azurerm_vm.azurerm_managed_disk.data_disk[0]"),
+		"module azurerm_vm has no resource azurerm_managed_disk.data_disk, available: azurerm_linux_virtual_machine.vm, "+
+			"azurerm_managed_disk.vm_data_disk, azurerm_network_interface.vm_nic, "+
+			"azurerm_network_interface_security_group_association.vm_nic_sg, azurerm_public_ip.vm_ip, "+
+			"azurerm_virtual_machine_data_disk_attachment.vm_data_disk_attach")
+	assert.EqualError(t, config.ResolveModulePlan("module.vm.azurerm_linux_virtual_machine.vm"), "the modules directory has no module vm")
+	assert.EqualError(t, config.ResolveModulePlan("azurerm_resource_group.aks_rg[0]"),
+		"azurerm_resource_group.aks_rg[0] is not an address of a module of the modules directory")
+}